@@ -12,18 +12,41 @@ func LoadAppConfig(path string) *koanf.Koanf {
 	k := koanf.New(".")
 
 	k.Load(confmap.Provider(map[string]interface{}{
-		"host":             "127.0.0.1",
-		"port":             8000,
-		"database.server":  "localhost",
-		"database.port":    "27017",
-		"database.name":    "f-taxes",
-		"log.path":         "./logs/app_%Y_%m_%d__%H_%M.log",
-		"log.write":        false,
-		"plugins.path":     "./plugins",
-		"plugins.registry": "https://github.com/f-taxes/plugins/raw/main/list.json",
-		"grpc.host":        "127.0.0.1",
-		"grpc.port":        4222,
-		"snapshots.path":   "./snapshots",
+		"host":                            "127.0.0.1",
+		"port":                            8000,
+		"database.server":                 "localhost",
+		"database.port":                   "27017",
+		"database.name":                   "f-taxes",
+		"log.path":                        "./logs/app_%Y_%m_%d__%H_%M.log",
+		"log.write":                       false,
+		"plugins.path":                    "./plugins",
+		"plugins.registry":                "https://github.com/f-taxes/plugins/raw/main/list.json",
+		"plugins.trustedKeys":             []string{},
+		"plugins.requireChecksum":         false,
+		"plugins.requireSignature":        false,
+		"plugins.secretKeyFile":           "./secret.key",
+		"plugins.registryCache":           "./registry-cache",
+		"plugins.registryTTL":             3600,
+		"plugins.offline":                 false,
+		"plugins.sideloadMaxSize":         200,
+		"plugins.sandbox":                 false,
+		"plugins.sandboxEnv":              []string{},
+		"plugins.cgroup":                  "",
+		"grpc.host":                       "127.0.0.1",
+		"grpc.port":                       4222,
+		"grpc.requireAuth":                true,
+		"snapshots.path":                  "./snapshots",
+		"snapshots.schedule":              "",
+		"snapshots.beforeDestructive":     true,
+		"snapshots.passphrase":            "",
+		"snapshots.retention.keepLast":    10,
+		"snapshots.retention.keepDaily":   7,
+		"snapshots.retention.keepWeekly":  4,
+		"snapshots.retention.keepMonthly": 12,
+		"trash.purgeAfterDays":            30,
+		"reports.path":                    "./reports",
+		"reports.maxFileSize":             100,
+		"reports.maxRunSize":              500,
 	}, "."), nil)
 
 	f := file.Provider(path)
@@ -0,0 +1,143 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	g "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/kataras/golog"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Type string
+
+const (
+	RECORD_CREATED      = Type("record-created")
+	RECORD_UPDATED      = Type("record-updated")
+	RECORD_DELETED      = Type("record-deleted")
+	SETTINGS_CHANGED    = Type("settings-changed")
+	IMPORT_FINISHED     = Type("import-finished")     // A plugin finished a job during which it submitted records.
+	CONVERSION_FINISHED = Type("conversion-finished") // A job converting prices of records finished.
+)
+
+// Number of events buffered per subscriber. Subscribers that fall further behind are dropped.
+const bufferSize = 1000
+
+// Maximum number of record ids in a single event. Changes to more records are split into several events,
+// so emptying the trash doesn't produce a message larger than grpc allows.
+const MAX_RECORD_IDS = 1000
+
+type Event struct {
+	Type       Type                 `json:"type"`
+	Ts         time.Time            `json:"ts"`
+	Collection string               `json:"collection,omitempty"`
+	RecordIDs  []primitive.ObjectID `json:"recordIds,omitempty"`
+	Plugin     string               `json:"plugin,omitempty"` // Plugin that caused the event, e.g. the one that imported or converted records.
+	JobID      string               `json:"jobId,omitempty"`
+	Count      int64                `json:"count,omitempty"` // Number of records affected by an import or conversion.
+}
+
+// Selects the events a subscriber is interested in. Empty lists match everything.
+type Filter struct {
+	Types       []Type
+	Collections []string
+}
+
+func (f Filter) Matches(e Event) bool {
+	if len(f.Types) > 0 && !g.ContainsAny(f.Types, e.Type) {
+		return false
+	}
+
+	if len(f.Collections) > 0 && e.Collection != "" && !g.ContainsAny(f.Collections, e.Collection) {
+		return false
+	}
+
+	return true
+}
+
+type Subscription struct {
+	C      <-chan Event
+	c      chan Event
+	filter Filter
+	done   bool
+}
+
+// True if the subscription was dropped because its events weren't consumed fast enough.
+func (s *Subscription) Overflowed() bool {
+	lock.Lock()
+	defer lock.Unlock()
+	return s.done
+}
+
+var lock sync.Mutex
+var subscriptions = map[*Subscription]struct{}{}
+
+func Subscribe(filter Filter) *Subscription {
+	c := make(chan Event, bufferSize)
+	s := &Subscription{C: c, c: c, filter: filter}
+
+	lock.Lock()
+	subscriptions[s] = struct{}{}
+	lock.Unlock()
+
+	return s
+}
+
+func Unsubscribe(s *Subscription) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, ok := subscriptions[s]; ok {
+		delete(subscriptions, s)
+		close(s.c)
+	}
+}
+
+// Delivers the event to all matching subscribers without blocking.
+func Publish(e Event) {
+	if e.Ts.IsZero() {
+		e.Ts = time.Now().UTC()
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	for s := range subscriptions {
+		if !s.filter.Matches(e) {
+			continue
+		}
+
+		select {
+		case s.c <- e:
+		default:
+			golog.Warnf("Dropping event subscriber that fell behind by more than %d events", bufferSize)
+			s.done = true
+			delete(subscriptions, s)
+			close(s.c)
+		}
+	}
+}
+
+// Publishes record events for the given records, with at most MAX_RECORD_IDS records per event. Nothing is published if there are no records.
+func RecordsChanged(t Type, collection string, ids ...primitive.ObjectID) {
+	PluginRecordsChanged(t, collection, "", ids...)
+}
+
+// Like RecordsChanged, but attributes the events to the plugin that caused them.
+func PluginRecordsChanged(t Type, collection, plugin string, ids ...primitive.ObjectID) {
+	ts := time.Now().UTC()
+
+	for len(ids) > 0 {
+		n := min(len(ids), MAX_RECORD_IDS)
+
+		Publish(Event{
+			Type:       t,
+			Ts:         ts,
+			Collection: collection,
+			RecordIDs:  ids[:n],
+			Plugin:     plugin,
+		})
+
+		ids = ids[n:]
+	}
+}
@@ -0,0 +1,90 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/f-taxes/f-taxes/backend/plugin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata keys plugins use to authenticate each call.
+const MD_PLUGIN_ID = "x-plugin-id"
+const MD_PLUGIN_TOKEN = "x-plugin-token"
+
+type pluginIDKey struct{}
+
+// Verifies the plugin id and token sent as metadata and stores the authenticated plugin id in the context.
+// The interceptors also check the plugin's permissions for the called method, see methodPermissions.
+func authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	id := firstValue(md, MD_PLUGIN_ID)
+
+	if id == "" || !plugin.Manager.Authenticate(id, firstValue(md, MD_PLUGIN_TOKEN)) {
+		return ctx, status.Error(codes.Unauthenticated, "invalid or missing plugin credentials")
+	}
+
+	return context.WithValue(ctx, pluginIDKey{}, id), nil
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// Returns the id of the plugin that made the call. Empty if authentication is disabled.
+func pluginFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(pluginIDKey{}).(string)
+	return id
+}
+
+// Makes sure a plugin only acts on its own behalf, e.g. doesn't submit records in the name of another plugin.
+func checkIdentity(ctx context.Context, claimed string) error {
+	id := pluginFromContext(ctx)
+
+	if id != "" && id != claimed {
+		return status.Errorf(codes.PermissionDenied, "plugin %s can't act on behalf of plugin %s", id, claimed)
+	}
+
+	return nil
+}
+
+func unaryAuthInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorize(pluginFromContext(ctx), info.FullMethod); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+func streamAuthInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+
+	if err := authorize(pluginFromContext(ctx), info.FullMethod); err != nil {
+		return err
+	}
+
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+}
@@ -0,0 +1,187 @@
+package gapi
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/f-taxes/f-taxes/backend/events"
+	g "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/f-taxes/f-taxes/backend/plugin"
+	pb "github.com/f-taxes/f-taxes/proto"
+	"github.com/kataras/golog"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var eventTypes = map[events.Type]pb.EventType{
+	events.RECORD_CREATED:      pb.EventType_RECORD_CREATED,
+	events.RECORD_UPDATED:      pb.EventType_RECORD_UPDATED,
+	events.RECORD_DELETED:      pb.EventType_RECORD_DELETED,
+	events.SETTINGS_CHANGED:    pb.EventType_SETTINGS_CHANGED,
+	events.IMPORT_FINISHED:     pb.EventType_IMPORT_FINISHED,
+	events.CONVERSION_FINISHED: pb.EventType_CONVERSION_FINISHED,
+}
+
+// Permission a plugin needs to receive each type of event.
+var eventPermissions = map[events.Type]plugin.Permission{
+	events.RECORD_CREATED:      plugin.PERM_RECORDS_READ,
+	events.RECORD_UPDATED:      plugin.PERM_RECORDS_READ,
+	events.RECORD_DELETED:      plugin.PERM_RECORDS_READ,
+	events.SETTINGS_CHANGED:    plugin.PERM_SETTINGS_READ,
+	events.IMPORT_FINISHED:     plugin.PERM_RECORDS_READ,
+	events.CONVERSION_FINISHED: plugin.PERM_RECORDS_READ,
+}
+
+func (s *GapiServer) Subscribe(filter *pb.EventFilter, stream pb.FTaxes_SubscribeServer) error {
+	if err := checkIdentity(stream.Context(), filter.Plugin); err != nil {
+		return err
+	}
+
+	f := events.Filter{Collections: filter.Collections}
+	requested := map[pb.EventType]bool{}
+	for _, t := range filter.Types {
+		requested[t] = true
+	}
+
+	for t, pbType := range eventTypes {
+		if len(requested) > 0 && !requested[pbType] {
+			continue
+		}
+
+		// Without authentication the permissions of the plugin the caller claims to be apply.
+		if !plugin.Manager.HasPermission(filter.Plugin, eventPermissions[t]) {
+			continue
+		}
+
+		f.Types = append(f.Types, t)
+	}
+
+	if len(f.Types) == 0 {
+		return status.Errorf(codes.PermissionDenied, "plugin %s isn't allowed to receive any of the requested events", filter.Plugin)
+	}
+
+	sub := events.Subscribe(f)
+	defer events.Unsubscribe(sub)
+
+	golog.Infof("Plugin %s subscribed to events %v", filter.Plugin, f.Types)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e, ok := <-sub.C:
+			if !ok {
+				if sub.Overflowed() {
+					return status.Error(codes.ResourceExhausted, "too many events weren't received in time")
+				}
+				return nil
+			}
+
+			if err := stream.Send(eventToProto(e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func eventToProto(e events.Event) *pb.Event {
+	ids := make([]string, len(e.RecordIDs))
+	for i := range e.RecordIDs {
+		ids[i] = e.RecordIDs[i].Hex()
+	}
+
+	return &pb.Event{
+		Type:       eventTypes[e.Type],
+		Ts:         timestamppb.New(e.Ts),
+		Collection: e.Collection,
+		RecordIDs:  ids,
+		Plugin:     e.Plugin,
+		JobID:      e.JobID,
+		Count:      e.Count,
+	}
+}
+
+// Time records submitted by a plugin are collected before a RECORD_CREATED event is published for them,
+// unless the plugin finishes its job or submits MAX_RECORD_IDS records earlier.
+const createdEventDelay = time.Second
+
+// Counts the records each plugin submitted since its last job finished.
+// Plugins report the end of an import by sending a job progress of 100.
+// The ids of submitted records are published in batches, as one event per record quickly overflows subscribers during imports.
+type importTracker struct {
+	sync.Mutex
+	counts  map[string]map[string]int64
+	created map[string]map[string][]primitive.ObjectID // Records whose RECORD_CREATED event is pending, by plugin and collection.
+}
+
+var imports = importTracker{counts: map[string]map[string]int64{}, created: map[string]map[string][]primitive.ObjectID{}}
+
+func (t *importTracker) submitted(pluginID, collection string, id primitive.ObjectID) {
+	t.Lock()
+
+	if t.counts[pluginID] == nil {
+		t.counts[pluginID] = map[string]int64{}
+	}
+
+	t.counts[pluginID][collection]++
+
+	if t.created[pluginID] == nil {
+		t.created[pluginID] = map[string][]primitive.ObjectID{}
+		time.AfterFunc(createdEventDelay, func() {
+			t.publishCreated(pluginID)
+		})
+	}
+
+	t.created[pluginID][collection] = append(t.created[pluginID][collection], id)
+	full := len(t.created[pluginID][collection]) >= events.MAX_RECORD_IDS
+	t.Unlock()
+
+	if full {
+		t.publishCreated(pluginID)
+	}
+}
+
+// Publishes the pending RECORD_CREATED events of a plugin.
+func (t *importTracker) publishCreated(pluginID string) {
+	t.Lock()
+	created := t.created[pluginID]
+	delete(t.created, pluginID)
+	t.Unlock()
+
+	for _, col := range []string{g.COL_TRADES, g.COL_TRANSFERS} {
+		events.PluginRecordsChanged(events.RECORD_CREATED, col, pluginID, created[col]...)
+	}
+}
+
+func (t *importTracker) progress(job *pb.JobProgress) {
+	p, err := strconv.ParseFloat(strings.TrimSpace(job.Progress), 64)
+	if err != nil || p < 100 {
+		return
+	}
+
+	// Subscribers learn about all records of the import before it is reported as finished.
+	t.publishCreated(job.Plugin)
+
+	t.Lock()
+	counts := t.counts[job.Plugin]
+	delete(t.counts, job.Plugin)
+	t.Unlock()
+
+	for _, col := range []string{g.COL_TRADES, g.COL_TRANSFERS} {
+		if counts[col] == 0 {
+			continue
+		}
+
+		events.Publish(events.Event{
+			Type:       events.IMPORT_FINISHED,
+			Collection: col,
+			Plugin:     job.Plugin,
+			JobID:      job.ID,
+			Count:      counts[col],
+		})
+	}
+}
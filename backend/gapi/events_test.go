@@ -0,0 +1,45 @@
+package gapi
+
+import (
+	"testing"
+
+	"github.com/f-taxes/f-taxes/backend/events"
+	g "github.com/f-taxes/f-taxes/backend/global"
+	pb "github.com/f-taxes/f-taxes/proto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestImportPublishesCreatedRecordsInBatches(t *testing.T) {
+	sub := events.Subscribe(events.Filter{})
+	defer events.Unsubscribe(sub)
+
+	// More records than a subscriber buffers events.
+	n := events.MAX_RECORD_IDS*2 + 5
+	for i := 0; i < n; i++ {
+		imports.submitted("test_import", g.COL_TRADES, primitive.NewObjectID())
+	}
+
+	imports.progress(&pb.JobProgress{ID: "job", Plugin: "test_import", Progress: "100"})
+
+	created := 0
+	for e := range sub.C {
+		switch e.Type {
+		case events.RECORD_CREATED:
+			if e.Plugin != "test_import" || e.Collection != g.COL_TRADES {
+				t.Fatalf("unexpected event %+v", e)
+			}
+			created += len(e.RecordIDs)
+		case events.IMPORT_FINISHED:
+			if created != n || e.Count != int64(n) {
+				t.Fatalf("import finished after %d of %d created records with count %d", created, n, e.Count)
+			}
+
+			if sub.Overflowed() {
+				t.Fatal("subscriber was dropped")
+			}
+			return
+		}
+	}
+
+	t.Fatal("subscriber was dropped")
+}
@@ -0,0 +1,42 @@
+package gapi
+
+import (
+	"github.com/f-taxes/f-taxes/backend/plugin"
+	pb "github.com/f-taxes/f-taxes/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Permission a plugin needs to call each method. An empty permission means every authenticated plugin may call it.
+// Methods missing here can't be called by any plugin.
+var methodPermissions = map[string]plugin.Permission{
+	pb.FTaxes_SubmitTrade_FullMethodName:      plugin.PERM_RECORDS_WRITE,
+	pb.FTaxes_SubmitTransfer_FullMethodName:   plugin.PERM_RECORDS_WRITE,
+	pb.FTaxes_UpdateTrade_FullMethodName:      plugin.PERM_RECORDS_UPDATE,
+	pb.FTaxes_UpdateTransfer_FullMethodName:   plugin.PERM_RECORDS_UPDATE,
+	pb.FTaxes_SubmitGenericFee_FullMethodName: plugin.PERM_RECORDS_WRITE,
+	pb.FTaxes_StreamRecords_FullMethodName:    plugin.PERM_RECORDS_READ,
+	pb.FTaxes_QueryRecords_FullMethodName:     plugin.PERM_RECORDS_READ,
+	pb.FTaxes_GetSettings_FullMethodName:      plugin.PERM_SETTINGS_READ,
+	pb.FTaxes_AppLog_FullMethodName:           plugin.PERM_APPLOG_WRITE,
+	pb.FTaxes_ShowJobProgress_FullMethodName:  plugin.PERM_JOBS_REPORT,
+	pb.FTaxes_PluginHeartbeat_FullMethodName:  "",
+	pb.FTaxes_GetPluginConfig_FullMethodName:  "", // Plugins can only access their own configuration.
+	pb.FTaxes_SetPluginConfig_FullMethodName:  "",
+	pb.FTaxes_Subscribe_FullMethodName:        "", // Events are filtered by the permissions of the plugin.
+}
+
+// Checks whether the plugin was granted the permission required to call the method.
+func authorize(pluginID, fullMethod string) error {
+	permission, ok := methodPermissions[fullMethod]
+
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "method %s isn't available to plugins", fullMethod)
+	}
+
+	if permission != "" && !plugin.Manager.HasPermission(pluginID, permission) {
+		return status.Errorf(codes.PermissionDenied, "plugin %s lacks the permission %s", pluginID, permission)
+	}
+
+	return nil
+}
@@ -0,0 +1,250 @@
+package gapi
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	g "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/f-taxes/f-taxes/backend/trades"
+	"github.com/f-taxes/f-taxes/backend/transfers"
+	pb "github.com/f-taxes/f-taxes/proto"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Records of all collections are streamed ordered by timestamp, then by collection in this order, then by id.
+var streamCollections = []string{g.COL_TRADES, g.COL_TRANSFERS}
+
+// Position of a record within a stream of records.
+type resumeToken struct {
+	Ts         time.Time          `json:"ts"`
+	Collection string             `json:"col"`
+	ID         primitive.ObjectID `json:"id"`
+}
+
+func (t resumeToken) String() string {
+	b, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func parseResumeToken(s string) (*resumeToken, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "malformed resume token")
+	}
+
+	t := resumeToken{}
+	if err := json.Unmarshal(b, &t); err != nil || collectionRank(t.Collection) == -1 {
+		return nil, status.Error(codes.InvalidArgument, "malformed resume token")
+	}
+
+	return &t, nil
+}
+
+func collectionRank(collection string) int {
+	for i := range streamCollections {
+		if streamCollections[i] == collection {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Returns the collections the job asks for.
+func jobCollections(job *pb.StreamRecordsJob) ([]string, error) {
+	if len(job.Collections) == 0 {
+		return streamCollections, nil
+	}
+
+	for _, c := range job.Collections {
+		if collectionRank(c) == -1 {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown record type '%s'", c)
+		}
+	}
+
+	return job.Collections, nil
+}
+
+// Builds the query selecting the records of a collection the job asks for, starting after the resume token.
+func jobFilter(job *pb.StreamRecordsJob, collection string, token *resumeToken) (bson.M, error) {
+	and := []bson.M{}
+
+	if job.From != nil {
+		and = append(and, bson.M{"ts": bson.M{"$gte": primitive.NewDateTimeFromTime(job.From.AsTime())}})
+	}
+
+	if job.To != nil {
+		and = append(and, bson.M{"ts": bson.M{"$lte": primitive.NewDateTimeFromTime(job.To.AsTime())}})
+	}
+
+	if len(job.Accounts) > 0 {
+		and = append(and, bson.M{"account": bson.M{"$in": job.Accounts}})
+	}
+
+	if len(job.Assets) > 0 {
+		and = append(and, bson.M{"asset": bson.M{"$in": job.Assets}})
+	}
+
+	if len(job.Plugins) > 0 {
+		and = append(and, bson.M{"plugin": bson.M{"$in": job.Plugins}})
+	}
+
+	f, err := parseFilter(job.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(f) > 0 {
+		and = append(and, f)
+	}
+
+	if token != nil {
+		and = append(and, afterToken(collection, *token))
+	}
+
+	if len(and) == 0 {
+		return g.NotDeleted(bson.M{}), nil
+	}
+
+	return g.NotDeleted(bson.M{"$and": and}), nil
+}
+
+// Builds a mongodb filter from a JSON encoded filter tree as sent by the UI.
+func parseFilter(s string) (bson.M, error) {
+	if s == "" {
+		return bson.M{}, nil
+	}
+
+	tree := [][]g.Filter{}
+	if err := json.Unmarshal([]byte(s), &tree); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "malformed filter: %v", err)
+	}
+
+	f, err := g.BuildFilter(tree)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid filter: %v", err)
+	}
+
+	return f, nil
+}
+
+// Selects the records of a collection that come after the token in the stream's order.
+func afterToken(collection string, token resumeToken) bson.M {
+	ts := primitive.NewDateTimeFromTime(token.Ts)
+	rank := collectionRank(collection)
+	tokenRank := collectionRank(token.Collection)
+
+	switch {
+	case rank > tokenRank:
+		return bson.M{"ts": bson.M{"$gte": ts}}
+	case rank < tokenRank:
+		return bson.M{"ts": bson.M{"$gt": ts}}
+	}
+
+	return bson.M{"$or": bson.A{
+		bson.M{"ts": bson.M{"$gt": ts}},
+		bson.M{"ts": ts, "_id": bson.M{"$gt": token.ID}},
+	}}
+}
+
+func tradeRecord(t g.Trade) *pb.Record {
+	return &pb.Record{
+		Trade:       g.TradeToProtoTrade(t),
+		ResumeToken: resumeToken{Ts: t.Ts, Collection: g.COL_TRADES, ID: t.ID}.String(),
+	}
+}
+
+func transferRecord(t g.Transfer) *pb.Record {
+	return &pb.Record{
+		Transfer:    g.TransferToProtoTransfer(t),
+		ResumeToken: resumeToken{Ts: t.Ts, Collection: g.COL_TRANSFERS, ID: t.ID}.String(),
+	}
+}
+
+func (s *GapiServer) QueryRecords(ctx context.Context, req *pb.QueryRequest) (*pb.QueryResponse, error) {
+	if err := checkIdentity(ctx, req.Plugin); err != nil {
+		return nil, err
+	}
+
+	filter, err := parseFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	q := g.Query{
+		Page:              max(req.Page, 1),
+		Limit:             req.Limit,
+		Sort:              req.Sort,
+		ConstructedFilter: filter,
+	}
+
+	if q.Limit <= 0 {
+		q.Limit = 100
+	}
+
+	q.Limit = min(q.Limit, 1000)
+	// Pages beyond the last one are empty anyway, this only keeps the offset from overflowing.
+	q.Page = min(q.Page, math.MaxInt64/q.Limit)
+
+	if q.Sort == "" {
+		q.Sort = "ts"
+	}
+
+	out := &pb.QueryResponse{Records: []*pb.Record{}}
+
+	switch req.Collection {
+	case g.COL_TRADES:
+		res, err := trades.Paginate(q)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range res.Items {
+			out.Records = append(out.Records, &pb.Record{Trade: g.TradeToProtoTrade(t)})
+		}
+
+		out.TotalCount, out.FilteredCount, out.TotalPages = res.TotalCount, res.FilteredCount, res.TotalPages
+	case g.COL_TRANSFERS:
+		res, err := transfers.Paginate(q)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range res.Items {
+			out.Records = append(out.Records, &pb.Record{Transfer: g.TransferToProtoTransfer(t)})
+		}
+
+		out.TotalCount, out.FilteredCount, out.TotalPages = res.TotalCount, res.FilteredCount, res.TotalPages
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown record type '%s'", req.Collection)
+	}
+
+	out.Page = q.Page
+	out.Limit = q.Limit
+	return out, nil
+}
+
+func describeJob(job *pb.StreamRecordsJob) string {
+	from, to := "the beginning", "now"
+
+	if job.From != nil {
+		from = job.From.AsTime().String()
+	}
+
+	if job.To != nil {
+		to = job.To.AsTime().String()
+	}
+
+	return fmt.Sprintf("from %s to %s", from, to)
+}
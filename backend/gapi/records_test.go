@@ -0,0 +1,48 @@
+package gapi
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	g "github.com/f-taxes/f-taxes/backend/global"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestResumeTokenRoundTrip(t *testing.T) {
+	token := resumeToken{
+		Ts:         time.Date(2023, 4, 1, 12, 30, 15, 123000000, time.UTC),
+		Collection: g.COL_TRANSFERS,
+		ID:         primitive.NewObjectID(),
+	}
+
+	parsed, err := parseResumeToken(token.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !parsed.Ts.Equal(token.Ts) || parsed.Collection != token.Collection || parsed.ID != token.ID {
+		t.Fatalf("expected %+v, got %+v", token, *parsed)
+	}
+}
+
+func TestParseEmptyResumeToken(t *testing.T) {
+	token, err := parseResumeToken("")
+	if token != nil || err != nil {
+		t.Fatalf("expected no token and no error, got %v and %v", token, err)
+	}
+}
+
+func TestParseMalformedResumeToken(t *testing.T) {
+	for name, s := range map[string]string{
+		"not base64":         "!!!",
+		"not json":           base64.RawURLEncoding.EncodeToString([]byte("trades")),
+		"unknown collection": resumeToken{Ts: time.Now(), Collection: "settings", ID: primitive.NewObjectID()}.String(),
+	} {
+		if _, err := parseResumeToken(s); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: expected InvalidArgument, got %v", name, err)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package gapi
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 
@@ -16,10 +17,12 @@ import (
 	pb "github.com/f-taxes/f-taxes/proto"
 	"github.com/kataras/golog"
 	"github.com/knadh/koanf"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
+	"github.com/qiniu/qmgo"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 var validator *protovalidate.Validator
@@ -39,6 +42,15 @@ func init() {
 
 // Create a grpc connection to the plugin if once it said hello.
 func (s *GapiServer) PluginHeartbeat(ctx context.Context, in *pb.PluginInfo) (*emptypb.Empty, error) {
+	if err := checkIdentity(ctx, in.ID); err != nil {
+		return nil, err
+	}
+
+	// Without authentication anyone could redirect F-Taxes to their own servers, so the addresses from the manifest or picked at the start are used.
+	if pluginFromContext(ctx) != "" {
+		plugin.Manager.UpdateAddresses(in.ID, in.CtlAddress, in.WebAddress)
+	}
+
 	if in.HasCtlServer {
 		plugin.Manager.ConnectBackToPlugin(in.ID)
 	}
@@ -49,51 +61,68 @@ func (s *GapiServer) PluginHeartbeat(ctx context.Context, in *pb.PluginInfo) (*e
 }
 
 func (s *GapiServer) StreamRecords(job *pb.StreamRecordsJob, stream pb.FTaxes_StreamRecordsServer) error {
-	colTrades := g.DBConn.Collection(g.COL_TRADES)
-	colTransfers := g.DBConn.Collection(g.COL_TRANSFERS)
-	golog.Infof("Plugin %s (v%s) requested a stream of records from %s to %s", job.Plugin, job.PluginVersion, job.From.AsTime(), job.To.AsTime())
+	if err := checkIdentity(stream.Context(), job.Plugin); err != nil {
+		return err
+	}
+
+	token, err := parseResumeToken(job.ResumeToken)
+	if err != nil {
+		return err
+	}
+
+	collections, err := jobCollections(job)
+	if err != nil {
+		return err
+	}
+
+	golog.Infof("Plugin %s (v%s) requested a stream of records %s", job.Plugin, job.PluginVersion, describeJob(job))
+
+	cursors := map[string]qmgo.CursorI{}
 
-	cursorTrades := colTrades.Find(context.Background(), bson.M{"ts": bson.M{"$gte": primitive.NewDateTimeFromTime(job.From.AsTime()), "$lte": primitive.NewDateTimeFromTime(job.To.AsTime())}}).Sort("ts").Cursor()
-	cursorTransfers := colTransfers.Find(context.Background(), bson.M{"ts": bson.M{"$gte": primitive.NewDateTimeFromTime(job.From.AsTime()), "$lte": primitive.NewDateTimeFromTime(job.To.AsTime())}}).Sort("ts").Cursor()
+	for _, col := range collections {
+		filter, err := jobFilter(job, col, token)
+		if err != nil {
+			return err
+		}
 
-	var hasTrade bool
-	var hasTransfer bool
-	trade := g.Trade{}
-	transfer := g.Transfer{}
+		cursors[col] = g.DBConn.Collection(col).Find(stream.Context(), filter).Sort("ts", "_id").Cursor()
+		defer cursors[col].Close()
+	}
+
+	// The next record of each collection that wasn't sent yet. Nil if it must be fetched or the cursor is exhausted.
+	var trade *g.Trade
+	var transfer *g.Transfer
 	tradeCount := 0
 	transferCount := 0
 
-	for {
-		if trade.Ts.IsZero() {
-			hasTrade = cursorTrades.Next(&trade)
+	for job.Limit <= 0 || int64(tradeCount+transferCount) < job.Limit {
+		if c, ok := cursors[g.COL_TRADES]; ok && trade == nil {
+			t := g.Trade{}
+			if c.Next(&t) {
+				trade = &t
+			}
 		}
 
-		if transfer.Ts.IsZero() {
-			hasTransfer = cursorTransfers.Next(&transfer)
+		if c, ok := cursors[g.COL_TRANSFERS]; ok && transfer == nil {
+			t := g.Transfer{}
+			if c.Next(&t) {
+				transfer = &t
+			}
 		}
 
-		if allFalse(hasTrade, hasTransfer) {
+		if trade == nil && transfer == nil {
 			break
 		}
 
-		var err error
-		e := getEarliest(&trade, &transfer)
-
-		switch e := e.(type) {
-		case *g.Trade:
+		// Trades come first if both have the same timestamp, matching the order of streamCollections.
+		if trade != nil && (transfer == nil || !transfer.Ts.Before(trade.Ts)) {
+			err = stream.Send(tradeRecord(*trade))
+			trade = nil
 			tradeCount++
-
-			err = stream.Send(&pb.Record{
-				Trade: g.TradeToProtoTrade(*e),
-			})
-			trade = g.Trade{}
-		case *g.Transfer:
+		} else {
+			err = stream.Send(transferRecord(*transfer))
+			transfer = nil
 			transferCount++
-
-			err = stream.Send(&pb.Record{
-				Transfer: g.TransferToProtoTransfer(*e),
-			})
-			transfer = g.Transfer{}
 		}
 
 		if err != nil {
@@ -101,34 +130,15 @@ func (s *GapiServer) StreamRecords(job *pb.StreamRecordsJob, stream pb.FTaxes_St
 		}
 	}
 
-	golog.Infof("Sent %d trades and %d transfers to plugin %s (v%s).", tradeCount, transferCount, job.Plugin, job.PluginVersion)
-	return nil
-}
-
-func getEarliest(records ...g.Record) g.Record {
-	var match g.Record
-
-	for i := range records {
-		if records[i].GetTs().IsZero() {
-			continue
-		}
-
-		if match == nil || records[i].GetTs().Before(match.GetTs()) {
-			match = records[i]
-		}
-	}
-
-	return match
-}
-
-func allFalse(states ...bool) bool {
-	for i := range states {
-		if states[i] {
-			return false
+	for col, c := range cursors {
+		if err := c.Err(); err != nil {
+			golog.Errorf("Failed to read %s for plugin %s: %v", col, job.Plugin, err)
+			return err
 		}
 	}
 
-	return true
+	golog.Infof("Sent %d trades and %d transfers to plugin %s (v%s).", tradeCount, transferCount, job.Plugin, job.PluginVersion)
+	return nil
 }
 
 func (s *GapiServer) SubmitTrade(ctx context.Context, t *pb.Trade) (*emptypb.Empty, error) {
@@ -137,7 +147,14 @@ func (s *GapiServer) SubmitTrade(ctx context.Context, t *pb.Trade) (*emptypb.Emp
 		return &emptypb.Empty{}, err
 	}
 
-	trades.StoreProtoTrade(t)
+	if err := checkIdentity(ctx, t.Plugin); err != nil {
+		return &emptypb.Empty{}, err
+	}
+
+	if id, inserted := trades.StoreProtoTrade(t); inserted {
+		imports.submitted(t.Plugin, g.COL_TRADES, id)
+	}
+
 	return &emptypb.Empty{}, nil
 }
 
@@ -147,7 +164,14 @@ func (s *GapiServer) SubmitTransfer(ctx context.Context, transfer *pb.Transfer)
 		return &emptypb.Empty{}, err
 	}
 
-	transfers.StoreProtoTransfer(transfer)
+	if err := checkIdentity(ctx, transfer.Plugin); err != nil {
+		return &emptypb.Empty{}, err
+	}
+
+	if id, inserted := transfers.StoreProtoTransfer(transfer); inserted {
+		imports.submitted(transfer.Plugin, g.COL_TRANSFERS, id)
+	}
+
 	return &emptypb.Empty{}, nil
 }
 
@@ -162,11 +186,17 @@ func (s *GapiServer) ShowJobProgress(ctx context.Context, job *pb.JobProgress) (
 		return &emptypb.Empty{}, err
 	}
 
+	if err := checkIdentity(ctx, job.Plugin); err != nil {
+		return &emptypb.Empty{}, err
+	}
+
 	global.PushToClients("job-progress", map[string]string{
 		"_id":      job.ID,
 		"label":    fmt.Sprintf("[%s] %s", job.Plugin, job.Label),
 		"progress": job.Progress,
 	})
+
+	imports.progress(job)
 	return &emptypb.Empty{}, nil
 }
 
@@ -182,6 +212,56 @@ func (s *GapiServer) GetSettings(ctx context.Context, _ *emptypb.Empty) (*pb.Set
 	}, nil
 }
 
+func (s *GapiServer) GetPluginConfig(ctx context.Context, in *pb.PluginConfigRequest) (*pb.PluginConfig, error) {
+	if err := checkIdentity(ctx, in.Plugin); err != nil {
+		return nil, err
+	}
+
+	values, err := plugin.Manager.GetConfig(in.Plugin)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without authentication anyone could claim to be the plugin, so secrets are left out.
+	if pluginFromContext(ctx) == "" {
+		uiConfig, err := plugin.Manager.GetUIConfig(in.Plugin)
+		if err != nil {
+			return nil, err
+		}
+
+		values = uiConfig.Values
+	}
+
+	pbValues, err := structpb.NewStruct(values)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.PluginConfig{
+		Plugin: in.Plugin,
+		Values: pbValues,
+	}, nil
+}
+
+func (s *GapiServer) SetPluginConfig(ctx context.Context, in *pb.PluginConfig) (*emptypb.Empty, error) {
+	if err := checkIdentity(ctx, in.Plugin); err != nil {
+		return &emptypb.Empty{}, err
+	}
+
+	if err := plugin.Manager.SetConfig(in.Plugin, in.Values.AsMap()); err != nil {
+		if errors.Is(err, plugin.ErrInvalidConfig) {
+			return &emptypb.Empty{}, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return &emptypb.Empty{}, err
+	}
+
+	global.PushToClients("plugin-config-changed", map[string]any{
+		"id": in.Plugin,
+	})
+
+	return &emptypb.Empty{}, nil
+}
+
 func (s *GapiServer) AppLog(ctx context.Context, msg *pb.AppLogMsg) (*emptypb.Empty, error) {
 	level := applog.Info
 
@@ -202,7 +282,15 @@ func Start(cfg *koanf.Koanf) {
 	if err != nil {
 		golog.Fatalf("failed to listen: %v", err)
 	}
-	s := grpc.NewServer()
+	opts := []grpc.ServerOption{}
+
+	if cfg.Bool("grpc.requireAuth") {
+		opts = append(opts, grpc.UnaryInterceptor(unaryAuthInterceptor), grpc.StreamInterceptor(streamAuthInterceptor))
+	} else {
+		golog.Warn("Authentication of plugins is disabled. Any local process can access the GRPC server.")
+	}
+
+	s := grpc.NewServer(opts...)
 	pb.RegisterFTaxesServer(s, srv)
 	golog.Infof("GRPC server listening at %v", lis.Addr())
 	if err := s.Serve(lis); err != nil {
@@ -0,0 +1,146 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	g "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/f-taxes/f-taxes/backend/history"
+	"github.com/f-taxes/f-taxes/backend/trades"
+	"github.com/f-taxes/f-taxes/backend/transfers"
+	pb "github.com/f-taxes/f-taxes/proto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Fields plugins can't change. Either because they identify the record or because F-Taxes doesn't store them.
+var immutableFields = []string{"ID", "TxID", "Plugin", "PluginVersion", "Created", "Updated", "SettlementCurrency", "OtherCosts"}
+
+func (s *GapiServer) UpdateTrade(ctx context.Context, req *pb.UpdateTradeRequest) (*pb.Trade, error) {
+	if err := checkIdentity(ctx, req.Plugin); err != nil {
+		return nil, err
+	}
+
+	if req.Trade == nil {
+		return nil, status.Error(codes.InvalidArgument, "trade is missing")
+	}
+
+	id, err := checkUpdate(req.Trade, req.Trade.ID, req.Trade.Updated, req.UpdateMask)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := trades.UpdateProtoTrade(id, req.Trade.Updated.AsTime(), func(t *pb.Trade) error {
+		return applyFieldMask(t, req.Trade, req.UpdateMask)
+	}, history.Actor{Source: history.SOURCE_PLUGIN, Name: req.Plugin})
+
+	if err != nil {
+		return nil, updateError(err)
+	}
+
+	return g.TradeToProtoTrade(updated), nil
+}
+
+func (s *GapiServer) UpdateTransfer(ctx context.Context, req *pb.UpdateTransferRequest) (*pb.Transfer, error) {
+	if err := checkIdentity(ctx, req.Plugin); err != nil {
+		return nil, err
+	}
+
+	if req.Transfer == nil {
+		return nil, status.Error(codes.InvalidArgument, "transfer is missing")
+	}
+
+	id, err := checkUpdate(req.Transfer, req.Transfer.ID, req.Transfer.Updated, req.UpdateMask)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := transfers.UpdateProtoTransfer(id, req.Transfer.Updated.AsTime(), func(t *pb.Transfer) error {
+		return applyFieldMask(t, req.Transfer, req.UpdateMask)
+	}, history.Actor{Source: history.SOURCE_PLUGIN, Name: req.Plugin})
+
+	if err != nil {
+		return nil, updateError(err)
+	}
+
+	return g.TransferToProtoTransfer(updated), nil
+}
+
+// Validates an update request and returns the id of the record to update.
+func checkUpdate(record proto.Message, id string, updated *timestamppb.Timestamp, mask *fieldmaskpb.FieldMask) (primitive.ObjectID, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return oid, status.Errorf(codes.InvalidArgument, "invalid record id '%s'", id)
+	}
+
+	if updated == nil {
+		return oid, status.Error(codes.InvalidArgument, "Updated must be set to the value of the record that was read")
+	}
+
+	if len(mask.GetPaths()) == 0 {
+		return oid, status.Error(codes.InvalidArgument, "update mask is empty")
+	}
+
+	if !mask.IsValid(record) {
+		return oid, status.Errorf(codes.InvalidArgument, "update mask %v contains unknown fields", mask.GetPaths())
+	}
+
+	for _, p := range mask.GetPaths() {
+		if g.ContainsAny(immutableFields, strings.Split(p, ".")[0]) {
+			return oid, status.Errorf(codes.InvalidArgument, "field %s can't be updated", p)
+		}
+	}
+
+	return oid, nil
+}
+
+func updateError(err error) error {
+	switch {
+	case errors.Is(err, g.ErrRecordNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, g.ErrRecordModified):
+		return status.Error(codes.Aborted, err.Error())
+	}
+
+	return err
+}
+
+// Copies the fields listed in the mask from src to dst. Fields that aren't set in src are cleared in dst.
+func applyFieldMask(dst, src proto.Message, mask *fieldmaskpb.FieldMask) error {
+	for _, p := range mask.GetPaths() {
+		d := dst.ProtoReflect()
+		s := src.ProtoReflect()
+		names := strings.Split(p, ".")
+
+		for i, name := range names {
+			fd := d.Descriptor().Fields().ByName(protoreflect.Name(name))
+			if fd == nil {
+				return status.Errorf(codes.InvalidArgument, "unknown field %s", p)
+			}
+
+			if i == len(names)-1 {
+				if s.Has(fd) {
+					d.Set(fd, s.Get(fd))
+				} else {
+					d.Clear(fd)
+				}
+				break
+			}
+
+			if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+				return status.Errorf(codes.InvalidArgument, "field %s has no sub fields", strings.Join(names[:i+1], "."))
+			}
+
+			d = d.Mutable(fd).Message()
+			s = s.Get(fd).Message()
+		}
+	}
+
+	return nil
+}
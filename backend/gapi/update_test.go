@@ -0,0 +1,107 @@
+package gapi
+
+import (
+	"testing"
+
+	pb "github.com/f-taxes/f-taxes/proto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestApplyFieldMask(t *testing.T) {
+	dst := &pb.Trade{Comment: "old", Account: "binance", Fee: &pb.Cost{Amount: "1", AmountC: "2"}}
+	src := &pb.Trade{Comment: "new", Account: "kraken", Fee: &pb.Cost{Amount: "5", AmountC: "3"}}
+
+	if err := applyFieldMask(dst, src, &fieldmaskpb.FieldMask{Paths: []string{"Comment", "Fee.AmountC"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if dst.Comment != "new" || dst.Fee.AmountC != "3" {
+		t.Fatalf("masked fields weren't copied: %v", dst)
+	}
+
+	if dst.Account != "binance" || dst.Fee.Amount != "1" {
+		t.Fatalf("fields outside of the mask were changed: %v", dst)
+	}
+}
+
+func TestApplyFieldMaskClearsUnsetFields(t *testing.T) {
+	dst := &pb.Trade{Comment: "old", QuoteFee: &pb.Cost{Amount: "1"}}
+
+	if err := applyFieldMask(dst, &pb.Trade{}, &fieldmaskpb.FieldMask{Paths: []string{"Comment", "QuoteFee"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if dst.Comment != "" || dst.QuoteFee != nil {
+		t.Fatalf("fields weren't cleared: %v", dst)
+	}
+}
+
+func TestApplyFieldMaskCreatesMissingMessages(t *testing.T) {
+	dst := &pb.Trade{}
+
+	if err := applyFieldMask(dst, &pb.Trade{Fee: &pb.Cost{PriceC: "42"}}, &fieldmaskpb.FieldMask{Paths: []string{"Fee.PriceC"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if dst.Fee.GetPriceC() != "42" {
+		t.Fatalf("nested field wasn't set: %v", dst)
+	}
+}
+
+func TestApplyFieldMaskRejectsInvalidPaths(t *testing.T) {
+	for _, path := range []string{"Comment.Text", "Unknown", "Fee.Unknown"} {
+		err := applyFieldMask(&pb.Trade{}, &pb.Trade{}, &fieldmaskpb.FieldMask{Paths: []string{path}})
+
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: expected InvalidArgument, got %v", path, err)
+		}
+	}
+}
+
+func TestCheckUpdate(t *testing.T) {
+	id := primitive.NewObjectID().Hex()
+	updated := timestamppb.Now()
+
+	if _, err := checkUpdate(&pb.Trade{}, id, updated, &fieldmaskpb.FieldMask{Paths: []string{"Comment", "Fee.AmountC"}}); err != nil {
+		t.Fatalf("valid update was rejected: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		id      string
+		updated *timestamppb.Timestamp
+		paths   []string
+	}{
+		{"invalid id", "nope", updated, []string{"Comment"}},
+		{"missing updated", id, nil, []string{"Comment"}},
+		{"empty mask", id, updated, nil},
+		{"unknown field", id, updated, []string{"Unknown"}},
+	} {
+		if _, err := checkUpdate(&pb.Trade{}, tc.id, tc.updated, &fieldmaskpb.FieldMask{Paths: tc.paths}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: expected InvalidArgument, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestCheckUpdateRejectsImmutableFields(t *testing.T) {
+	id := primitive.NewObjectID().Hex()
+
+	for _, tc := range []struct {
+		record proto.Message
+		paths  []string
+	}{
+		{&pb.Trade{}, []string{"ID", "TxID", "Plugin", "PluginVersion", "Created", "Updated", "Updated.seconds", "SettlementCurrency"}},
+		{&pb.Transfer{}, []string{"ID", "TxID", "Plugin", "PluginVersion", "Created", "Updated"}},
+	} {
+		for _, p := range tc.paths {
+			if _, err := checkUpdate(tc.record, id, timestamppb.Now(), &fieldmaskpb.FieldMask{Paths: []string{p}}); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("%s of %T: expected InvalidArgument, got %v", p, tc.record, err)
+			}
+		}
+	}
+}
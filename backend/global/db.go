@@ -2,11 +2,13 @@ package global
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/kataras/golog"
 	"github.com/knadh/koanf"
 	"github.com/qiniu/qmgo"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 const COL_TRANSFERS = "transfers"
@@ -27,3 +29,65 @@ func ConnectDB(cfg *koanf.Koanf) {
 	DBConn = client.Database(cfg.MustString("database.name"))
 	golog.Debug("Database connection established")
 }
+
+// Checks whether a collection with the given name exists in the database.
+func CollectionExists(name string) (bool, error) {
+	col, err := DBConn.Collection(name).CloneCollection()
+	if err != nil {
+		return false, err
+	}
+
+	names, err := col.Database().ListCollectionNames(context.Background(), bson.M{"name": name})
+	if err != nil {
+		return false, err
+	}
+
+	return len(names) > 0, nil
+}
+
+// Creates an empty collection. Collections are otherwise only created by the first insert.
+func CreateCollection(name string) error {
+	col, err := DBConn.Collection(name).CloneCollection()
+	if err != nil {
+		return err
+	}
+
+	return col.Database().CreateCollection(context.Background(), name)
+}
+
+// Renames a collection within the database. An existing collection with the target name is dropped.
+// Renaming a single collection is atomic in MongoDB.
+func RenameCollection(from, to string) error {
+	col, err := DBConn.Collection(from).CloneCollection()
+	if err != nil {
+		return err
+	}
+
+	dbName := DBConn.GetDatabaseName()
+	return col.Database().Client().Database("admin").RunCommand(context.Background(), bson.D{
+		{Key: "renameCollection", Value: dbName + "." + from},
+		{Key: "to", Value: dbName + "." + to},
+		{Key: "dropTarget", Value: true},
+	}).Err()
+}
+
+var ErrRecordNotFound = errors.New("record not found")
+var ErrRecordModified = errors.New("record was modified concurrently")
+
+// Restricts a filter to records that aren't in the trash.
+func NotDeleted(filter bson.M) bson.M {
+	if len(filter) == 0 {
+		return bson.M{"deletedAt": nil}
+	}
+
+	return bson.M{"$and": bson.A{filter, bson.M{"deletedAt": nil}}}
+}
+
+// Restricts a filter to records that are in the trash.
+func Deleted(filter bson.M) bson.M {
+	if len(filter) == 0 {
+		return bson.M{"deletedAt": bson.M{"$ne": nil}}
+	}
+
+	return bson.M{"$and": bson.A{filter, bson.M{"deletedAt": bson.M{"$ne": nil}}}}
+}
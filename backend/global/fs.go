@@ -3,12 +3,18 @@ package global
 import (
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/kataras/golog"
 )
 
+// Recursively searches for files with the given name. Hidden directories (starting with a dot) are skipped.
 func FindFileByName(name, searchRoot string) (paths []string) {
 	err := filepath.Walk(searchRoot, func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.IsDir() && path != searchRoot && strings.HasPrefix(info.Name(), ".") {
+			return filepath.SkipDir
+		}
+
 		if err == nil && info.Name() == name {
 			if !info.IsDir() {
 				paths = append(paths, path)
@@ -9,3 +9,16 @@ type Query struct {
 	Filter            [][]Filter `json:"filter"`
 	ConstructedFilter bson.M     `json:"constructedFilter"`
 }
+
+// Fields to sort by. Records with the same value are ordered by their id, so they don't move between pages.
+func (q Query) SortFields() []string {
+	if q.Sort == "" {
+		return []string{"_id"}
+	}
+
+	if q.Sort == "_id" || q.Sort == "-_id" {
+		return []string{q.Sort}
+	}
+
+	return []string{q.Sort, "_id"}
+}
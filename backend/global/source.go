@@ -1,11 +1,5 @@
 package global
 
-import (
-	"time"
-
-	"go.mongodb.org/mongo-driver/bson/primitive"
-)
-
 type SourceType string
 
 const (
@@ -25,16 +19,3 @@ type SourceInfo struct {
 	Type  SourceType `json:"type"`
 	ID    string     `json:"id"`
 }
-
-type SourceConnection struct {
-	ID         primitive.ObjectID `bson:"_id" json:"_id"`
-	Label      string             `bson:"label" json:"label"`
-	Notes      string             `bson:"notes" json:"notes"`
-	SrcName    string             `bson:"srcName" json:"srcName"`
-	ApiKey     string             `bson:"apiKey" json:"key"`
-	ApiSecret  string             `bson:"apiSecret" json:"secret"`
-	Subaccount string             `bson:"subaccount" json:"subaccount"`
-
-	// Timestamp of the last time the app fetched trades from the source.
-	LastFetched time.Time `bson:"lastFetched" json:"lastFetched"`
-}
@@ -79,6 +79,7 @@ type Trade struct {
 	PluginVersion         string             `json:"pluginVersion" bson:"pluginVersion"`
 	Created               time.Time          `json:"created" bson:"created"`
 	Updated               time.Time          `json:"updated" bson:"updated"`
+	DeletedAt             *time.Time         `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"` // Set while the trade is in the trash.
 }
 
 func (t Trade) GetTs() time.Time {
@@ -133,6 +134,7 @@ func (t Trade) MarshalBSON() ([]byte, error) {
 		PluginVersion: t.PluginVersion,
 		Created:       t.Created,
 		Updated:       t.Updated,
+		DeletedAt:     t.DeletedAt,
 	})
 
 	if err != nil {
@@ -201,6 +203,8 @@ func (t *Trade) UnmarshalBSON(b []byte) error {
 	t.Plugin = d.Plugin
 	t.PluginVersion = d.PluginVersion
 	t.Created = d.Created
+	t.Updated = d.Updated
+	t.DeletedAt = d.DeletedAt
 	return nil
 }
 
@@ -245,20 +249,23 @@ type tradeDoc struct {
 	PluginVersion         string               `json:"pluginVersion" bson:"pluginVersion"`
 	Created               time.Time            `json:"created" bson:"created"`
 	Updated               time.Time            `json:"updated" bson:"updated"`
+	DeletedAt             *time.Time           `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
 }
 
-func (t *Trade) Store() error {
+// Inserts the trade unless one with the same TxID already exists, in which case false is returned.
+// Trades in the trash count as existing, so deleted trades aren't imported again.
+func (t *Trade) Store() (bool, error) {
 	col := DBConn.Collection(COL_TRADES)
 
 	if t.TxID != "" {
 		count, _ := col.Find(context.Background(), bson.M{"txId": t.TxID}).Count()
 		if count > 0 {
-			return nil
+			return false, nil
 		}
 	}
 
 	_, err := col.InsertOne(context.Background(), t)
-	return err
+	return err == nil, err
 }
 
 func DecimalToMongoDecimal(v decimal.Decimal) primitive.Decimal128 {
@@ -380,5 +387,6 @@ func TradeToProtoTrade(t Trade) *proto.Trade {
 		PluginVersion: t.PluginVersion,
 		Created:       timestamppb.New(t.Created),
 		Updated:       timestamppb.New(t.Updated),
+		ID:            t.ID.Hex(),
 	}
 }
@@ -42,28 +42,31 @@ type Transfer struct {
 	FeeCurrency    Currency        `json:"feeCurrency" bson:"feeCurrency"`
 	FeePriceC      decimal.Decimal `json:"feePriceC" bson:"feePriceC"` // Price of the fee converted. Lets say the fee is quoted in SOL. FeePriceC would be the price of SOL at the time of transfer.
 
-	Plugin        string    `json:"plugin" bson:"plugin"`
-	PluginVersion string    `json:"pluginVersion" bson:"pluginVersion"`
-	Created       time.Time `json:"created" bson:"created"`
-	Updated       time.Time `json:"updated" bson:"updated"`
+	Plugin        string     `json:"plugin" bson:"plugin"`
+	PluginVersion string     `json:"pluginVersion" bson:"pluginVersion"`
+	Created       time.Time  `json:"created" bson:"created"`
+	Updated       time.Time  `json:"updated" bson:"updated"`
+	DeletedAt     *time.Time `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"` // Set while the transfer is in the trash.
 }
 
 func (t Transfer) GetTs() time.Time {
 	return t.Ts
 }
 
-func (t *Transfer) Store() error {
+// Inserts the transfer unless one with the same TxID already exists, in which case false is returned.
+// Transfers in the trash count as existing, so deleted transfers aren't imported again.
+func (t *Transfer) Store() (bool, error) {
 	col := DBConn.Collection(COL_TRANSFERS)
 
 	if t.TxID != "" {
 		count, _ := col.Find(context.Background(), bson.M{"txId": t.TxID}).Count()
 		if count > 0 {
-			return nil
+			return false, nil
 		}
 	}
 
 	_, err := col.InsertOne(context.Background(), t)
-	return err
+	return err == nil, err
 }
 
 func (t Transfer) MarshalBSON() ([]byte, error) {
@@ -91,6 +94,7 @@ func (t Transfer) MarshalBSON() ([]byte, error) {
 		PluginVersion: t.PluginVersion,
 		Created:       t.Created,
 		Updated:       t.Updated,
+		DeletedAt:     t.DeletedAt,
 	})
 
 	if err != nil {
@@ -132,6 +136,8 @@ func (t *Transfer) UnmarshalBSON(b []byte) error {
 	t.Plugin = d.Plugin
 	t.PluginVersion = d.PluginVersion
 	t.Created = d.Created
+	t.Updated = d.Updated
+	t.DeletedAt = d.DeletedAt
 	return nil
 }
 
@@ -157,10 +163,11 @@ type transferDoc struct {
 	FeeC           primitive.Decimal128 `json:"feeC" bson:"feeC"`
 	FeeConvertedBy string               `json:"feeConvertedBy"`
 
-	Plugin        string    `json:"plugin" bson:"plugin"`
-	PluginVersion string    `json:"pluginVersion" bson:"pluginVersion"`
-	Created       time.Time `json:"created" bson:"created"`
-	Updated       time.Time `json:"updated" bson:"updated"`
+	Plugin        string     `json:"plugin" bson:"plugin"`
+	PluginVersion string     `json:"pluginVersion" bson:"pluginVersion"`
+	Created       time.Time  `json:"created" bson:"created"`
+	Updated       time.Time  `json:"updated" bson:"updated"`
+	DeletedAt     *time.Time `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
 }
 
 func ProtoTransferToTransfer(t *proto.Transfer) Transfer {
@@ -211,5 +218,6 @@ func TransferToProtoTransfer(t Transfer) *proto.Transfer {
 		PluginVersion:  t.PluginVersion,
 		Created:        timestamppb.New(t.Created),
 		Updated:        timestamppb.New(t.Updated),
+		ID:             t.ID.Hex(),
 	}
 }
@@ -0,0 +1,288 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/f-taxes/f-taxes/backend/events"
+	g "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/kataras/golog"
+	"github.com/qiniu/qmgo"
+	"github.com/qiniu/qmgo/options"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const COL_HISTORY = "history"
+
+var ErrModifiedSince = errors.New("the record was modified after this change")
+
+// Moves records to the trash. Set by the trash package, which depends on this one.
+// Reverting the creation of a trade or transfer uses it, so the record can still be restored.
+var MoveToTrash func(collection string, filter bson.M, actor Actor) (int64, error)
+
+type Source string
+
+const (
+	SOURCE_UI         = Source("ui")         // Changes made by the user in the app.
+	SOURCE_PLUGIN     = Source("plugin")     // Changes requested by a plugin via grpc.
+	SOURCE_CONVERSION = Source("conversion") // Prices converted by a conversion job.
+	SOURCE_IMPORT     = Source("import")     // Records submitted by an import plugin.
+	SOURCE_REVERT     = Source("revert")     // Changes undone via the history.
+	SOURCE_SYSTEM     = Source("system")     // Changes made by the app itself, e.g. default settings.
+)
+
+type Action string
+
+const (
+	CREATED = Action("created")
+	UPDATED = Action("updated")
+	DELETED = Action("deleted")
+)
+
+// Describes who changed a record.
+type Actor struct {
+	Source Source `json:"source" bson:"source"`
+	Name   string `json:"name" bson:"name"` // E.g. the id of the plugin that made the change.
+	Ref    string `json:"ref" bson:"ref"`   // Optional reference to the job or batch the change was part of.
+}
+
+func UI() Actor {
+	return Actor{Source: SOURCE_UI, Name: "user"}
+}
+
+// A single change of a record. Before is nil for created records and After is nil for deleted ones.
+type Entry struct {
+	ID         primitive.ObjectID `json:"_id" bson:"_id"`
+	RecordID   primitive.ObjectID `json:"recordId" bson:"recordId"`
+	Collection string             `json:"collection" bson:"collection"`
+	Action     Action             `json:"action" bson:"action"`
+	Before     bson.M             `json:"before" bson:"before"`
+	After      bson.M             `json:"after" bson:"after"`
+	Actor      Actor              `json:"actor" bson:"actor"`
+	Ts         time.Time          `json:"ts" bson:"ts"`
+}
+
+func Setup() {
+	col := g.DBConn.Collection(COL_HISTORY)
+	col.CreateIndexes(context.Background(), []options.IndexModel{
+		{Key: []string{"recordId", "-ts"}, Background: true},
+	})
+}
+
+// Stores a change of a record in the given collection. before and after are the complete documents (e.g. a Trade),
+// nil marks a record that didn't exist before or doesn't exist anymore. Changes that didn't alter the document aren't stored.
+func Record(collection string, recordID primitive.ObjectID, before, after any, actor Actor) {
+	entry, changed, err := newEntry(collection, recordID, before, after, actor)
+
+	if err != nil {
+		golog.Errorf("Failed to prepare history entry for %s %s: %v", collection, recordID.Hex(), err)
+		return
+	}
+
+	if !changed {
+		return
+	}
+
+	if _, err := g.DBConn.Collection(COL_HISTORY).InsertOne(context.Background(), entry); err != nil {
+		golog.Errorf("Failed to store history entry for %s %s: %v", collection, recordID.Hex(), err)
+	}
+}
+
+// Stores a deletion for every document in the collection that matches the filter.
+// Must be called before the documents are actually removed.
+func RecordDeletions(collection string, filter bson.M, actor Actor) error {
+	return recordMany(collection, filter, func(doc bson.M) (Entry, error) {
+		id, _ := doc["_id"].(primitive.ObjectID)
+		entry, _, err := newEntry(collection, id, doc, nil, actor)
+		return entry, err
+	})
+}
+
+func recordMany(collection string, filter bson.M, toEntry func(doc bson.M) (Entry, error)) error {
+	cursor := g.DBConn.Collection(collection).Find(context.Background(), filter).Cursor()
+	defer cursor.Close()
+
+	batch := []Entry{}
+
+	for {
+		doc := bson.M{}
+		if !cursor.Next(&doc) {
+			break
+		}
+
+		entry, err := toEntry(doc)
+		if err != nil {
+			return err
+		}
+
+		batch = append(batch, entry)
+
+		if len(batch) == 1000 {
+			if _, err := g.DBConn.Collection(COL_HISTORY).InsertMany(context.Background(), batch); err != nil {
+				return err
+			}
+			batch = []Entry{}
+		}
+	}
+
+	if len(batch) > 0 {
+		if _, err := g.DBConn.Collection(COL_HISTORY).InsertMany(context.Background(), batch); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// Stores a change for every document in the collection that matches the filter.
+// apply receives a copy of each document and must modify it the same way the upcoming update will.
+// Must be called before the documents are actually updated.
+func RecordUpdates(collection string, filter bson.M, apply func(doc bson.M), actor Actor) error {
+	return recordMany(collection, filter, func(doc bson.M) (Entry, error) {
+		after := bson.M{}
+		for k, v := range doc {
+			after[k] = v
+		}
+		apply(after)
+
+		id, _ := doc["_id"].(primitive.ObjectID)
+		entry, _, err := newEntry(collection, id, doc, after, actor)
+		return entry, err
+	})
+}
+
+func newEntry(collection string, recordID primitive.ObjectID, before, after any, actor Actor) (Entry, bool, error) {
+	entry := Entry{
+		ID:         primitive.NewObjectID(),
+		RecordID:   recordID,
+		Collection: collection,
+		Actor:      actor,
+		Ts:         time.Now().UTC(),
+	}
+
+	beforeRaw, err := toRaw(before)
+	if err != nil {
+		return entry, false, err
+	}
+
+	afterRaw, err := toRaw(after)
+	if err != nil {
+		return entry, false, err
+	}
+
+	switch {
+	case beforeRaw == nil:
+		entry.Action = CREATED
+	case afterRaw == nil:
+		entry.Action = DELETED
+	default:
+		entry.Action = UPDATED
+	}
+
+	if beforeRaw != nil {
+		if err := bson.Unmarshal(beforeRaw, &entry.Before); err != nil {
+			return entry, false, err
+		}
+	}
+
+	if afterRaw != nil {
+		if err := bson.Unmarshal(afterRaw, &entry.After); err != nil {
+			return entry, false, err
+		}
+	}
+
+	return entry, !reflect.DeepEqual(entry.Before, entry.After), nil
+}
+
+func toRaw(doc any) ([]byte, error) {
+	if doc == nil {
+		return nil, nil
+	}
+
+	if m, ok := doc.(bson.M); ok && m == nil {
+		return nil, nil
+	}
+
+	return bson.Marshal(doc)
+}
+
+// Lists all changes of a record, latest first.
+func List(recordID primitive.ObjectID) ([]Entry, error) {
+	list := []Entry{}
+	err := g.DBConn.Collection(COL_HISTORY).Find(context.Background(), bson.M{"recordId": recordID}).Sort("-ts").All(&list)
+	return list, err
+}
+
+func Get(id primitive.ObjectID) (Entry, error) {
+	entry := Entry{}
+	err := g.DBConn.Collection(COL_HISTORY).Find(context.Background(), bson.M{"_id": id}).One(&entry)
+	return entry, err
+}
+
+// Fetches the current state of a record as a plain document. Returns nil if the record doesn't exist.
+func Current(collection string, recordID primitive.ObjectID) (bson.M, error) {
+	doc := bson.M{}
+	err := g.DBConn.Collection(collection).Find(context.Background(), bson.M{"_id": recordID}).One(&doc)
+
+	if qmgo.IsErrNoDocuments(err) {
+		return nil, nil
+	}
+
+	return doc, err
+}
+
+// Restores the state a record had before the given change. The revert itself is recorded as a new change.
+// Fails with ErrModifiedSince if the record was changed afterwards, unless force is set.
+func Revert(entryID primitive.ObjectID, force bool) (Entry, error) {
+	entry, err := Get(entryID)
+	if err != nil {
+		return entry, err
+	}
+
+	current, err := Current(entry.Collection, entry.RecordID)
+	if err != nil {
+		return entry, err
+	}
+
+	if !force && !reflect.DeepEqual(current, entry.After) {
+		return entry, ErrModifiedSince
+	}
+
+	actor := Actor{Source: SOURCE_REVERT, Name: "user", Ref: entry.ID.Hex()}
+
+	// The trash records the change and announces the deletion itself.
+	if entry.Before == nil && MoveToTrash != nil && (entry.Collection == g.COL_TRADES || entry.Collection == g.COL_TRANSFERS) {
+		_, err := MoveToTrash(entry.Collection, bson.M{"_id": entry.RecordID}, actor)
+		return entry, err
+	}
+
+	col := g.DBConn.Collection(entry.Collection)
+
+	if entry.Before == nil {
+		err = col.RemoveId(context.Background(), entry.RecordID)
+	} else {
+		_, err = col.UpsertId(context.Background(), entry.RecordID, entry.Before)
+	}
+
+	if err != nil {
+		return entry, err
+	}
+
+	Record(entry.Collection, entry.RecordID, current, entry.Before, actor)
+
+	if entry.Collection == g.COL_TRADES || entry.Collection == g.COL_TRANSFERS {
+		switch {
+		case entry.Before == nil || entry.Before["deletedAt"] != nil:
+			events.RecordsChanged(events.RECORD_DELETED, entry.Collection, entry.RecordID)
+		case current == nil || current["deletedAt"] != nil:
+			events.RecordsChanged(events.RECORD_CREATED, entry.Collection, entry.RecordID)
+		default:
+			events.RecordsChanged(events.RECORD_UPDATED, entry.Collection, entry.RecordID)
+		}
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,77 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/f-taxes/f-taxes/backend/applog"
+	g "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/kataras/golog"
+	"github.com/kataras/iris/v12"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func RegisterRoutes(app *iris.Application) {
+	Setup()
+
+	app.Get("/history/{id:string}", func(ctx iris.Context) {
+		id, err := primitive.ObjectIDFromHex(ctx.Params().GetString("id"))
+
+		if err != nil {
+			ctx.StatusCode(iris.StatusBadRequest)
+			return
+		}
+
+		entries, err := List(id)
+
+		if err != nil {
+			golog.Errorf("Failed to fetch history of record %s: %v", id.Hex(), err)
+			ctx.StatusCode(iris.StatusInternalServerError)
+			return
+		}
+
+		ctx.JSON(g.Resp{
+			Result: true,
+			Data:   entries,
+		})
+	})
+
+	app.Post("/history/revert", func(ctx iris.Context) {
+		reqData := struct {
+			ID    primitive.ObjectID `json:"_id"`
+			Force bool               `json:"force"`
+		}{}
+
+		if !g.ReadJSON(ctx, &reqData) {
+			return
+		}
+
+		entry, err := Revert(reqData.ID, reqData.Force)
+
+		if errors.Is(err, ErrModifiedSince) {
+			ctx.JSON(g.Resp{
+				Result: false,
+				Data:   err.Error(),
+			})
+			return
+		}
+
+		if err != nil {
+			applog.Send(applog.Error, fmt.Sprintf("Failed to revert change: %s", err.Error()))
+
+			ctx.JSON(g.Resp{
+				Result: false,
+			})
+			return
+		}
+
+		g.PushToClients("record-reverted", map[string]any{
+			"_id":        entry.RecordID,
+			"collection": entry.Collection,
+		})
+
+		ctx.JSON(g.Resp{
+			Result: true,
+		})
+	})
+}
@@ -0,0 +1,53 @@
+package plugin
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+)
+
+// Environment variables used to hand a plugin its identity and secret token for the FTaxes grpc server.
+const ENV_PLUGIN_ID = "FTAXES_PLUGIN_ID"
+const ENV_PLUGIN_TOKEN = "FTAXES_PLUGIN_TOKEN"
+
+// Creates a new secret token for the plugin, replacing any previous one.
+func (m *PluginManager) issueToken(id string) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	token := hex.EncodeToString(raw)
+
+	m.Lock()
+	m.tokens[id] = token
+	m.Unlock()
+
+	return token, nil
+}
+
+func (m *PluginManager) revokeToken(id, token string) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.tokens[id] == token {
+		delete(m.tokens, id)
+	}
+}
+
+func (m *PluginManager) hasToken(id string) bool {
+	m.Lock()
+	defer m.Unlock()
+
+	_, ok := m.tokens[id]
+	return ok
+}
+
+// Checks whether the token was issued to the plugin with the given id.
+func (m *PluginManager) Authenticate(id, token string) bool {
+	m.Lock()
+	expected, ok := m.tokens[id]
+	m.Unlock()
+
+	return ok && token != "" && subtle.ConstantTimeCompare([]byte(expected), []byte(token)) == 1
+}
@@ -0,0 +1,403 @@
+package plugin
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	. "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/qiniu/qmgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const COL_PLUGIN_CONFIG = "plugin_config"
+
+// Prefix of secret values that are stored encrypted.
+const encryptedPrefix = "enc:v1:"
+
+var ErrInvalidConfig = errors.New("invalid plugin configuration")
+
+// Subset of JSON Schema that F-Taxes understands when validating a plugin's configuration.
+// The UI receives the full schema from the manifest and may use additional keywords to render the form.
+type ConfigSchema struct {
+	Type       string                    `json:"type"`
+	Properties map[string]ConfigProperty `json:"properties"`
+	Required   []string                  `json:"required"`
+}
+
+type ConfigProperty struct {
+	Type      string `json:"type"`
+	Enum      []any  `json:"enum"`
+	WriteOnly bool   `json:"writeOnly"` // Marks a secret like an api key. It is encrypted at rest and never sent back to the UI.
+	Format    string `json:"format"`    // Properties with the format "password" are treated as secrets as well.
+}
+
+func (p ConfigProperty) IsSecret() bool {
+	return p.WriteOnly || p.Format == "password"
+}
+
+type configDoc struct {
+	ID        string         `bson:"_id"`
+	Values    map[string]any `bson:"values"`
+	UpdatedAt time.Time      `bson:"updatedAt"`
+}
+
+// Configuration of a plugin as presented to the UI. Secrets are left out of Values, IsSet tells whether they have a value.
+type UIConfig struct {
+	Schema json.RawMessage `json:"schema"`
+	Values map[string]any  `json:"values"`
+	IsSet  map[string]bool `json:"isSet"`
+}
+
+func (m Manifest) configSchema() (ConfigSchema, error) {
+	schema := ConfigSchema{Properties: map[string]ConfigProperty{}}
+
+	if len(m.ConfigSchema) == 0 {
+		return schema, nil
+	}
+
+	if err := json.Unmarshal(m.ConfigSchema, &schema); err != nil {
+		return schema, fmt.Errorf("plugin %s has an invalid config schema: %w", m.ID, err)
+	}
+
+	return schema, nil
+}
+
+// Returns the decrypted configuration of a plugin.
+func (m *PluginManager) GetConfig(id string) (map[string]any, error) {
+	manifest, ok := m.findLocalManifestById(id)
+	if !ok {
+		return nil, fmt.Errorf("plugin %s isn't installed", id)
+	}
+
+	schema, err := manifest.configSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	return m.config(id, schema)
+}
+
+// Returns the configuration of a plugin. Only values the schema marks as secret are decrypted,
+// anything else is returned as stored, even if it happens to look like an encrypted value.
+func (m *PluginManager) config(id string, schema ConfigSchema) (map[string]any, error) {
+	doc := configDoc{}
+	err := DBConn.Collection(COL_PLUGIN_CONFIG).Find(context.Background(), bson.M{"_id": id}).One(&doc)
+
+	if qmgo.IsErrNoDocuments(err) {
+		return map[string]any{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]any{}
+
+	for k, v := range doc.Values {
+		s, ok := v.(string)
+		if !ok || !schema.Properties[k].IsSecret() || !strings.HasPrefix(s, encryptedPrefix) {
+			values[k] = normalizeConfigValue(v)
+			continue
+		}
+
+		plain, err := m.decryptSecret(id, k, s)
+		if err != nil {
+			return nil, err
+		}
+
+		values[k] = plain
+	}
+
+	return values, nil
+}
+
+// Merges the given values into the plugin's configuration. Keys that are missing keep their value, a nil value removes the key.
+// The result is validated against the schema in the plugin's manifest and secrets are encrypted before being stored.
+func (m *PluginManager) SetConfig(id string, updates map[string]any) error {
+	manifest, ok := m.findLocalManifestById(id)
+	if !ok {
+		return fmt.Errorf("plugin %s isn't installed", id)
+	}
+
+	schema, err := manifest.configSchema()
+	if err != nil {
+		return err
+	}
+
+	values, err := m.config(id, schema)
+	if err != nil {
+		return err
+	}
+
+	for k, v := range updates {
+		if v == nil {
+			delete(values, k)
+			continue
+		}
+		values[k] = v
+	}
+
+	if err := schema.validate(values); err != nil {
+		return err
+	}
+
+	stored := map[string]any{}
+
+	for k, v := range values {
+		if !schema.Properties[k].IsSecret() {
+			stored[k] = v
+			continue
+		}
+
+		enc, err := m.encryptSecret(id, k, v)
+		if err != nil {
+			return err
+		}
+
+		stored[k] = enc
+	}
+
+	_, err = DBConn.Collection(COL_PLUGIN_CONFIG).Upsert(context.Background(), bson.M{"_id": id}, configDoc{
+		ID:        id,
+		Values:    stored,
+		UpdatedAt: time.Now().UTC(),
+	})
+
+	return err
+}
+
+// Returns the plugin's config schema and its values without secrets.
+func (m *PluginManager) GetUIConfig(id string) (UIConfig, error) {
+	manifest, ok := m.findLocalManifestById(id)
+	if !ok {
+		return UIConfig{}, fmt.Errorf("plugin %s isn't installed", id)
+	}
+
+	schema, err := manifest.configSchema()
+	if err != nil {
+		return UIConfig{}, err
+	}
+
+	values, err := m.config(id, schema)
+	if err != nil {
+		return UIConfig{}, err
+	}
+
+	cfg := UIConfig{
+		Schema: manifest.ConfigSchema,
+		Values: map[string]any{},
+		IsSet:  map[string]bool{},
+	}
+
+	for k, v := range values {
+		cfg.IsSet[k] = true
+
+		if !schema.Properties[k].IsSecret() {
+			cfg.Values[k] = v
+		}
+	}
+
+	return cfg, nil
+}
+
+func (m *PluginManager) removeConfig(id string) error {
+	err := DBConn.Collection(COL_PLUGIN_CONFIG).RemoveId(context.Background(), id)
+	if qmgo.IsErrNoDocuments(err) {
+		return nil
+	}
+	return err
+}
+
+func (s ConfigSchema) validate(values map[string]any) error {
+	for _, k := range s.Required {
+		if _, ok := values[k]; !ok {
+			return fmt.Errorf("%w: %s is required", ErrInvalidConfig, k)
+		}
+	}
+
+	for k, v := range values {
+		prop, ok := s.Properties[k]
+		if !ok {
+			continue
+		}
+
+		if !prop.matchesType(v) {
+			return fmt.Errorf("%w: %s must be of type %s", ErrInvalidConfig, k, prop.Type)
+		}
+
+		if len(prop.Enum) > 0 && !slices.ContainsFunc(prop.Enum, func(e any) bool { return fmt.Sprint(e) == fmt.Sprint(v) }) {
+			return fmt.Errorf("%w: %s must be one of %v", ErrInvalidConfig, k, prop.Enum)
+		}
+	}
+
+	return nil
+}
+
+func (p ConfigProperty) matchesType(v any) bool {
+	switch p.Type {
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	}
+
+	return true
+}
+
+// Converts values read from the database into the types encoding/json would produce, so they validate against the schema.
+func normalizeConfigValue(v any) any {
+	switch v := v.(type) {
+	case bson.D:
+		out := map[string]any{}
+		for _, e := range v {
+			out[e.Key] = normalizeConfigValue(e.Value)
+		}
+		return out
+	case bson.M:
+		out := map[string]any{}
+		for k, e := range v {
+			out[k] = normalizeConfigValue(e)
+		}
+		return out
+	case bson.A:
+		out := []any{}
+		for _, e := range v {
+			out = append(out, normalizeConfigValue(e))
+		}
+		return out
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+
+	return v
+}
+
+// Loads the key secrets are encrypted with. A new key is created if the key file doesn't exist yet.
+func (m *PluginManager) secretKey() ([]byte, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.configKey != nil {
+		return m.configKey, nil
+	}
+
+	raw, err := os.ReadFile(m.SecretKeyFile)
+
+	if errors.Is(err, os.ErrNotExist) {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+
+		if err := os.MkdirAll(filepath.Dir(m.SecretKeyFile), 0700); err != nil {
+			return nil, err
+		}
+
+		if err := os.WriteFile(m.SecretKeyFile, []byte(base64.StdEncoding.EncodeToString(key)), 0600); err != nil {
+			return nil, err
+		}
+
+		m.configKey = key
+		return key, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("secret key file %s is invalid", m.SecretKeyFile)
+	}
+
+	m.configKey = key
+	return key, nil
+}
+
+func (m *PluginManager) configAEAD() (cipher.AEAD, error) {
+	key, err := m.secretKey()
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// Secrets are bound to the plugin and key they belong to, so they can't be copied over to another plugin's configuration.
+func secretAdditionalData(id, key string) []byte {
+	return []byte(id + "/" + key)
+}
+
+func (m *PluginManager) encryptSecret(id, key string, v any) (string, error) {
+	aead, err := m.configAEAD()
+	if err != nil {
+		return "", err
+	}
+
+	plain, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, plain, secretAdditionalData(id, key))
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (m *PluginManager) decryptSecret(id, key, s string) (any, error) {
+	aead, err := m.configAEAD()
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, encryptedPrefix))
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("secret %s of plugin %s is malformed", key, id)
+	}
+
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], secretAdditionalData(id, key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secret %s of plugin %s: %w", key, id, err)
+	}
+
+	var v any
+	err = json.Unmarshal(plain, &v)
+	return v, err
+}
@@ -0,0 +1,52 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/f-taxes/f-taxes/backend/global"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestGetConfigOnlyDecryptsSecrets(t *testing.T) {
+	connectTestDB(t)
+
+	m := &PluginManager{PluginPath: t.TempDir()}
+	m.SecretKeyFile = filepath.Join(m.PluginPath, "secret.key")
+
+	manifest := Manifest{ID: "test_config", ConfigSchema: json.RawMessage(`{"type": "object", "properties": {"apiKey": {"type": "string", "writeOnly": true}, "note": {"type": "string"}}}`)}
+	data, _ := json.Marshal(manifest)
+	os.MkdirAll(filepath.Join(m.PluginPath, manifest.ID), 0755)
+	if err := os.WriteFile(filepath.Join(m.PluginPath, manifest.ID, "manifest.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A plain value that merely looks encrypted.
+	note := encryptedPrefix + "not a secret"
+
+	if err := m.SetConfig(manifest.ID, map[string]any{"apiKey": "secret", "note": note}); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := configDoc{}
+	if err := DBConn.Collection(COL_PLUGIN_CONFIG).Find(context.Background(), bson.M{"_id": manifest.ID}).One(&doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if s, _ := doc.Values["apiKey"].(string); !strings.HasPrefix(s, encryptedPrefix) {
+		t.Fatalf("secret was stored as %v", doc.Values["apiKey"])
+	}
+
+	values, err := m.GetConfig(manifest.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if values["apiKey"] != "secret" || values["note"] != note {
+		t.Fatalf("unexpected config %v", values)
+	}
+}
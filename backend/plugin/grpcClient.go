@@ -2,56 +2,154 @@ package plugin
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/f-taxes/f-taxes/proto"
 	"github.com/kataras/golog"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
 type CtlClient struct {
+	sync.Mutex
 	name       string
+	started    bool
+	closed     bool
 	conStr     string
+	ctx        context.Context // Canceled by Close to stop connecting and watching the connection.
+	cancel     context.CancelFunc
 	Connection *grpc.ClientConn
 	GrpcClient proto.PluginCtlClient
 }
 
 func NewCtlClient(name, conStr string) *CtlClient {
+	ctx, cancel := context.WithCancel(context.Background())
+
 	return &CtlClient{
 		name:   name,
 		conStr: conStr,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Starts connecting in the background unless that already happened. Returns false if it did.
+func (c *CtlClient) ConnectOnce() bool {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.started {
+		return false
+	}
+
+	c.started = true
+	go c.Connect()
+	return true
+}
+
+// State of the connection to the plugin. Shutdown if the client isn't connected yet.
+func (c *CtlClient) State() connectivity.State {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.Connection == nil {
+		return connectivity.Shutdown
 	}
+
+	return c.Connection.GetState()
+}
+
+// Client for calls to the plugin. Nil until connected.
+func (c *CtlClient) Client() proto.PluginCtlClient {
+	c.Lock()
+	defer c.Unlock()
+	return c.GrpcClient
+}
+
+// Whether calls can be made with GrpcClient.
+func (c *CtlClient) Connected() bool {
+	state := c.State()
+	return state == connectivity.Ready || state == connectivity.Idle
 }
 
 // Tries to connect to a plugin. Will sleep for 10s if it fails to connect and then try again to keep CPU usage in check.
 func (c *CtlClient) Connect() error {
 	for {
-		ctx, _ := context.WithTimeout(context.Background(), time.Second*3)
+		if c.isClosed() {
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(c.ctx, time.Second*3)
 		con, err := grpc.DialContext(ctx, c.conStr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithConnectParams(grpc.ConnectParams{
 			MinConnectTimeout: time.Second * 3,
 			Backoff:           backoff.Config{MaxDelay: time.Second * 5},
 		}), grpc.WithBlock())
+		cancel()
 
 		if err != nil {
+			if c.isClosed() {
+				return nil
+			}
+
 			golog.Errorf("%s: Failed to establish grpc connections: %v", c.name, err)
-			time.Sleep(time.Second * 10)
+
+			select {
+			case <-c.ctx.Done():
+			case <-time.After(time.Second * 10):
+			}
 			continue
 		}
 
-		go func() {
-			state := con.GetState()
-			for {
-				golog.Infof("%s: Connection state is %s", c.name, state.String())
-				con.WaitForStateChange(context.Background(), state)
-				state = con.GetState()
-			}
-		}()
+		c.Lock()
+		if c.closed {
+			c.Unlock()
+			con.Close()
+			return nil
+		}
 
 		c.Connection = con
 		c.GrpcClient = proto.NewPluginCtlClient(con)
+		c.Unlock()
+
+		go c.logStateChanges(con)
 
 		return nil
 	}
 }
+
+// Closes the connection to the plugin and stops trying to establish one.
+func (c *CtlClient) Close() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.closed = true
+	c.cancel()
+
+	if c.Connection != nil {
+		c.Connection.Close()
+	}
+}
+
+// Logs the state of the connection until it is shut down or the client is closed.
+func (c *CtlClient) logStateChanges(con *grpc.ClientConn) {
+	state := con.GetState()
+
+	for {
+		golog.Infof("%s: Connection state is %s", c.name, state.String())
+
+		if state == connectivity.Shutdown || !con.WaitForStateChange(c.ctx, state) {
+			return
+		}
+
+		state = con.GetState()
+	}
+}
+
+func (c *CtlClient) isClosed() bool {
+	c.Lock()
+	defer c.Unlock()
+	return c.closed
+}
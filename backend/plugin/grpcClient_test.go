@@ -0,0 +1,77 @@
+package plugin
+
+import (
+	"net"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// Whether a goroutine is running the given function.
+func goroutineRunning(fn string) bool {
+	buf := make([]byte, 1<<20)
+	return strings.Contains(string(buf[:runtime.Stack(buf, true)]), fn)
+}
+
+// Waits up to 5 seconds for cond to become true.
+func eventually(cond func() bool) bool {
+	deadline := time.Now().Add(time.Second * 5)
+
+	for !cond() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	return true
+}
+
+func TestCtlClientCloseStopsWatchingTheConnection(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer()
+	go server.Serve(lis)
+	defer server.Stop()
+
+	c := NewCtlClient("test", lis.Addr().String())
+	if err := c.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !eventually(func() bool { return goroutineRunning("logStateChanges") }) {
+		t.Fatal("connection isn't watched")
+	}
+
+	c.Close()
+
+	if !eventually(func() bool { return !goroutineRunning("logStateChanges") }) {
+		t.Fatal("connection is still watched after the client was closed")
+	}
+}
+
+func TestCtlClientCloseStopsConnecting(t *testing.T) {
+	// Nothing listens there, so the client keeps trying.
+	c := NewCtlClient("test", "127.0.0.1:1")
+	done := make(chan struct{})
+
+	go func() {
+		c.Connect()
+		close(done)
+	}()
+
+	time.Sleep(time.Millisecond * 100)
+	c.Close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("client still tries to connect after it was closed")
+	}
+}
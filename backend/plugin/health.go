@@ -0,0 +1,210 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/f-taxes/f-taxes/backend/applog"
+	. "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/f-taxes/f-taxes/proto"
+	"github.com/kataras/golog"
+	"google.golang.org/grpc/connectivity"
+)
+
+// Plugins are expected to send a heartbeat every few seconds.
+const degradedAfter = 15 * time.Second // A plugin whose last heartbeat is older is degraded.
+const staleAfter = 60 * time.Second    // A plugin whose last heartbeat is older is unreachable. Also the time a plugin gets to send its first heartbeat.
+const healthCheckInterval = 5 * time.Second
+
+var ErrPluginUnavailable = errors.New("plugin is unavailable")
+
+const reasonNotRunning = "plugin isn't running"
+
+type HealthState string
+
+const (
+	HEALTHY     = HealthState("healthy")
+	DEGRADED    = HealthState("degraded")    // Heartbeats are late or the connection to the plugin is being established.
+	UNREACHABLE = HealthState("unreachable") // No heartbeats anymore or the connection to the plugin failed.
+)
+
+type Health struct {
+	ID            string      `json:"id"`
+	State         HealthState `json:"state"`
+	Reason        string      `json:"reason"`
+	LastHeartbeat time.Time   `json:"lastHeartbeat"`
+	CtlState      string      `json:"ctlState,omitempty"` // State of the grpc connection to the plugin's PluginCtl server, if it has one.
+}
+
+// Last known health of each plugin. Used to push only changes to the UI.
+type healthMonitor struct {
+	sync.Mutex
+	states map[string]Health
+}
+
+var monitor = healthMonitor{states: map[string]Health{}}
+
+// Evaluates the health of a spawned plugin.
+func (p *SpawnedPlugin) health(now time.Time) Health {
+	h := Health{
+		ID:            p.Manifest.ID,
+		State:         HEALTHY,
+		LastHeartbeat: p.Manifest.LastHeartbeat,
+	}
+
+	// Before the first heartbeat the age is measured from the start of the plugin.
+	age := now.Sub(p.Manifest.LastHeartbeat)
+	if p.Manifest.LastHeartbeat.IsZero() {
+		age = now.Sub(p.StartedAt)
+	}
+
+	switch {
+	case p.Manifest.LastHeartbeat.IsZero() && age >= staleAfter:
+		h.State = UNREACHABLE
+		h.Reason = fmt.Sprintf("no heartbeat received within %s after the start", staleAfter)
+	case p.Manifest.LastHeartbeat.IsZero():
+		h.State = DEGRADED
+		h.Reason = "waiting for the first heartbeat"
+	case age >= staleAfter:
+		h.State = UNREACHABLE
+		h.Reason = fmt.Sprintf("last heartbeat was %s ago", age.Truncate(time.Second))
+	case age >= degradedAfter:
+		h.State = DEGRADED
+		h.Reason = fmt.Sprintf("last heartbeat was %s ago", age.Truncate(time.Second))
+	}
+
+	if p.Manifest.Ctl.Address == "" || h.State == UNREACHABLE {
+		return h
+	}
+
+	state := connectivity.Shutdown
+	if p.CtlClient != nil {
+		state = p.CtlClient.State()
+	}
+
+	h.CtlState = state.String()
+
+	switch state {
+	case connectivity.TransientFailure:
+		h.State = UNREACHABLE
+		h.Reason = "connection to the plugin failed"
+	case connectivity.Connecting, connectivity.Shutdown:
+		if h.State == HEALTHY {
+			h.State = DEGRADED
+			h.Reason = "connecting to the plugin"
+		}
+	}
+
+	return h
+}
+
+func (m *PluginManager) spawnedPlugins() []*SpawnedPlugin {
+	m.Lock()
+	defer m.Unlock()
+
+	out := []*SpawnedPlugin{}
+	for _, p := range m.SpawnedPlugins {
+		out = append(out, p)
+	}
+
+	return out
+}
+
+// Health of all spawned plugins.
+func (m *PluginManager) HealthList() []Health {
+	now := time.Now().UTC()
+	out := []Health{}
+
+	for _, p := range m.spawnedPlugins() {
+		m.Lock()
+		h := p.health(now)
+		m.Unlock()
+		out = append(out, h)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ID < out[j].ID
+	})
+
+	return out
+}
+
+// A plugin F-Taxes can make calls to. The client is captured when the plugin is requested,
+// so a plugin that moves its PluginCtl server meanwhile makes calls fail rather than switch clients underneath a job.
+type AvailablePlugin struct {
+	Manifest Manifest
+	Ctl      proto.PluginCtlClient
+}
+
+// Returns a plugin that F-Taxes can make calls to. Fails with ErrPluginUnavailable if the plugin isn't running,
+// is unreachable or F-Taxes isn't connected to its PluginCtl server. Degraded plugins are returned.
+func (m *PluginManager) GetAvailablePluginById(id string) (AvailablePlugin, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	p := m.GetSpawnedPluginById(id)
+
+	if p == nil {
+		return AvailablePlugin{}, fmt.Errorf("%w: %s isn't running", ErrPluginUnavailable, id)
+	}
+
+	h := p.health(time.Now().UTC())
+
+	if h.State == UNREACHABLE {
+		return AvailablePlugin{}, fmt.Errorf("%w: %s is unreachable (%s)", ErrPluginUnavailable, p.Manifest.Label, h.Reason)
+	}
+
+	if p.CtlClient == nil || !p.CtlClient.Connected() {
+		return AvailablePlugin{}, fmt.Errorf("%w: %s isn't connected yet, please try again in a moment", ErrPluginUnavailable, p.Manifest.Label)
+	}
+
+	return AvailablePlugin{Manifest: p.Manifest, Ctl: p.CtlClient.Client()}, nil
+}
+
+// Periodically evaluates the health of all plugins and pushes changes to the UI.
+func (m *PluginManager) monitorHealth() {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		m.checkHealth()
+	}
+}
+
+func (m *PluginManager) checkHealth() {
+	current := map[string]Health{}
+	for _, h := range m.HealthList() {
+		current[h.ID] = h
+	}
+
+	monitor.Lock()
+	changed := []Health{}
+
+	for id, h := range current {
+		if prev, ok := monitor.states[id]; !ok || prev.State != h.State {
+			changed = append(changed, h)
+		}
+	}
+
+	// Plugins that aren't spawned anymore, e.g. because they were stopped, are reported as unreachable.
+	for id := range monitor.states {
+		if _, ok := current[id]; !ok {
+			changed = append(changed, Health{ID: id, State: UNREACHABLE, Reason: reasonNotRunning})
+		}
+	}
+
+	monitor.states = current
+	monitor.Unlock()
+
+	for _, h := range changed {
+		if h.State == UNREACHABLE && h.Reason != reasonNotRunning {
+			applog.Send(applog.Warning, fmt.Sprintf("Plugin %s is unreachable: %s", h.ID, h.Reason), "Plugin")
+		}
+
+		golog.Infof("Plugin %s is %s %s", h.ID, h.State, h.Reason)
+		PushToClients("plugin-health", h)
+	}
+}
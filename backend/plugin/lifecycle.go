@@ -0,0 +1,129 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/kataras/golog"
+	"github.com/qiniu/qmgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const COL_PLUGINS = "plugins"
+
+// Time to wait for a plugin to exit after it was asked to stop.
+const stopTimeout = 10 * time.Second
+
+// State of an installed plugin that is managed by F-Taxes rather than the plugin's manifest.
+type pluginState struct {
+	ID          string       `bson:"_id"`
+	Enabled     bool         `bson:"enabled"`
+	Checksum    string       `bson:"checksum"`    // SHA-256 of the plugin's binary recorded when it was installed.
+	Permissions []Permission `bson:"permissions"` // Permissions the user granted the plugin. Nil if none were recorded yet.
+	Sideloaded  bool         `bson:"sideloaded"`  // The plugin was installed from an uploaded archive instead of a registry.
+}
+
+// Plugins are enabled unless they have been disabled explicitly.
+func (m *PluginManager) IsEnabled(id string) bool {
+	state := pluginState{Enabled: true}
+	err := DBConn.Collection(COL_PLUGINS).Find(context.Background(), bson.M{"_id": id}).One(&state)
+
+	if qmgo.IsErrNoDocuments(err) {
+		return true
+	}
+
+	if err != nil {
+		golog.Errorf("Failed to fetch state of plugin %s: %v", id, err)
+		return true
+	}
+
+	return state.Enabled
+}
+
+// Enables or disables a plugin. Enabling starts the plugin, disabling stops it.
+func (m *PluginManager) SetEnabled(id string, enabled bool) error {
+	if _, ok := m.findLocalManifestById(id); !ok {
+		return fmt.Errorf("plugin %s isn't installed", id)
+	}
+
+	_, err := DBConn.Collection(COL_PLUGINS).Upsert(context.Background(), bson.M{"_id": id}, bson.M{"$set": bson.M{"enabled": enabled}})
+	if err != nil {
+		return err
+	}
+
+	if enabled {
+		if m.isRunning(id) {
+			return nil
+		}
+		return m.StartPlugin(id)
+	}
+
+	return m.StopPlugin(id)
+}
+
+func (m *PluginManager) removeState(id string) {
+	m.forgetPermissions(id)
+
+	if err := DBConn.Collection(COL_PLUGINS).RemoveId(context.Background(), id); err != nil && !qmgo.IsErrNoDocuments(err) {
+		golog.Errorf("Failed to remove state of plugin %s: %v", id, err)
+	}
+}
+
+// Starts an installed plugin that isn't running.
+func (m *PluginManager) StartPlugin(id string) error {
+	manifest, ok := m.findLocalManifestById(id)
+	if !ok {
+		return fmt.Errorf("plugin %s isn't installed", id)
+	}
+
+	if !m.IsEnabled(id) {
+		return fmt.Errorf("plugin %s is disabled", id)
+	}
+
+	if manifest.NoSpawn {
+		return fmt.Errorf("plugin %s has the NoSpawn flag set and must be started manually", id)
+	}
+
+	if m.isRunning(id) {
+		return fmt.Errorf("plugin %s is already running", id)
+	}
+
+	return m.spawn(manifest)
+}
+
+// Stops a running plugin and waits for its process to exit. It won't be restarted by the supervisor.
+func (m *PluginManager) StopPlugin(id string) error {
+	if _, ok := m.findLocalManifestById(id); !ok {
+		return fmt.Errorf("plugin %s isn't installed", id)
+	}
+
+	return m.stop(id)
+}
+
+func (m *PluginManager) RestartPlugin(id string) error {
+	if err := m.StopPlugin(id); err != nil {
+		return err
+	}
+
+	return m.StartPlugin(id)
+}
+
+// Checks whether F-Taxes started a process for the plugin that hasn't exited yet.
+func (m *PluginManager) isRunning(id string) bool {
+	m.Lock()
+	defer m.Unlock()
+
+	p, ok := m.SpawnedPlugins[id]
+	if !ok || p.Cmd == nil {
+		return false
+	}
+
+	select {
+	case <-p.Cmd.Done():
+		return false
+	default:
+		return true
+	}
+}
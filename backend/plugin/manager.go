@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/go-cmd/cmd"
 
@@ -18,88 +20,177 @@ type SpawnedPlugin struct {
 	Cmd       *cmd.Cmd
 	CtlClient *CtlClient
 	Manifest  Manifest
+	StartedAt time.Time
+	Sandbox   *sandbox
+	exited    chan struct{} // Closed once the exit of the process was fully handled, including the supervisor's decision to restart it.
 }
 
 // Listens on the nats message server for plugins trying to register themselves.
 // Is also used to send messages to plugins and receive responses.
 type PluginManager struct {
 	sync.Mutex
-	Registry       string
-	RegistryFile   string // Used to available load plugins from a local file instead of downloading it from the registry. Useful for development.
-	PluginPath     string
-	GrpcAddress    string
-	SpawnedPlugins map[string]*SpawnedPlugin
+	Registries       []Registry
+	RegistryCache    string        // Directory the indexes of remote registries are cached in.
+	RegistryTTL      time.Duration // Time a cached registry index is used without asking the registry for changes.
+	Offline          bool          // Only use cached registry indexes and local registries.
+	PluginPath       string
+	GrpcAddress      string
+	TrustedKeys      []string // Minisign public keys of plugin authors whose signatures are accepted.
+	RequireChecksum  bool     // Refuse to install plugins that don't provide a checksum.
+	RequireSignature bool     // Refuse to install plugins that aren't signed by a trusted key.
+	SecretKeyFile    string   // File holding the key used to encrypt secrets in plugin configurations.
+	SideloadMaxSize  int64    // Maximum size in bytes of the files unpacked from a side-loaded archive.
+	Sandbox          bool     // Run plugins in their own data directory with a scrubbed environment and enforce their limits. Linux only.
+	SandboxEnv       []string // Environment variables sandboxed plugins may ask for in their manifest.
+	CgroupPath       string   // Delegated cgroup v2 directory to create plugin cgroups in. Only the open files limit is enforced if empty.
+	SpawnedPlugins   map[string]*SpawnedPlugin
+	supervisor       *supervisor
+	tokens           map[string]string       // Secret tokens plugins use to authenticate against the grpc server.
+	permissions      map[string][]Permission // Permissions granted to plugins, cached because they are checked on every grpc call.
+	configKey        []byte
 }
 
 func (m *PluginManager) Start() {
 	golog.Info("Starting plugin manager")
 
 	m.SpawnPlugins()
+	go m.monitorHealth()
 }
 
 func (m *PluginManager) SpawnPlugins() {
 	all := m.listLocalManifests()
 	for i := range all {
 		manifest := all[i]
-		m.spawn(manifest)
+
+		if !m.IsEnabled(manifest.ID) {
+			golog.Infof("Won't start plugin %s as it is disabled", manifest.ID)
+			continue
+		}
+
+		if err := m.spawn(manifest); err != nil {
+			applog.Send(applog.Error, fmt.Sprintf("Failed to start plugin %s (%s): %s", manifest.Label, manifest.Version, err.Error()), "Plugin")
+		}
 	}
 }
 
 func (m *PluginManager) ConnectBackToPlugin(pluginId string) {
 	if manifest, ok := m.findLocalManifestById(pluginId); ok {
-		if manifest.Ctl.Address != "" && pluginId == manifest.ID && m.SpawnedPlugins[manifest.ID] != nil {
-			m.Lock()
-			if m.SpawnedPlugins[manifest.ID].CtlClient == nil {
-				m.SpawnedPlugins[manifest.ID].CtlClient = NewCtlClient(manifest.ID, manifest.Ctl.Address)
-				go m.SpawnedPlugins[manifest.ID].CtlClient.Connect()
-			}
-			m.Unlock()
+		if manifest.Ctl.Address == "" || pluginId != manifest.ID {
+			return
+		}
+
+		m.Lock()
+		defer m.Unlock()
+
+		p := m.SpawnedPlugins[manifest.ID]
+		if p == nil {
+			return
+		}
+
+		// The address the plugin reported in its heartbeat takes precedence over the one in its manifest.
+		if p.CtlClient == nil {
+			p.CtlClient = NewCtlClient(manifest.ID, p.Manifest.Ctl.Address)
 		}
+
+		// Spawned plugins get a client right away, but it only connects once the plugin said hello.
+		p.CtlClient.ConnectOnce()
 	}
 }
 
-func (m *PluginManager) spawn(manifest Manifest) {
-	if manifest.Ctl.Address != "" {
-		pluginInstance := SpawnedPlugin{
-			Manifest: manifest,
-		}
+func (m *PluginManager) spawn(manifest Manifest) error {
+	// Plugins with a PluginCtl server get an entry right away, so F-Taxes can connect back once they said hello.
+	placeholder := &SpawnedPlugin{
+		Manifest:  manifest,
+		StartedAt: time.Now().UTC(),
+	}
 
-		// if manifest.Ctl.Address != "" {
-		// 	pluginInstance.CtlClient = NewCtlClient(manifest.ID, manifest.Ctl.Address)
-		// 	go pluginInstance.CtlClient.Connect()
-		// }
+	if manifest.Ctl.Address != "" {
+		m.Lock()
+		m.SpawnedPlugins[manifest.ID] = placeholder
+		m.Unlock()
+	}
 
+	// Removes the placeholder again if the plugin couldn't be started.
+	fail := func(err error) error {
 		m.Lock()
-		m.SpawnedPlugins[manifest.ID] = &pluginInstance
+		if m.SpawnedPlugins[manifest.ID] == placeholder {
+			delete(m.SpawnedPlugins, manifest.ID)
+		}
 		m.Unlock()
+
+		return err
 	}
 
 	if manifest.NoSpawn {
 		golog.Warnf("Won't start plugin %s. NoSpawn flag set.", manifest.ID)
-		return
+
+		if !m.hasToken(manifest.ID) {
+			token, err := m.issueToken(manifest.ID)
+			if err != nil {
+				return fail(err)
+			}
+
+			golog.Infof("Start plugin %s with the environment variables %s=%s and %s=%s to connect to F-Taxes", manifest.ID, ENV_PLUGIN_ID, manifest.ID, ENV_PLUGIN_TOKEN, token)
+		}
+		return nil
+	}
+
+	// Plugins aren't necessarily installed in a directory named after their id.
+	dir, ok := m.getPluginPath(manifest.ID)
+	if !ok {
+		return fail(fmt.Errorf("plugin %s isn't installed", manifest.ID))
+	}
+
+	if err := m.verifyInstalledBinary(manifest, dir); err != nil {
+		return fail(err)
+	}
+
+	addresses, err := allocateAddresses(manifest)
+	if err != nil {
+		return fail(err)
+	}
+
+	// Nothing may fail after the token was issued, the exit of the process revokes it.
+	token, err := m.issueToken(manifest.ID)
+	if err != nil {
+		return fail(err)
 	}
 
 	golog.Infof("Starting plugin %s", manifest.ID)
+	box := m.newSandbox(manifest, dir)
 	cmdOptions := cmd.Options{
-		Streaming: true,
-		Buffered:  false,
+		Streaming:  true,
+		Buffered:   false,
+		BeforeExec: []func(*exec.Cmd){box.beforeExec},
+	}
+	pluginCmd := cmd.NewCmdOptions(cmdOptions, box.bin, "-grpc-addr", m.GrpcAddress)
+	pluginCmd.Dir = box.dir
+	pluginCmd.Env = append(box.env, ENV_PLUGIN_ID+"="+manifest.ID, ENV_PLUGIN_TOKEN+"="+token)
+	pluginCmd.Env = append(pluginCmd.Env, addresses.env()...)
+
+	// F-Taxes picked the addresses, so they are used right away. Heartbeats can only move them if the plugin authenticated itself.
+	if addresses.ctl != "" {
+		manifest.Ctl.Address = addresses.ctl
 	}
-	pluginCmd := cmd.NewCmdOptions(cmdOptions, fmt.Sprintf(".%s%s", string(os.PathSeparator), manifest.Bin), "-grpc-addr", m.GrpcAddress)
-	pluginCmd.Dir = filepath.Join(m.PluginPath, manifest.ID)
 
-	pluginInstance := SpawnedPlugin{
-		Cmd:      pluginCmd,
-		Manifest: manifest,
+	if addresses.web != "" {
+		manifest.Web.Address = addresses.web
 	}
 
-	if manifest.Ctl.Address != "" {
-		pluginInstance.CtlClient = NewCtlClient(manifest.ID, manifest.Ctl.Address)
+	pluginInstance := SpawnedPlugin{
+		Cmd:       pluginCmd,
+		Manifest:  manifest,
+		StartedAt: time.Now().UTC(),
+		Sandbox:   box,
+		exited:    make(chan struct{}),
 	}
 
 	m.Lock()
 	m.SpawnedPlugins[manifest.ID] = &pluginInstance
 	m.Unlock()
 
+	m.supervisor.started(manifest)
+
 	doneChan := make(chan struct{})
 	go func(id string) {
 		defer close(doneChan)
@@ -119,24 +210,134 @@ func (m *PluginManager) spawn(manifest Manifest) {
 					continue
 				}
 				golog.Infof("Plugin [%s]: %s", id, line)
+				m.supervisor.stderr(id, line)
 			}
 		}
 	}(manifest.ID)
 
 	go func(manifest Manifest) {
-		// Run and wait for Cmd to return, discard Status
-		<-pluginCmd.Start()
+		defer close(pluginInstance.exited)
+
+		// Run and wait for Cmd to return
+		statusChan := pluginCmd.Start()
+		go box.apply(pluginCmd)
+		status := <-statusChan
 
 		// Wait for goroutine to print everything
 		<-doneChan
+		box.release()
+
+		m.Lock()
+		if m.SpawnedPlugins[manifest.ID] == &pluginInstance {
+			delete(m.SpawnedPlugins, manifest.ID)
+		}
+		m.Unlock()
+
+		m.revokeToken(manifest.ID, token)
 
 		m.Lock()
-		delete(m.SpawnedPlugins, manifest.ID)
+		if pluginInstance.CtlClient != nil {
+			pluginInstance.CtlClient.Close()
+		}
 		m.Unlock()
 
-		golog.Warnf("Plugin %s (%s) has exited", manifest.Label, manifest.Version)
-		applog.Send(applog.Warning, fmt.Sprintf("Plugin %s (%s) has exited", manifest.Label, manifest.Version), "Plugin")
+		golog.Warnf("Plugin %s (%s) has exited with code %d", manifest.Label, manifest.Version, status.Exit)
+
+		delay, restart := m.supervisor.exited(manifest.ID, status)
+		if !restart {
+			applog.Send(applog.Warning, fmt.Sprintf("Plugin %s (%s) has exited", manifest.Label, manifest.Version), "Plugin")
+			return
+		}
+
+		applog.Send(applog.Warning, fmt.Sprintf("Plugin %s (%s) has exited and will be restarted in %s", manifest.Label, manifest.Version, delay), "Plugin")
+		time.AfterFunc(delay, func() {
+			m.restart(manifest.ID)
+		})
 	}(manifest)
+
+	return nil
+}
+
+// Starts a plugin again after it exited. The manifest is read again, as the plugin might have been updated or removed meanwhile.
+func (m *PluginManager) restart(id string) {
+	if m.supervisor.isStopRequested(id) || !m.IsEnabled(id) {
+		return
+	}
+
+	manifest, ok := m.findLocalManifestById(id)
+	if !ok {
+		golog.Warnf("Won't restart plugin %s as it is no longer installed", id)
+		return
+	}
+
+	m.Lock()
+	_, running := m.SpawnedPlugins[id]
+	m.Unlock()
+
+	if running {
+		return
+	}
+
+	if err := m.spawn(manifest); err != nil {
+		applog.Send(applog.Error, fmt.Sprintf("Failed to restart plugin %s (%s): %s", manifest.Label, manifest.Version, err.Error()), "Plugin")
+	}
+}
+
+// Stops the process of a plugin without restarting it. The connection to the plugin is closed first,
+// then the process is asked to terminate and awaited.
+func (m *PluginManager) stop(id string) error {
+	m.supervisor.requestStop(id)
+
+	m.Lock()
+	p, ok := m.SpawnedPlugins[id]
+	m.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	m.Lock()
+	if p.CtlClient != nil {
+		p.CtlClient.Close()
+	}
+	m.Unlock()
+
+	if p.Cmd == nil {
+		return nil
+	}
+
+	if err := p.Cmd.Stop(); err != nil && err != cmd.ErrNotStarted {
+		return err
+	}
+
+	// Waiting for the exit to be handled keeps it from being attributed to a process that is started right after.
+	select {
+	case <-p.exited:
+		return nil
+	case <-time.After(stopTimeout):
+		return fmt.Errorf("plugin %s didn't exit within %s", id, stopTimeout)
+	}
+}
+
+// Status of the processes of all plugins, including the resources used by running ones.
+func (m *PluginManager) ProcessStatus() []ProcessStatus {
+	list := m.supervisor.list()
+
+	for i := range list {
+		if list[i].State != PROCESS_RUNNING {
+			continue
+		}
+
+		m.Lock()
+		p := m.GetSpawnedPluginById(list[i].ID)
+		m.Unlock()
+
+		if p != nil && p.Sandbox != nil {
+			list[i].Usage = p.Sandbox.usage()
+		}
+	}
+
+	return list
 }
 
 func (m *PluginManager) listLocalManifests() []Manifest {
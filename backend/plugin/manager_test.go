@@ -0,0 +1,159 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/f-taxes/f-taxes/pluginsdk"
+	"github.com/f-taxes/f-taxes/proto"
+	"github.com/kataras/iris/v12"
+	"github.com/qiniu/qmgo"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Set for the copy of the test binary that tests spawn as a plugin.
+const envTestPlugin = "FTAXES_TEST_PLUGIN"
+
+// MongoDB tests that need a database connect to, e.g. mongodb://localhost:27017. They are skipped if it isn't set.
+const envTestMongo = "FTAXES_TEST_MONGO"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(envTestPlugin) != "" {
+		runTestPlugin()
+		return
+	}
+
+	os.Exit(m.Run())
+}
+
+// A plugin whose PluginCtl server answers conversions with the address it listens on.
+func runTestPlugin() {
+	p := pluginsdk.New("", "1.0.0")
+	p.CtlAddress = "127.0.0.1:1"
+
+	p.OnConvertTrade = func(ctx context.Context, job *proto.TradeConversionJob) (*proto.Trade, error) {
+		job.Trade.Comment = p.CtlListenAddress()
+		return job.Trade, nil
+	}
+
+	if err := p.Run(context.Background()); err != nil {
+		os.Exit(1)
+	}
+}
+
+// Connects DBConn to a fresh database that is dropped after the test.
+func connectTestDB(t *testing.T) {
+	uri := os.Getenv(envTestMongo)
+	if uri == "" {
+		t.Skipf("%s isn't set", envTestMongo)
+	}
+
+	client, err := qmgo.NewClient(context.Background(), &qmgo.Config{Uri: uri})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	DBConn = client.Database("f-taxes-test-" + primitive.NewObjectID().Hex())
+
+	t.Cleanup(func() {
+		DBConn.DropDatabase(context.Background())
+		client.Close(context.Background())
+	})
+}
+
+// Installs the test binary as a plugin with a PluginCtl server. The directory deliberately isn't named after the plugin's id.
+func installTestPlugin(t *testing.T, pluginPath, id string) Manifest {
+	manifest := Manifest{ID: id, Label: id, Version: "1.0.0", Bin: "plugin"}
+	manifest.Ctl.Address = "127.0.0.1:1"
+
+	dir := filepath.Join(pluginPath, "installed", id+"-1.0.0")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := json.Marshal(manifest)
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bin, err := os.ReadFile(self)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, manifest.Bin), bin, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	return manifest
+}
+
+func TestSpawnUsesAllocatedAddresses(t *testing.T) {
+	connectTestDB(t)
+	SetupWebsocketServer(iris.New())
+	t.Setenv(envTestPlugin, "1")
+
+	m := &PluginManager{
+		PluginPath:     t.TempDir(),
+		GrpcAddress:    "127.0.0.1:1",
+		SpawnedPlugins: map[string]*SpawnedPlugin{},
+		supervisor:     newSupervisor(),
+		tokens:         map[string]string{},
+		permissions:    map[string][]Permission{},
+	}
+
+	manifest := installTestPlugin(t, m.PluginPath, "test_spawn")
+
+	if err := m.spawn(manifest); err != nil {
+		t.Fatal(err)
+	}
+	defer m.stop(manifest.ID)
+
+	m.Lock()
+	allocated := m.SpawnedPlugins[manifest.ID].Manifest.Ctl.Address
+	m.Unlock()
+
+	if allocated == manifest.Ctl.Address {
+		t.Fatalf("spawned plugin still uses the address of its manifest")
+	}
+
+	// Auth is off, so no heartbeat reports the address. F-Taxes has to use the one it picked.
+	m.ConnectBackToPlugin(manifest.ID)
+
+	deadline := time.Now().Add(time.Second * 10)
+	for {
+		p, err := m.GetAvailablePluginById(manifest.ID)
+
+		if err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+			trade, err := p.Ctl.ConvertPricesInTrade(ctx, &proto.TradeConversionJob{Trade: &proto.Trade{TxID: "1"}})
+			cancel()
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if trade.Comment != allocated {
+				t.Fatalf("plugin listens on %s, F-Taxes allocated %s", trade.Comment, allocated)
+			}
+
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("ctl client didn't connect to %s: %v", allocated, err)
+		}
+
+		time.Sleep(time.Millisecond * 50)
+	}
+}
@@ -0,0 +1,102 @@
+package plugin
+
+import (
+	"context"
+	"slices"
+
+	. "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/kataras/golog"
+	"github.com/qiniu/qmgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type Permission string
+
+const (
+	PERM_RECORDS_READ   = Permission("records:read")   // Read trades and transfers.
+	PERM_RECORDS_WRITE  = Permission("records:write")  // Submit new trades and transfers.
+	PERM_RECORDS_UPDATE = Permission("records:update") // Change existing trades and transfers.
+	PERM_SETTINGS_READ  = Permission("settings:read")  // Read the user's settings.
+	PERM_APPLOG_WRITE   = Permission("applog:write")   // Show messages in the app's log.
+	PERM_JOBS_REPORT    = Permission("jobs:report")    // Show the progress of jobs.
+)
+
+var AllPermissions = []Permission{PERM_RECORDS_READ, PERM_RECORDS_WRITE, PERM_RECORDS_UPDATE, PERM_SETTINGS_READ, PERM_APPLOG_WRITE, PERM_JOBS_REPORT}
+
+// Permissions of plugins whose manifest predates permissions. Changing existing records was added later, so they can't have needed it.
+var LegacyPermissions = []Permission{PERM_RECORDS_READ, PERM_RECORDS_WRITE, PERM_SETTINGS_READ, PERM_APPLOG_WRITE, PERM_JOBS_REPORT}
+
+// Returns the permissions a plugin asks for. Manifests that don't declare any permissions get LegacyPermissions.
+func (m Manifest) RequestedPermissions() []Permission {
+	if m.Permissions == nil {
+		return LegacyPermissions
+	}
+
+	return m.Permissions
+}
+
+// Remembers the permissions the user granted when installing or updating the plugin.
+// Later changes to the manifest on disk don't grant a plugin any more permissions.
+func (m *PluginManager) grantPermissions(id string, permissions []Permission) error {
+	_, err := DBConn.Collection(COL_PLUGINS).Upsert(context.Background(), bson.M{"_id": id}, bson.M{"$set": bson.M{"permissions": permissions}})
+	if err != nil {
+		m.forgetPermissions(id)
+		return err
+	}
+
+	m.Lock()
+	m.permissions[id] = slices.Clone(permissions)
+	m.Unlock()
+
+	return nil
+}
+
+func (m *PluginManager) forgetPermissions(id string) {
+	m.Lock()
+	delete(m.permissions, id)
+	m.Unlock()
+}
+
+func (m *PluginManager) grantedPermissions(id string) []Permission {
+	m.Lock()
+	cached, ok := m.permissions[id]
+	m.Unlock()
+
+	if ok {
+		return cached
+	}
+
+	state := pluginState{}
+	err := DBConn.Collection(COL_PLUGINS).Find(context.Background(), bson.M{"_id": id}).One(&state)
+
+	if err != nil && !qmgo.IsErrNoDocuments(err) {
+		golog.Errorf("Failed to fetch permissions of plugin %s: %v", id, err)
+		return nil
+	}
+
+	if state.Permissions != nil {
+		m.Lock()
+		m.permissions[id] = state.Permissions
+		m.Unlock()
+
+		return state.Permissions
+	}
+
+	// Plugins installed before permissions were recorded keep the permissions of their installed manifest.
+	manifest, ok := m.findLocalManifestById(id)
+	if !ok {
+		return nil
+	}
+
+	// Cached by grantPermissions.
+	permissions := manifest.RequestedPermissions()
+	if err := m.grantPermissions(id, permissions); err != nil {
+		golog.Errorf("Failed to record permissions of plugin %s: %v", id, err)
+	}
+
+	return permissions
+}
+
+func (m *PluginManager) HasPermission(id string, permission Permission) bool {
+	return slices.Contains(m.grantedPermissions(id), permission)
+}
@@ -1,6 +1,9 @@
 package plugin
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type PluginStatus int
 
@@ -21,34 +24,71 @@ type Social struct {
 }
 
 type DlInfo struct {
-	Windows string `json:"windows"`
-	Linux   string `json:"linux"`
-	Darwin  string `json:"darwin"`
+	Windows   string       `json:"windows"`
+	Linux     string       `json:"linux"`
+	Darwin    string       `json:"darwin"`
+	Sha256    PerOSStrings `json:"sha256"`    // Hex encoded SHA-256 checksums of the plugin's binary for each operating system.
+	Signature PerOSStrings `json:"signature"` // Optional minisign signatures (contents of the .minisig file) of the plugin's binary for each operating system.
+}
+
+type PerOSStrings struct {
+	Windows string `json:"windows,omitempty"`
+	Linux   string `json:"linux,omitempty"`
+	Darwin  string `json:"darwin,omitempty"`
+}
+
+func (p PerOSStrings) ForOS(goos string) string {
+	switch goos {
+	case "linux":
+		return p.Linux
+	case "darwin":
+		return p.Darwin
+	}
+
+	return p.Windows
+}
+
+func (d DlInfo) ChecksumForOS(goos string) string {
+	return d.Sha256.ForOS(goos)
+}
+
+func (d DlInfo) SignatureForOS(goos string) string {
+	return d.Signature.ForOS(goos)
 }
 
 type Web struct {
-	Address    string `json:"address"` // Address that the plugin's web server is listening on."
-	ConfigPage string `json:"configPage,omitempty"`
+	Address    string `json:"address"`              // Default address of the plugin's web server. F-Taxes passes a free one in FTAXES_WEB_ADDRESS and uses it, unless an authenticated plugin reports another one in its heartbeat.
+	ConfigPage string `json:"configPage,omitempty"` // Path of the config page. The UI loads it through F-Taxes at /plugins/{id}/ui{configPage}.
 	ReportPage string `json:"reportPage,omitempty"`
 }
 
 type Ctl struct {
-	Address string `json:"address"` // Address for the web server to listen on.
+	Address string `json:"address"` // Default address of the plugin's PluginCtl server. F-Taxes passes a free one in FTAXES_CTL_ADDRESS and uses it, unless an authenticated plugin reports another one in its heartbeat.
 }
 
 type Manifest struct {
-	ID            string       `json:"id"`            // Unique ID of the plugin.
-	Type          string       `json:"type"`          // Type of the plugin. Currently only "source" is supported.
-	Label         string       `json:"label"`         // Name of the plugin as presented in the apps UI.
-	Author        Author       `json:"author"`        // Author of the plugin. Can include a social media link as well. See Author struct.
-	Version       string       `json:"version"`       // Version of the plugin.
-	Icon          string       `json:"icon"`          // Icon to show in the plugin section and else where if needed.
-	Bin           string       `json:"bin"`           // Name of the binary that f-taxes should start (must be the same for each operating system. The file extension should be omitted here. F-Taxes will add ".exe" on windows automatically).
-	NoSpawn       bool         `json:"noSpawn"`       // If true, F-Taxes won't try to spawn the plugin. Useful to run a plugin manually for development.
-	Repository    string       `json:"repository"`    // Url of the repository with the plugin's source code.
-	Download      DlInfo       `json:"download"`      // List of download urls. Should supply one for each operating system if possible.
-	Web           Web          `json:"web"`           // If set F-Taxes will allow the plugin to display a web ui.
-	Ctl           Ctl          `json:"ctl"`           // Settings for the plugin's grpc server that allows for control via F-Taxes.
-	Status        PluginStatus `json:"status"`        // Status of the plugin. Possible states are "installed", "not installed" and "update available".
-	LastHeartbeat time.Time    `json:"lastHeartbeat"` // Last time a heartbeat was received from the plugin.
+	ID            string          `json:"id"`                      // Unique ID of the plugin.
+	Type          string          `json:"type"`                    // Type of the plugin. Currently only "source" is supported.
+	Label         string          `json:"label"`                   // Name of the plugin as presented in the apps UI.
+	Author        Author          `json:"author"`                  // Author of the plugin. Can include a social media link as well. See Author struct.
+	Version       string          `json:"version"`                 // Version of the plugin.
+	Icon          string          `json:"icon"`                    // Icon to show in the plugin section and else where if needed.
+	Bin           string          `json:"bin"`                     // Name of the binary that f-taxes should start (must be the same for each operating system. The file extension should be omitted here. F-Taxes will add ".exe" on windows automatically).
+	NoSpawn       bool            `json:"noSpawn"`                 // If true, F-Taxes won't try to spawn the plugin. Useful to run a plugin manually for development.
+	Repository    string          `json:"repository"`              // Url of the repository with the plugin's source code.
+	Download      DlInfo          `json:"download"`                // List of download urls. Should supply one for each operating system if possible.
+	Web           Web             `json:"web"`                     // If set F-Taxes will allow the plugin to display a web ui.
+	Ctl           Ctl             `json:"ctl"`                     // Settings for the plugin's grpc server that allows for control via F-Taxes.
+	Status        PluginStatus    `json:"status"`                  // Status of the plugin. Possible states are "installed", "not installed" and "update available".
+	LastHeartbeat time.Time       `json:"lastHeartbeat"`           // Last time a heartbeat was received from the plugin.
+	Restart       RestartPolicy   `json:"restart"`                 // Whether and how often F-Taxes restarts the plugin after it exited.
+	Limits        Limits          `json:"limits"`                  // Memory, CPU and open files the plugin may use. Only enforced on Linux.
+	Env           []string        `json:"env,omitempty"`           // Names of environment variables the plugin needs on top of the ones F-Taxes passes to sandboxed plugins. Only passed on if allowed by "plugins.sandboxEnv".
+	Permissions   []Permission    `json:"permissions"`             // Permissions the plugin needs, e.g. "records:read". Plugins without this field get all but "records:update".
+	Enabled       bool            `json:"enabled"`                 // Whether the plugin is enabled. Managed by F-Taxes, not read from the manifest file.
+	ConfigSchema  json.RawMessage `json:"configSchema,omitempty"`  // JSON Schema of the plugin's configuration. The UI renders a form from it. Properties marked "writeOnly" or with the format "password" are stored encrypted.
+	ReportMethods []string        `json:"reportMethods,omitempty"` // Cost basis methods, e.g. "FIFO", a report plugin supports. Plugins that list any implement PluginCtl.GenerateReport.
+	Registry      string          `json:"registry,omitempty"`      // Name of the registry the manifest was loaded from. Set by F-Taxes.
+	RegistryTrust TrustLevel      `json:"registryTrust,omitempty"` // Trust level of that registry. Set by F-Taxes.
+	Local         bool            `json:"local,omitempty"`         // The plugin was side-loaded from an archive instead of installed from a registry. Set by F-Taxes.
 }
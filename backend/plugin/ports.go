@@ -0,0 +1,123 @@
+package plugin
+
+import (
+	"net"
+
+	"github.com/kataras/golog"
+)
+
+// Environment variables used to hand a spawned plugin free addresses for its PluginCtl and web servers.
+// Plugins that ignore them listen on the addresses in their manifest instead.
+const ENV_CTL_ADDRESS = "FTAXES_CTL_ADDRESS"
+const ENV_WEB_ADDRESS = "FTAXES_WEB_ADDRESS"
+
+// Addresses picked for the servers of a spawned plugin. Empty if the plugin doesn't declare the server.
+type allocatedAddresses struct {
+	ctl string
+	web string
+}
+
+// Environment variables to pass the addresses on to the plugin.
+func (a allocatedAddresses) env() []string {
+	env := []string{}
+
+	if a.ctl != "" {
+		env = append(env, ENV_CTL_ADDRESS+"="+a.ctl)
+	}
+
+	if a.web != "" {
+		env = append(env, ENV_WEB_ADDRESS+"="+a.web)
+	}
+
+	return env
+}
+
+// Picks free ports for the servers the plugin declares in its manifest.
+// Both listeners are held until all ports are picked, so a plugin never gets the same port twice.
+func allocateAddresses(manifest Manifest) (allocatedAddresses, error) {
+	addresses := allocatedAddresses{}
+	listeners := []net.Listener{}
+
+	defer func() {
+		for _, l := range listeners {
+			l.Close()
+		}
+	}()
+
+	for _, server := range []struct {
+		declared  string
+		allocated *string
+	}{
+		{manifest.Ctl.Address, &addresses.ctl},
+		{manifest.Web.Address, &addresses.web},
+	} {
+		if server.declared == "" {
+			continue
+		}
+
+		lis, err := net.Listen("tcp", net.JoinHostPort(listenHost(server.declared), "0"))
+		if err != nil {
+			return allocatedAddresses{}, err
+		}
+
+		listeners = append(listeners, lis)
+		*server.allocated = lis.Addr().String()
+	}
+
+	return addresses, nil
+}
+
+// Host of the declared address. Plugins only listen locally unless their manifest says otherwise.
+func listenHost(declared string) string {
+	host, _, err := net.SplitHostPort(declared)
+	if err != nil || host == "" {
+		return "127.0.0.1"
+	}
+
+	return host
+}
+
+// Takes note of the addresses an authenticated plugin reported in its heartbeat. A plugin that moved its PluginCtl server,
+// e.g. because it was restarted outside of F-Taxes, gets a new client.
+func (m *PluginManager) UpdateAddresses(id, ctlAddress, webAddress string) {
+	m.Lock()
+	defer m.Unlock()
+
+	p := m.GetSpawnedPluginById(id)
+	if p == nil {
+		return
+	}
+
+	if webAddress != "" {
+		p.Manifest.Web.Address = webAddress
+	}
+
+	if ctlAddress == "" || ctlAddress == p.Manifest.Ctl.Address {
+		return
+	}
+
+	golog.Infof("Plugin %s listens on %s", id, ctlAddress)
+	p.Manifest.Ctl.Address = ctlAddress
+
+	if p.CtlClient != nil {
+		p.CtlClient.Close()
+		p.CtlClient = nil
+	}
+}
+
+// Address of the plugin's web server. Prefers the address the running plugin reported over its manifest.
+func (m *PluginManager) webAddress(id string) string {
+	m.Lock()
+	if p := m.GetSpawnedPluginById(id); p != nil {
+		addr := p.Manifest.Web.Address
+		m.Unlock()
+		return addr
+	}
+	m.Unlock()
+
+	if manifest, ok := m.findLocalManifestById(id); ok {
+		return manifest.Web.Address
+	}
+
+	return ""
+}
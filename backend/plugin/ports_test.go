@@ -0,0 +1,47 @@
+package plugin
+
+import (
+	"net"
+	"slices"
+	"testing"
+)
+
+func TestAllocateAddresses(t *testing.T) {
+	manifest := Manifest{ID: "test"}
+	manifest.Ctl.Address = "127.0.0.1:4300"
+	manifest.Web.Address = "localhost:4301"
+
+	addresses, err := allocateAddresses(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if addresses.ctl == "" || addresses.web == "" || addresses.ctl == addresses.web {
+		t.Fatalf("unexpected addresses %+v", addresses)
+	}
+
+	for _, addr := range []string{addresses.ctl, addresses.web} {
+		if _, port, err := net.SplitHostPort(addr); err != nil || port == "0" || port == "4300" || port == "4301" {
+			t.Fatalf("%s isn't a freshly picked port: %v", addr, err)
+		}
+	}
+
+	env := addresses.env()
+	if !slices.Contains(env, ENV_CTL_ADDRESS+"="+addresses.ctl) || !slices.Contains(env, ENV_WEB_ADDRESS+"="+addresses.web) {
+		t.Fatalf("addresses aren't passed on in %v", env)
+	}
+}
+
+func TestAllocateAddressesOnlyForDeclaredServers(t *testing.T) {
+	manifest := Manifest{ID: "test"}
+	manifest.Web.Address = "127.0.0.1:4301"
+
+	addresses, err := allocateAddresses(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if addresses.ctl != "" || addresses.web == "" || len(addresses.env()) != 1 {
+		t.Fatalf("unexpected addresses %+v", addresses)
+	}
+}
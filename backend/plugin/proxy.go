@@ -0,0 +1,129 @@
+package plugin
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kataras/golog"
+	"github.com/kataras/iris/v12"
+)
+
+// Path under which the web ui of a plugin is served by F-Taxes.
+func UIPrefix(id string) string {
+	return fmt.Sprintf("/plugins/%s/ui", id)
+}
+
+// Largest html page of a plugin that is rewritten. Larger pages are passed through as they are.
+const maxRewrittenPage = 10 << 20
+
+var htmlHeadPattern = regexp.MustCompile(`(?i)<head(\s[^>]*)?>`)
+var htmlBasePattern = regexp.MustCompile(`(?i)<base\s`)
+var htmlRootLinkPattern = regexp.MustCompile(`(?i)(\s(?:src|href|action)\s*=\s*["'])(/[^"'/][^"']*|/)(["'])`)
+
+// Forwards requests below UIPrefix to the web server of the plugin, including websocket connections.
+// The prefix is stripped before the request reaches the plugin. Plugins that need to build absolute links
+// can read it from the X-Forwarded-Prefix header. Html pages get a <base> element and their links to
+// absolute paths are moved below the prefix, so plugins written for serving at "/" work unchanged.
+// The route is registered on the main app, so every middleware that guards the app applies to the plugin uis as well.
+func (m *PluginManager) proxyUI(ctx iris.Context) {
+	id := ctx.Params().Get("id")
+	address := m.webAddress(id)
+
+	if address == "" {
+		ctx.StopWithStatus(http.StatusNotFound)
+		return
+	}
+
+	target, err := url.Parse("http://" + address)
+	if err != nil {
+		golog.Errorf("Invalid web address of plugin %s: %v", id, err)
+		ctx.StopWithStatus(http.StatusBadGateway)
+		return
+	}
+
+	prefix := UIPrefix(id)
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.Out.URL.Path = "/" + strings.TrimPrefix(strings.TrimPrefix(pr.In.URL.Path, prefix), "/")
+			pr.Out.URL.RawPath = ""
+			pr.SetURL(target)
+			pr.SetXForwarded()
+			pr.Out.Header.Set("X-Forwarded-Prefix", prefix)
+			// Html pages are rewritten, so they must arrive uncompressed. The transport still compresses and unpacks them transparently.
+			pr.Out.Header.Del("Accept-Encoding")
+		},
+		ModifyResponse: func(resp *http.Response) error {
+			// Keeps redirects of the plugin to its own absolute paths within the prefix.
+			if loc := resp.Header.Get("Location"); strings.HasPrefix(loc, "/") && !strings.HasPrefix(loc, "//") {
+				resp.Header.Set("Location", prefix+loc)
+			}
+			return rewriteHTML(resp, prefix)
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			golog.Errorf("Failed to reach web ui of plugin %s: %v", id, err)
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+
+	// The proxied response is passed through as is. Compressing it again would break websocket upgrades.
+	// The headers set by the compression middleware are only dropped at the end of the request, so they are removed here.
+	ctx.CompressWriter(false)
+	ctx.ResponseWriter().Header().Del("Content-Encoding")
+	ctx.ResponseWriter().Header().Del("Vary")
+	proxy.ServeHTTP(ctx.ResponseWriter(), ctx.Request())
+}
+
+// Adds a <base> element pointing at the prefix to html pages and moves links to absolute paths below it.
+func rewriteHTML(resp *http.Response, prefix string) error {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" || resp.Header.Get("Content-Encoding") != "" || resp.ContentLength > maxRewrittenPage {
+		return nil
+	}
+
+	page, err := io.ReadAll(io.LimitReader(resp.Body, maxRewrittenPage+1))
+	if err != nil {
+		return err
+	}
+
+	if len(page) > maxRewrittenPage {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(page), resp.Body), resp.Body}
+		return nil
+	}
+
+	resp.Body.Close()
+
+	html := htmlRootLinkPattern.ReplaceAllStringFunc(string(page), func(attr string) string {
+		m := htmlRootLinkPattern.FindStringSubmatch(attr)
+		if m[2] == prefix || strings.HasPrefix(m[2], prefix+"/") {
+			return attr
+		}
+		return m[1] + prefix + m[2] + m[3]
+	})
+
+	if !htmlBasePattern.MatchString(html) {
+		base := fmt.Sprintf(`<base href="%s/">`, prefix)
+
+		if loc := htmlHeadPattern.FindStringIndex(html); loc != nil {
+			html = html[:loc[1]] + base + html[loc[1]:]
+		} else {
+			html = base + html
+		}
+	}
+
+	resp.Body = io.NopCloser(strings.NewReader(html))
+	resp.ContentLength = int64(len(html))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(html)))
+	return nil
+}
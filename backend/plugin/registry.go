@@ -0,0 +1,361 @@
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	. "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/kataras/golog"
+	"github.com/knadh/koanf"
+)
+
+const registryTimeout = 10 * time.Second
+
+var ErrNoRegistry = errors.New("none of the plugin registries could be loaded")
+
+type TrustLevel string
+
+const (
+	TRUST_TRUSTED   = TrustLevel("trusted")   // Plugins are checked according to "plugins.requireChecksum" and "plugins.requireSignature".
+	TRUST_COMMUNITY = TrustLevel("community") // Plugins must provide a checksum.
+	TRUST_UNTRUSTED = TrustLevel("untrusted") // Plugins must provide a checksum and be signed by a trusted key.
+)
+
+// A source of plugin manifests. Either a url serving a json list of manifests or a local path.
+// A local path is either such a list or a directory that is searched for manifest.json files.
+type Registry struct {
+	Name     string     `koanf:"name" json:"name"`
+	URL      string     `koanf:"url" json:"url,omitempty"`
+	Path     string     `koanf:"path" json:"path,omitempty"`
+	Priority int        `koanf:"priority" json:"priority"` // If several registries offer the same plugin, the one with the highest priority wins.
+	Trust    TrustLevel `koanf:"trust" json:"trust"`       // Defaults to "trusted".
+}
+
+func (r Registry) trust() TrustLevel {
+	switch r.Trust {
+	case TRUST_COMMUNITY, TRUST_UNTRUSTED:
+		return r.Trust
+	}
+
+	return TRUST_TRUSTED
+}
+
+// Result of the last attempt to load a registry.
+type RegistryStatus struct {
+	Registry
+	Plugins   int       `json:"plugins"`
+	FetchedAt time.Time `json:"fetchedAt"`       // Time the index was last downloaded. Zero for local registries.
+	Cached    bool      `json:"cached"`          // The index was served from the cache.
+	Error     string    `json:"error,omitempty"` // Why the registry couldn't be loaded or refreshed.
+}
+
+// Meta data of a cached registry index.
+type registryCacheMeta struct {
+	ETag         string    `json:"etag"`
+	LastModified string    `json:"lastModified"`
+	FetchedAt    time.Time `json:"fetchedAt"`
+}
+
+var registryLock sync.Mutex
+var registryStatus = map[string]RegistryStatus{}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// Reads the registries from "plugins.registries". Configurations that predate it only have a single registry,
+// which is read from "plugins.registryFile" if set, or "plugins.registry".
+func registriesFromConfig(cfg *koanf.Koanf) []Registry {
+	registries := []Registry{}
+
+	if err := cfg.Unmarshal("plugins.registries", &registries); err != nil {
+		golog.Errorf("Invalid plugin registries in configuration: %v", err)
+	}
+
+	if len(registries) > 0 {
+		for i := range registries {
+			if registries[i].Name == "" {
+				registries[i].Name = fmt.Sprintf("registry-%d", i+1)
+			}
+		}
+
+		return registries
+	}
+
+	if file := cfg.String("plugins.registryFile"); file != "" {
+		return []Registry{{Name: "local", Path: file, Trust: TRUST_TRUSTED}}
+	}
+
+	if url := cfg.String("plugins.registry"); url != "" {
+		return []Registry{{Name: "official", URL: url, Trust: TRUST_TRUSTED}}
+	}
+
+	return registries
+}
+
+// Loads the manifests of all registries and merges them. Registries that can't be loaded are skipped,
+// an error is only returned if none could be loaded.
+func (m *PluginManager) dlIndex() ([]Manifest, error) {
+	registries := append([]Registry{}, m.Registries...)
+	sort.SliceStable(registries, func(i, j int) bool {
+		return registries[i].Priority > registries[j].Priority
+	})
+
+	list := []Manifest{}
+	seen := map[string]bool{}
+	loaded := 0
+	var lastErr error
+
+	for _, r := range registries {
+		manifests, err := m.loadRegistry(r)
+		if err != nil {
+			golog.Errorf("Failed to load plugin registry %s: %v", r.Name, err)
+			lastErr = err
+			continue
+		}
+
+		loaded++
+
+		for _, manifest := range manifests {
+			if seen[manifest.ID] {
+				continue
+			}
+
+			seen[manifest.ID] = true
+			manifest.Registry = r.Name
+			manifest.RegistryTrust = r.trust()
+			list = append(list, manifest)
+		}
+	}
+
+	if loaded == 0 && len(registries) > 0 {
+		return list, fmt.Errorf("%w: %v", ErrNoRegistry, lastErr)
+	}
+
+	return list, nil
+}
+
+// Status of all registries as of the last time they were loaded.
+func (m *PluginManager) RegistryStatus() []RegistryStatus {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	out := []RegistryStatus{}
+	for _, r := range m.Registries {
+		status, ok := registryStatus[r.Name]
+		if !ok {
+			status = RegistryStatus{Registry: r}
+		}
+
+		out = append(out, status)
+	}
+
+	return out
+}
+
+func (m *PluginManager) loadRegistry(r Registry) ([]Manifest, error) {
+	status := RegistryStatus{Registry: r}
+	var manifests []Manifest
+	var err error
+
+	if r.Path != "" {
+		manifests, err = readLocalRegistry(r.Path)
+	} else {
+		manifests, status, err = m.fetchRegistry(r)
+	}
+
+	if err != nil {
+		status.Error = err.Error()
+	}
+
+	status.Plugins = len(manifests)
+
+	registryLock.Lock()
+	registryStatus[r.Name] = status
+	registryLock.Unlock()
+
+	return manifests, err
+}
+
+func readLocalRegistry(path string) ([]Manifest, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		list := []Manifest{}
+		return list, json.Unmarshal(contents, &list)
+	}
+
+	list := []Manifest{}
+	for _, p := range FindFileByName("manifest.json", path) {
+		contents, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+
+		manifest := Manifest{}
+		if err := json.Unmarshal(contents, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", p, err)
+		}
+
+		list = append(list, manifest)
+	}
+
+	return list, nil
+}
+
+// Returns the index of a remote registry. The cached index is used while it's younger than RegistryTTL,
+// when F-Taxes is in offline mode or the registry can't be reached. Otherwise it is revalidated with ETag and Last-Modified.
+func (m *PluginManager) fetchRegistry(r Registry) ([]Manifest, RegistryStatus, error) {
+	status := RegistryStatus{Registry: r}
+	cachePath := filepath.Join(m.RegistryCache, unsafeFileChars.ReplaceAllString(r.Name, "_"))
+	meta, cached, cacheErr := readRegistryCache(cachePath)
+
+	status.FetchedAt = meta.FetchedAt
+
+	useCache := func(reason error) ([]Manifest, RegistryStatus, error) {
+		if cacheErr != nil {
+			if reason != nil {
+				return nil, status, reason
+			}
+			return nil, status, cacheErr
+		}
+
+		status.Cached = true
+		if reason != nil {
+			status.Error = reason.Error()
+			golog.Warnf("Using cached index of plugin registry %s: %v", r.Name, reason)
+		}
+
+		return cached, status, nil
+	}
+
+	if m.Offline {
+		if cacheErr != nil {
+			return nil, status, errors.New("offline mode is enabled and the registry's index isn't cached")
+		}
+
+		return useCache(nil)
+	}
+
+	if cacheErr == nil && time.Since(meta.FetchedAt) < m.RegistryTTL {
+		return useCache(nil)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, r.URL, nil)
+	if err != nil {
+		return nil, status, err
+	}
+
+	if cacheErr == nil {
+		if meta.ETag != "" {
+			req.Header.Set("If-None-Match", meta.ETag)
+		}
+
+		if meta.LastModified != "" {
+			req.Header.Set("If-Modified-Since", meta.LastModified)
+		}
+	}
+
+	client := http.Client{Timeout: registryTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return useCache(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cacheErr == nil {
+		meta.FetchedAt = time.Now().UTC()
+		status.FetchedAt = meta.FetchedAt
+
+		if err := writeRegistryMeta(cachePath, meta); err != nil {
+			golog.Errorf("Failed to update cache of plugin registry %s: %v", r.Name, err)
+		}
+
+		return useCache(nil)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return useCache(fmt.Errorf("registry responded with status %s", resp.Status))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return useCache(err)
+	}
+
+	list := []Manifest{}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return useCache(fmt.Errorf("invalid registry index: %w", err))
+	}
+
+	meta = registryCacheMeta{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FetchedAt:    time.Now().UTC(),
+	}
+
+	status.FetchedAt = meta.FetchedAt
+
+	if err := writeRegistryCache(cachePath, body, meta); err != nil {
+		golog.Errorf("Failed to cache index of plugin registry %s: %v", r.Name, err)
+	}
+
+	return list, status, nil
+}
+
+func readRegistryCache(path string) (registryCacheMeta, []Manifest, error) {
+	meta := registryCacheMeta{}
+	list := []Manifest{}
+
+	contents, err := os.ReadFile(path + ".json")
+	if err != nil {
+		return meta, nil, err
+	}
+
+	if err := json.Unmarshal(contents, &list); err != nil {
+		return meta, nil, err
+	}
+
+	// The index is still usable without its meta data, it's just revalidated right away.
+	if contents, err := os.ReadFile(path + ".meta.json"); err == nil {
+		json.Unmarshal(contents, &meta)
+	}
+
+	return meta, list, nil
+}
+
+func writeRegistryCache(path string, index []byte, meta registryCacheMeta) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path+".json", index, 0644); err != nil {
+		return err
+	}
+
+	return writeRegistryMeta(path, meta)
+}
+
+func writeRegistryMeta(path string, meta registryCacheMeta) error {
+	contents, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path+".meta.json", contents, 0644)
+}
@@ -2,10 +2,7 @@ package plugin
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -29,12 +26,13 @@ func (m *PluginManager) UpdatePluginConnectionStatus(pluginId string) {
 func (m *PluginManager) List(onlyInstalled bool, typeFilter ...string) ([]Manifest, error) {
 	availManifests, err := m.dlIndex()
 
+	// Side-loaded plugins are listed from their installed manifest and regardless of the registries.
+	updatedList := m.listSideloaded(typeFilter...)
+
 	if err != nil {
-		return availManifests, err
+		return updatedList, err
 	}
 
-	updatedList := []Manifest{}
-
 	for i := range availManifests {
 		manifest := availManifests[i]
 		manifest.Status = PLUGIN_NOT_INSTALLED
@@ -43,6 +41,10 @@ func (m *PluginManager) List(onlyInstalled bool, typeFilter ...string) ([]Manife
 			continue
 		}
 
+		if m.IsSideloaded(manifest.ID) {
+			continue
+		}
+
 		if instManifest, ok := m.findLocalManifestById(manifest.ID); ok {
 			instVer, err := semver.NewVersion(instManifest.Version)
 
@@ -70,6 +72,10 @@ func (m *PluginManager) List(onlyInstalled bool, typeFilter ...string) ([]Manife
 				manifest.LastHeartbeat = spawnedPlugin.Manifest.LastHeartbeat
 			}
 
+			if manifest.Status != PLUGIN_NOT_INSTALLED {
+				manifest.Enabled = m.IsEnabled(manifest.ID)
+			}
+
 			updatedList = append(updatedList, manifest)
 		}
 	}
@@ -77,6 +83,32 @@ func (m *PluginManager) List(onlyInstalled bool, typeFilter ...string) ([]Manife
 	return updatedList, nil
 }
 
+func (m *PluginManager) listSideloaded(typeFilter ...string) []Manifest {
+	out := []Manifest{}
+
+	for _, manifest := range m.listLocalManifests() {
+		if len(typeFilter) > 0 && !global.ContainsAny(typeFilter, manifest.Type) {
+			continue
+		}
+
+		if !m.IsSideloaded(manifest.ID) {
+			continue
+		}
+
+		manifest.Status = PLUGIN_INSTALLED
+		manifest.Local = true
+		manifest.Enabled = m.IsEnabled(manifest.ID)
+
+		if spawnedPlugin := m.GetSpawnedPluginById(manifest.ID); spawnedPlugin != nil {
+			manifest.LastHeartbeat = spawnedPlugin.Manifest.LastHeartbeat
+		}
+
+		out = append(out, manifest)
+	}
+
+	return out
+}
+
 func (m *PluginManager) ListInstalled() []Manifest {
 	return m.listLocalManifests()
 }
@@ -96,17 +128,20 @@ func (m *PluginManager) Uninstall(id string) error {
 		return fmt.Errorf("plugin %s seems to not be installed", id)
 	}
 
-	m.Lock()
-	c, ok := m.SpawnedPlugins[id]
-	m.Unlock()
+	if err := m.stop(id); err != nil {
+		return err
+	}
 
-	if ok {
-		c.Cmd.Stop()
+	m.removeState(id)
 
-		if c.CtlClient != nil {
-			c.CtlClient.Connection.Close()
-		}
+	if err := m.removeConfig(id); err != nil {
+		golog.Errorf("Failed to remove configuration of plugin %s: %v", id, err)
 	}
+
+	if err := os.RemoveAll(m.pluginDataPath(id)); err != nil {
+		golog.Errorf("Failed to remove data directory of plugin %s: %v", id, err)
+	}
+
 	return os.RemoveAll(p)
 }
 
@@ -125,6 +160,30 @@ func (m *PluginManager) Install(ctx context.Context, id string) error {
 
 	manifest := list[idx]
 	pluginPath := filepath.Join(m.PluginPath, manifest.ID)
+
+	if err := m.download(ctx, manifest, pluginPath); err != nil {
+		return err
+	}
+
+	checksum, err := m.verifyDownload(manifest, pluginPath)
+	if err != nil {
+		os.RemoveAll(pluginPath)
+		return err
+	}
+
+	if err := m.recordChecksum(manifest.ID, checksum); err != nil {
+		return err
+	}
+
+	if err := m.grantPermissions(manifest.ID, manifest.RequestedPermissions()); err != nil {
+		return err
+	}
+
+	return m.spawn(manifest)
+}
+
+// Downloads and unpacks the plugin's binary for the current OS into the given directory.
+func (m *PluginManager) download(ctx context.Context, manifest Manifest, pluginPath string) error {
 	os.MkdirAll(pluginPath, 0755)
 
 	dlUrl := manifest.Download.Windows
@@ -142,16 +201,13 @@ func (m *PluginManager) Install(ctx context.Context, id string) error {
 	}
 
 	golog.Infof("Downloading plugin %s from url %s", manifest.ID, dlUrl)
-	err = dl.Get(pluginPath, dlUrl, dl.WithContext(ctx))
+	err := dl.Get(pluginPath, dlUrl, dl.WithContext(ctx))
 
 	if err != nil {
 		return err
 	}
 
 	golog.Infof("Download of plugin %s from url %s finished", manifest.ID, dlUrl)
-
-	m.spawn(manifest)
-
 	return nil
 }
 
@@ -163,37 +219,3 @@ func (m *PluginManager) indexOfManifest(id string, manifests []Manifest) int {
 	}
 	return -1
 }
-
-func (m *PluginManager) dlIndex() ([]Manifest, error) {
-	list := []Manifest{}
-
-	if m.RegistryFile == "" {
-		resp, err := http.Get(m.Registry)
-		if err != nil {
-			return nil, err
-		}
-
-		defer resp.Body.Close()
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, err
-		}
-
-		err = json.Unmarshal(body, &list)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		contents, err := os.ReadFile(m.RegistryFile)
-		if err != nil {
-			return nil, err
-		}
-
-		err = json.Unmarshal(contents, &list)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	return list, nil
-}
@@ -3,10 +3,13 @@ package plugin
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/f-taxes/f-taxes/backend/applog"
 	. "github.com/f-taxes/f-taxes/backend/global"
 	jobmanager "github.com/f-taxes/f-taxes/backend/jobManager"
+	"github.com/f-taxes/f-taxes/backend/snapshot"
+	"github.com/kataras/golog"
 	"github.com/kataras/iris/v12"
 	"github.com/knadh/koanf"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -17,11 +20,24 @@ var Manager *PluginManager
 
 func RegisterRoutes(app *iris.Application, cfg *koanf.Koanf) {
 	Manager = &PluginManager{
-		Registry:       cfg.String("plugins.registry"),
-		RegistryFile:   cfg.String("plugins.registryFile"),
-		PluginPath:     cfg.MustString("plugins.path"),
-		GrpcAddress:    cfg.MustString("grpc.address"),
-		SpawnedPlugins: map[string]*SpawnedPlugin{},
+		Registries:       registriesFromConfig(cfg),
+		RegistryCache:    cfg.MustString("plugins.registryCache"),
+		RegistryTTL:      time.Duration(cfg.Int("plugins.registryTTL")) * time.Second,
+		Offline:          cfg.Bool("plugins.offline"),
+		PluginPath:       cfg.MustString("plugins.path"),
+		GrpcAddress:      cfg.MustString("grpc.address"),
+		TrustedKeys:      cfg.Strings("plugins.trustedKeys"),
+		RequireChecksum:  cfg.Bool("plugins.requireChecksum"),
+		RequireSignature: cfg.Bool("plugins.requireSignature"),
+		SecretKeyFile:    cfg.MustString("plugins.secretKeyFile"),
+		SideloadMaxSize:  int64(cfg.Int("plugins.sideloadMaxSize")) << 20 * SIDELOAD_EXTRACT_RATIO,
+		Sandbox:          cfg.Bool("plugins.sandbox"),
+		SandboxEnv:       cfg.Strings("plugins.sandboxEnv"),
+		CgroupPath:       cfg.String("plugins.cgroup"),
+		SpawnedPlugins:   map[string]*SpawnedPlugin{},
+		supervisor:       newSupervisor(),
+		tokens:           map[string]string{},
+		permissions:      map[string][]Permission{},
 	}
 
 	Manager.Start()
@@ -53,6 +69,117 @@ func RegisterRoutes(app *iris.Application, cfg *koanf.Koanf) {
 		})
 	})
 
+	app.Get("/plugins/status", func(ctx iris.Context) {
+		ctx.JSON(Resp{
+			Result: true,
+			Data:   Manager.ProcessStatus(),
+		})
+	})
+
+	app.Get("/plugins/registries", func(ctx iris.Context) {
+		ctx.JSON(Resp{
+			Result: true,
+			Data:   Manager.RegistryStatus(),
+		})
+	})
+
+	app.Get("/plugins/health", func(ctx iris.Context) {
+		ctx.JSON(Resp{
+			Result: true,
+			Data:   Manager.HealthList(),
+		})
+	})
+
+	app.Any("/plugins/{id}/ui", Manager.proxyUI)
+	app.Any("/plugins/{id}/ui/{p:path}", Manager.proxyUI)
+
+	app.Get("/plugins/config/{id}", func(ctx iris.Context) {
+		cfg, err := Manager.GetUIConfig(ctx.Params().Get("id"))
+
+		if err != nil {
+			golog.Errorf("Failed to load configuration of plugin %s: %v", ctx.Params().Get("id"), err)
+			ctx.JSON(Resp{
+				Result: false,
+				Data:   err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(Resp{
+			Result: true,
+			Data:   cfg,
+		})
+	})
+
+	app.Post("/plugins/config", func(ctx iris.Context) {
+		reqData := struct {
+			ID     string         `json:"id"`
+			Values map[string]any `json:"values"`
+		}{}
+
+		if !ReadJSON(ctx, &reqData) {
+			return
+		}
+
+		if err := Manager.SetConfig(reqData.ID, reqData.Values); err != nil {
+			ctx.JSON(Resp{
+				Result: false,
+				Data:   err.Error(),
+			})
+			return
+		}
+
+		PushToClients("plugin-config-changed", map[string]any{
+			"id": reqData.ID,
+		})
+
+		ctx.JSON(Resp{
+			Result: true,
+		})
+	})
+
+	lifecycleOps := map[string]func(id string) error{
+		"start":   Manager.StartPlugin,
+		"stop":    Manager.StopPlugin,
+		"restart": Manager.RestartPlugin,
+		"enable": func(id string) error {
+			return Manager.SetEnabled(id, true)
+		},
+		"disable": func(id string) error {
+			return Manager.SetEnabled(id, false)
+		},
+	}
+
+	for name, op := range lifecycleOps {
+		app.Post("/plugins/"+name, func(ctx iris.Context) {
+			reqData := struct {
+				ID string `json:"id"`
+			}{}
+
+			if !ReadJSON(ctx, &reqData) {
+				return
+			}
+
+			if err := op(reqData.ID); err != nil {
+				applog.Send(applog.Error, fmt.Sprintf("Failed to %s plugin '%s': %s", name, reqData.ID, err.Error()), "Plugin")
+				ctx.JSON(Resp{
+					Result: false,
+					Data:   err.Error(),
+				})
+				return
+			}
+
+			PushToClients("plugin-lifecycle-changed", map[string]any{
+				"id":     reqData.ID,
+				"action": name,
+			})
+
+			ctx.JSON(Resp{
+				Result: true,
+			})
+		})
+	}
+
 	app.Post("/plugins/install", func(ctx iris.Context) {
 		reqData := struct {
 			ID      string `json:"id"`
@@ -107,6 +234,99 @@ func RegisterRoutes(app *iris.Application, cfg *koanf.Koanf) {
 		})
 	})
 
+	app.Post("/plugins/sideload", func(ctx iris.Context) {
+		ctx.SetMaxRequestBodySize(int64(cfg.Int("plugins.sideloadMaxSize")) << 20)
+
+		file, header, err := ctx.FormFile("file")
+
+		if err != nil {
+			applog.Send(applog.Error, fmt.Sprintf("Failed to receive plugin archive: %v", err.Error()))
+			ctx.JSON(Resp{
+				Result: false,
+				Data:   err.Error(),
+			})
+			return
+		}
+		defer file.Close()
+
+		manifest, err := Manager.Sideload(file)
+
+		if err != nil {
+			applog.Send(applog.Error, fmt.Sprintf("Failed to side-load plugin from '%s': %v", header.Filename, err.Error()))
+			ctx.JSON(Resp{
+				Result: false,
+				Data:   err.Error(),
+			})
+			return
+		}
+
+		applog.Send(applog.Info, fmt.Sprintf("Plugin '%s (%s)' was side-loaded from '%s'", manifest.Label, manifest.Version, header.Filename))
+
+		PushToClients("plugin-install-result", map[string]any{
+			"id":     manifest.ID,
+			"result": true,
+		})
+
+		ctx.JSON(Resp{
+			Result: true,
+			Data:   manifest,
+		})
+	})
+
+	app.Post("/plugins/update", func(ctx iris.Context) {
+		reqData := struct {
+			ID      string `json:"id"`
+			Label   string `json:"label"`
+			Version string `json:"version"`
+		}{}
+
+		if !ReadJSON(ctx, &reqData) {
+			return
+		}
+
+		jobID := primitive.NewObjectID()
+		jobCtx, cancelFn := context.WithCancel(context.Background())
+		jobs.Add(jobID, cancelFn)
+
+		defer PushToClients("job-progress", map[string]string{
+			"_id":      jobID.Hex(),
+			"label":    fmt.Sprintf("Updating plugin %s (%s)", reqData.Label, reqData.Version),
+			"progress": "100",
+		})
+
+		PushToClients("job-progress", map[string]string{
+			"_id":      jobID.Hex(),
+			"label":    fmt.Sprintf("Updating plugin %s (%s)", reqData.Label, reqData.Version),
+			"progress": "-1",
+		})
+
+		err := Manager.Update(jobCtx, reqData.ID)
+
+		if err != nil {
+			applog.Send(applog.Error, fmt.Sprintf("Failed to update plugin '%s (%s)': %v", reqData.Label, reqData.Version, err.Error()))
+			ctx.JSON(Resp{
+				Result: false,
+			})
+
+			PushToClients("plugin-update-result", map[string]any{
+				"id":     reqData.ID,
+				"result": false,
+			})
+			return
+		}
+
+		applog.Send(applog.Info, fmt.Sprintf("Plugin '%s (%s)' was updated", reqData.Label, reqData.Version))
+
+		PushToClients("plugin-update-result", map[string]any{
+			"id":     reqData.ID,
+			"result": true,
+		})
+
+		ctx.JSON(Resp{
+			Result: true,
+		})
+	})
+
 	app.Post("/plugin/uninstall", func(ctx iris.Context) {
 		reqData := struct {
 			ID      string `json:"id"`
@@ -118,6 +338,13 @@ func RegisterRoutes(app *iris.Application, cfg *koanf.Koanf) {
 			return
 		}
 
+		if err := snapshot.BeforeDestructiveOperation(fmt.Sprintf("uninstalling plugin %s", reqData.ID)); err != nil {
+			ctx.JSON(Resp{
+				Result: false,
+			})
+			return
+		}
+
 		err := Manager.Uninstall(reqData.ID)
 
 		if err != nil {
@@ -0,0 +1,71 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Resources a plugin may use. Zero means unlimited. Limits are only enforced on Linux.
+type Limits struct {
+	MemoryMB   int `json:"memoryMB,omitempty"`   // Maximum memory in megabytes. Requires cgroups v2.
+	CPUPercent int `json:"cpuPercent,omitempty"` // Maximum CPU time in percent of one core, e.g. 200 for two cores. Requires cgroups v2.
+	OpenFiles  int `json:"openFiles,omitempty"`  // Maximum number of open files and sockets. Applied shortly after the plugin started.
+}
+
+func (l Limits) isSet() bool {
+	return l.MemoryMB > 0 || l.CPUPercent > 0 || l.OpenFiles > 0
+}
+
+type Enforcement string
+
+const (
+	ENFORCE_NONE   = Enforcement("none")   // Limits aren't enforced, either because there are none or the OS doesn't support them.
+	ENFORCE_CGROUP = Enforcement("cgroup") // Limits are enforced by a cgroup v2 of the plugin.
+	ENFORCE_RLIMIT = Enforcement("rlimit") // Only the open files limit is enforced with an rlimit, memory and CPU limits are ignored.
+)
+
+// Resources a plugin's process used, as of SampledAt.
+type ResourceUsage struct {
+	MemoryBytes uint64      `json:"memoryBytes"` // Resident memory, or the memory of the plugin's cgroup if it has one.
+	CPUSeconds  float64     `json:"cpuSeconds"`  // CPU time used since the process started.
+	CPUPercent  float64     `json:"cpuPercent"`  // CPU usage in percent of one core since the previous sample.
+	OpenFiles   int         `json:"openFiles"`
+	Enforcement Enforcement `json:"enforcement"`
+	SampledAt   time.Time   `json:"sampledAt"`
+}
+
+// How a plugin's process is started and confined.
+type sandbox struct {
+	sync.Mutex
+	id          string
+	bin         string   // Executable to start.
+	dir         string   // Working directory of the process.
+	env         []string // Environment of the process, without the plugin's credentials.
+	limits      Limits
+	enforcement Enforcement
+	cgroup      string // Directory of the plugin's cgroup, if it has one.
+	cgroupFD    int
+	pid         int
+	last        ResourceUsage
+}
+
+// Starts the plugin like F-Taxes always did: from its installation directory and with the environment of F-Taxes.
+func (m *PluginManager) unsandboxed(manifest Manifest, dir string) *sandbox {
+	return &sandbox{
+		id:          manifest.ID,
+		bin:         fmt.Sprintf(".%s%s", string(os.PathSeparator), manifest.Bin),
+		dir:         dir,
+		env:         os.Environ(),
+		limits:      manifest.Limits,
+		enforcement: ENFORCE_NONE,
+		cgroupFD:    -1,
+	}
+}
+
+// Directory a sandboxed plugin runs in and may keep its files in. Hidden directories are ignored when looking for installed plugins.
+func (m *PluginManager) pluginDataPath(id string) string {
+	return filepath.Join(m.PluginPath, ".data", id)
+}
@@ -0,0 +1,311 @@
+//go:build linux
+
+package plugin
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-cmd/cmd"
+	"github.com/kataras/golog"
+	"golang.org/x/sys/unix"
+)
+
+// Clock ticks per second used in /proc/<pid>/stat. It is 100 on all architectures Linux runs F-Taxes on.
+const clockTicks = 100
+
+// Time to wait for the process id of a started plugin.
+const pidTimeout = 5 * time.Second
+
+// Environment variables passed on to sandboxed plugins. Plugins can ask for the ones in "plugins.sandboxEnv" with "env" in their manifest.
+var sandboxEnv = []string{"PATH", "LANG", "LANGUAGE", "TZ", "HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy", "SSL_CERT_FILE", "SSL_CERT_DIR"}
+
+// Prepares the sandbox of a plugin installed in dir. Plugins run in their own data directory with a scrubbed environment.
+// Memory and CPU limits are enforced by a cgroup below "plugins.cgroup" if configured and usable, the open files limit with an rlimit.
+// The plugin can still access any file the user running F-Taxes can access.
+func (m *PluginManager) newSandbox(manifest Manifest, dir string) *sandbox {
+	if !m.Sandbox {
+		return m.unsandboxed(manifest, dir)
+	}
+
+	s := &sandbox{
+		id:          manifest.ID,
+		bin:         filepath.Join(dir, manifest.Bin),
+		dir:         m.pluginDataPath(manifest.ID),
+		limits:      manifest.Limits,
+		enforcement: ENFORCE_NONE,
+		cgroupFD:    -1,
+	}
+
+	if abs, err := filepath.Abs(s.bin); err == nil {
+		s.bin = abs
+	}
+
+	if abs, err := filepath.Abs(s.dir); err == nil {
+		s.dir = abs
+	}
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		golog.Errorf("Failed to create data directory of plugin %s, starting it from its installation directory: %v", manifest.ID, err)
+		s.dir = filepath.Dir(s.bin)
+	}
+
+	allowed := append([]string{}, sandboxEnv...)
+
+	for _, name := range manifest.Env {
+		if slices.Contains(m.SandboxEnv, name) {
+			allowed = append(allowed, name)
+		} else {
+			golog.Warnf("Plugin %s asks for the environment variable %s, which isn't allowed by \"plugins.sandboxEnv\"", manifest.ID, name)
+		}
+	}
+
+	s.env = scrubEnv(os.Environ(), allowed)
+	s.env = append(s.env, "HOME="+s.dir, "TMPDIR="+s.dir)
+
+	if !s.limits.isSet() {
+		return s
+	}
+
+	s.enforcement = ENFORCE_RLIMIT
+
+	if m.CgroupPath != "" {
+		if err := s.createCgroup(m.CgroupPath); err != nil {
+			golog.Warnf("Failed to create cgroup for plugin %s, falling back to rlimits: %v", manifest.ID, err)
+			s.removeCgroup()
+		} else {
+			s.enforcement = ENFORCE_CGROUP
+		}
+	}
+
+	if s.enforcement == ENFORCE_RLIMIT && (s.limits.MemoryMB > 0 || s.limits.CPUPercent > 0) {
+		golog.Warnf("Memory and CPU limits of plugin %s can't be enforced without cgroups v2, see \"plugins.cgroup\"", manifest.ID)
+	}
+
+	return s
+}
+
+// Keeps only the allowed variables of env.
+func scrubEnv(env []string, allowed []string) []string {
+	out := []string{}
+
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+
+		for _, a := range allowed {
+			if name == a || strings.HasPrefix(name, "LC_") {
+				out = append(out, kv)
+				break
+			}
+		}
+	}
+
+	return out
+}
+
+// Creates a cgroup for the plugin within root, which must be a cgroup v2 directory delegated to the user running F-Taxes.
+func (s *sandbox) createCgroup(root string) error {
+	// Enabling the controllers fails if they already are or the parent doesn't offer them. The latter shows when writing the limits.
+	os.WriteFile(filepath.Join(root, "cgroup.subtree_control"), []byte("+memory +cpu +pids"), 0644)
+
+	s.cgroup = filepath.Join(root, "f-taxes-"+s.id)
+
+	if err := os.Mkdir(s.cgroup, 0755); err != nil && !os.IsExist(err) {
+		return err
+	}
+
+	if s.limits.MemoryMB > 0 {
+		if err := os.WriteFile(filepath.Join(s.cgroup, "memory.max"), []byte(strconv.Itoa(s.limits.MemoryMB<<20)), 0644); err != nil {
+			return err
+		}
+	}
+
+	if s.limits.CPUPercent > 0 {
+		// The plugin gets CPUPercent/100 of a core per period of 100ms.
+		if err := os.WriteFile(filepath.Join(s.cgroup, "cpu.max"), []byte(fmt.Sprintf("%d 100000", s.limits.CPUPercent*1000)), 0644); err != nil {
+			return err
+		}
+	}
+
+	fd, err := unix.Open(s.cgroup, unix.O_PATH|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+
+	s.cgroupFD = fd
+	return nil
+}
+
+func (s *sandbox) removeCgroup() {
+	if s.cgroupFD >= 0 {
+		unix.Close(s.cgroupFD)
+		s.cgroupFD = -1
+	}
+
+	if s.cgroup != "" {
+		os.Remove(s.cgroup)
+		s.cgroup = ""
+	}
+}
+
+// Places the process in the plugin's cgroup before it executes, so limits apply right from the start.
+func (s *sandbox) beforeExec(c *exec.Cmd) {
+	if s.cgroupFD < 0 {
+		return
+	}
+
+	if c.SysProcAttr == nil {
+		c.SysProcAttr = &syscall.SysProcAttr{}
+	}
+
+	c.SysProcAttr.UseCgroupFD = true
+	c.SysProcAttr.CgroupFD = s.cgroupFD
+}
+
+// Waits for the plugin's process to start and applies the open files limit, which no cgroup controller covers.
+// Go can't set rlimits for a child before it executes, so the plugin runs without the limit for a moment.
+// Child processes it starts during that moment keep the limit of F-Taxes.
+func (s *sandbox) apply(c *cmd.Cmd) {
+	deadline := time.Now().Add(pidTimeout)
+	pid := 0
+
+	for pid == 0 && time.Now().Before(deadline) {
+		select {
+		case <-c.Done():
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		pid = c.Status().PID
+	}
+
+	if pid == 0 {
+		golog.Warnf("Plugin %s didn't start within %s, its limits weren't applied", s.id, pidTimeout)
+		return
+	}
+
+	s.Lock()
+	s.pid = pid
+	s.Unlock()
+
+	if s.limits.OpenFiles > 0 {
+		s.setRlimit(pid, unix.RLIMIT_NOFILE, uint64(s.limits.OpenFiles))
+	}
+}
+
+func (s *sandbox) setRlimit(pid, resource int, value uint64) {
+	limit := unix.Rlimit{Cur: value, Max: value}
+
+	if err := unix.Prlimit(pid, resource, &limit, nil); err != nil {
+		golog.Errorf("Failed to apply limits to plugin %s: %v", s.id, err)
+	}
+}
+
+// Samples the resources the plugin's process currently uses. Returns nil if the process isn't running.
+func (s *sandbox) usage() *ResourceUsage {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.pid == 0 {
+		return nil
+	}
+
+	procDir := filepath.Join("/proc", strconv.Itoa(s.pid))
+	cpuSeconds, err := readProcCPU(filepath.Join(procDir, "stat"))
+	if err != nil {
+		return nil
+	}
+
+	u := ResourceUsage{
+		CPUSeconds:  cpuSeconds,
+		Enforcement: s.enforcement,
+		SampledAt:   time.Now().UTC(),
+	}
+
+	if s.cgroup != "" {
+		if v, err := os.ReadFile(filepath.Join(s.cgroup, "memory.current")); err == nil {
+			u.MemoryBytes, _ = strconv.ParseUint(strings.TrimSpace(string(v)), 10, 64)
+		}
+	}
+
+	if u.MemoryBytes == 0 {
+		u.MemoryBytes = readProcRSS(filepath.Join(procDir, "status"))
+	}
+
+	if fds, err := os.ReadDir(filepath.Join(procDir, "fd")); err == nil {
+		u.OpenFiles = len(fds)
+	}
+
+	if !s.last.SampledAt.IsZero() {
+		if elapsed := u.SampledAt.Sub(s.last.SampledAt).Seconds(); elapsed > 0 {
+			u.CPUPercent = (u.CPUSeconds - s.last.CPUSeconds) / elapsed * 100
+		}
+	}
+
+	s.last = u
+	return &u
+}
+
+// Reads the user and system CPU time of a process from /proc/<pid>/stat.
+func readProcCPU(path string) (float64, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	// The process name is in parentheses and may contain spaces, the fields after it are separated by spaces.
+	stat := string(contents)
+	fields := strings.Fields(stat[strings.LastIndexByte(stat, ')')+1:])
+
+	// utime and stime are fields 14 and 15 of the whole line, state is field 3.
+	if len(fields) < 13 {
+		return 0, fmt.Errorf("unexpected format of %s", path)
+	}
+
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(utime+stime) / clockTicks, nil
+}
+
+func readProcRSS(path string) uint64 {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if v, ok := strings.CutPrefix(scanner.Text(), "VmRSS:"); ok {
+			kb, _ := strconv.ParseUint(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(v), "kB")), 10, 64)
+			return kb << 10
+		}
+	}
+
+	return 0
+}
+
+// Cleans up after the plugin's process exited.
+func (s *sandbox) release() {
+	s.Lock()
+	s.pid = 0
+	s.Unlock()
+
+	s.removeCgroup()
+}
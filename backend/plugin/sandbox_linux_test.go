@@ -0,0 +1,43 @@
+//go:build linux
+
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadProcCPU(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stat")
+
+	// The process name may contain spaces and parentheses. utime is 250 and stime 150 ticks.
+	stat := "1234 (my (odd) plugin) S 1 1234 1234 0 -1 4194304 100 0 0 0 250 150 0 0 20 0 1 0 100 1000000 200\n"
+	if err := os.WriteFile(path, []byte(stat), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	seconds, err := readProcCPU(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if seconds != 4 {
+		t.Fatalf("expected 4 seconds, got %v", seconds)
+	}
+}
+
+func TestReadProcCPURejectsShortLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stat")
+	os.WriteFile(path, []byte("1234 (plugin) S 1 1234\n"), 0644)
+
+	if _, err := readProcCPU(path); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestReadProcCPUOfSelf(t *testing.T) {
+	if _, err := readProcCPU("/proc/self/stat"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,28 @@
+//go:build !linux
+
+package plugin
+
+import (
+	"os/exec"
+
+	"github.com/go-cmd/cmd"
+	"github.com/kataras/golog"
+)
+
+func (m *PluginManager) newSandbox(manifest Manifest, dir string) *sandbox {
+	if manifest.Limits.isSet() {
+		golog.Warnf("Resource limits of plugin %s are only enforced on Linux", manifest.ID)
+	}
+
+	return m.unsandboxed(manifest, dir)
+}
+
+func (s *sandbox) beforeExec(c *exec.Cmd) {}
+
+func (s *sandbox) apply(c *cmd.Cmd) {}
+
+func (s *sandbox) usage() *ResourceUsage {
+	return nil
+}
+
+func (s *sandbox) release() {}
@@ -0,0 +1,334 @@
+package plugin
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	semver "github.com/Masterminds/semver/v3"
+	. "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/kataras/golog"
+	"github.com/qiniu/qmgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var ErrInvalidArchive = errors.New("invalid plugin archive")
+var ErrInvalidManifest = errors.New("invalid plugin manifest")
+
+// How much larger than the uploaded archive its unpacked files may be.
+const SIDELOAD_EXTRACT_RATIO = 4
+
+var pluginIdPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Installs a plugin from a zip or tar.gz archive the user uploaded, without going through a registry.
+// The archive must contain exactly one manifest.json, the plugin's binary is expected next to it.
+// A plugin that was side-loaded before is replaced, plugins installed from a registry are not.
+func (m *PluginManager) Sideload(archive io.Reader) (Manifest, error) {
+	// Hidden directories are ignored when looking for installed plugins.
+	if err := os.MkdirAll(filepath.Join(m.PluginPath, ".staging"), 0755); err != nil {
+		return Manifest{}, err
+	}
+
+	stagingPath, err := os.MkdirTemp(filepath.Join(m.PluginPath, ".staging"), "sideload-")
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer os.RemoveAll(stagingPath)
+
+	if err := extractArchive(archive, stagingPath, m.SideloadMaxSize); err != nil {
+		return Manifest{}, err
+	}
+
+	manifests := FindFileByName("manifest.json", stagingPath)
+	if len(manifests) != 1 {
+		return Manifest{}, fmt.Errorf("%w: expected exactly one manifest.json but found %d", ErrInvalidArchive, len(manifests))
+	}
+
+	manifest, err := readSideloadedManifest(manifests[0])
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	pluginDir := filepath.Dir(manifests[0])
+	bin := binaryPath(manifest, pluginDir)
+
+	info, err := os.Stat(bin)
+	if err != nil || info.IsDir() {
+		return Manifest{}, fmt.Errorf("%w: binary %s isn't part of the archive", ErrInvalidArchive, manifest.Bin)
+	}
+
+	if err := os.Chmod(bin, 0755); err != nil {
+		return Manifest{}, err
+	}
+
+	// A side-loaded plugin is replaced where it is installed, new ones get a directory named after their id.
+	installedPath, installed := m.getPluginPath(manifest.ID)
+	if installed && !m.IsSideloaded(manifest.ID) {
+		return Manifest{}, fmt.Errorf("plugin %s is already installed from a registry", manifest.ID)
+	}
+
+	checksum, err := fileChecksum(bin)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	if err := m.stop(manifest.ID); err != nil {
+		return Manifest{}, err
+	}
+
+	if !installed {
+		installedPath = filepath.Join(m.PluginPath, manifest.ID)
+	}
+
+	if err := os.RemoveAll(installedPath); err != nil {
+		return Manifest{}, err
+	}
+
+	if err := os.Rename(pluginDir, installedPath); err != nil {
+		return Manifest{}, err
+	}
+
+	if err := m.recordChecksum(manifest.ID, checksum); err != nil {
+		return Manifest{}, err
+	}
+
+	if err := m.grantPermissions(manifest.ID, manifest.RequestedPermissions()); err != nil {
+		return Manifest{}, err
+	}
+
+	if err := m.setSideloaded(manifest.ID); err != nil {
+		return Manifest{}, err
+	}
+
+	manifest.Local = true
+
+	if !m.IsEnabled(manifest.ID) {
+		return manifest, nil
+	}
+
+	return manifest, m.spawn(manifest)
+}
+
+// Whether the plugin was installed from an uploaded archive rather than a registry.
+func (m *PluginManager) IsSideloaded(id string) bool {
+	state := pluginState{}
+	err := DBConn.Collection(COL_PLUGINS).Find(context.Background(), bson.M{"_id": id}).One(&state)
+
+	if err != nil && !qmgo.IsErrNoDocuments(err) {
+		golog.Errorf("Failed to fetch state of plugin %s: %v", id, err)
+	}
+
+	return state.Sideloaded
+}
+
+func (m *PluginManager) setSideloaded(id string) error {
+	_, err := DBConn.Collection(COL_PLUGINS).Upsert(context.Background(), bson.M{"_id": id}, bson.M{"$set": bson.M{"sideloaded": true}})
+	return err
+}
+
+// Reads a manifest and checks that it has everything F-Taxes needs to install and start the plugin.
+func readSideloadedManifest(path string) (Manifest, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	manifest := Manifest{}
+	if err := json.Unmarshal(contents, &manifest); err != nil {
+		return manifest, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+
+	if !pluginIdPattern.MatchString(manifest.ID) {
+		return manifest, fmt.Errorf("%w: id %q may only contain letters, digits, \"_\" and \"-\"", ErrInvalidManifest, manifest.ID)
+	}
+
+	if manifest.Label == "" {
+		return manifest, fmt.Errorf("%w: label is missing", ErrInvalidManifest)
+	}
+
+	if _, err := semver.NewVersion(manifest.Version); err != nil {
+		return manifest, fmt.Errorf("%w: version %q isn't a semantic version", ErrInvalidManifest, manifest.Version)
+	}
+
+	if manifest.Bin == "" || manifest.Bin != filepath.Base(manifest.Bin) {
+		return manifest, fmt.Errorf("%w: bin must be the name of a file next to the manifest", ErrInvalidManifest)
+	}
+
+	for _, p := range manifest.Permissions {
+		if !slices.Contains(AllPermissions, p) {
+			return manifest, fmt.Errorf("%w: unknown permission %q", ErrInvalidManifest, p)
+		}
+	}
+
+	if _, err := manifest.configSchema(); err != nil {
+		return manifest, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+
+	return manifest, nil
+}
+
+// Unpacks a zip or tar.gz archive into dir. The format is detected from the archive's contents.
+// Fails once the unpacked files exceed maxSize bytes in total, unless maxSize is 0.
+func extractArchive(r io.Reader, dir string, maxSize int64) error {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(4)
+
+	switch {
+	case bytes.HasPrefix(magic, []byte("PK\x03\x04")):
+		return extractZip(br, dir, &extractBudget{max: maxSize})
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		return extractTarGz(br, dir, &extractBudget{max: maxSize})
+	}
+
+	return fmt.Errorf("%w: only zip and tar.gz archives are supported", ErrInvalidArchive)
+}
+
+// Returns the path name resolves to within dir. Fails for entries that would end up outside of dir.
+func archiveEntryPath(dir, name string) (string, error) {
+	p := filepath.Join(dir, filepath.FromSlash(name))
+
+	if p != dir && !strings.HasPrefix(p, dir+string(os.PathSeparator)) {
+		return "", fmt.Errorf("%w: entry %s points outside of the archive", ErrInvalidArchive, name)
+	}
+
+	return p, nil
+}
+
+// Bytes unpacked from an archive so far. Shared by all of its entries, so many small files can't add up to more than one large one.
+type extractBudget struct {
+	max  int64 // Unlimited if 0.
+	used int64
+}
+
+func writeArchiveFile(p string, r io.Reader, mode os.FileMode, budget *extractBudget) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()|0600)
+	if err != nil {
+		return err
+	}
+
+	if budget.max <= 0 {
+		_, err = io.Copy(f, r)
+	} else {
+		// Reading one byte more than allowed tells an entry that fits exactly apart from one that's too large.
+		var n int64
+		n, err = io.Copy(f, io.LimitReader(r, budget.max-budget.used+1))
+		budget.used += n
+
+		if err == nil && budget.used > budget.max {
+			err = fmt.Errorf("%w: unpacked files are larger than %d MB", ErrInvalidArchive, budget.max>>20)
+		}
+	}
+
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func extractZip(r io.Reader, dir string, budget *extractBudget) error {
+	// Zip archives need random access, so the upload is buffered in a temporary file.
+	tmp, err := os.CreateTemp("", "f-taxes-sideload-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, r)
+	if err != nil {
+		return err
+	}
+
+	zr, err := zip.NewReader(tmp, size)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+
+	for _, f := range zr.File {
+		p, err := archiveEntryPath(dir, f.Name)
+		if err != nil {
+			return err
+		}
+
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(p, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !f.Mode().IsRegular() {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+
+		err = writeArchiveFile(p, rc, f.Mode(), budget)
+		rc.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func extractTarGz(r io.Reader, dir string, budget *extractBudget) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+
+		p, err := archiveEntryPath(dir, hdr.Name)
+		if err != nil {
+			return err
+		}
+
+		// Links are skipped, a plugin doesn't need them and they could point outside of the plugin's directory.
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(p, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeArchiveFile(p, tr, hdr.FileInfo().Mode(), budget); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package plugin
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestArchiveEntryPath(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "staging")
+
+	for name, ok := range map[string]bool{
+		"manifest.json":           true,
+		"plugin/bin/plugin":       true,
+		"plugin/../manifest.json": true,
+		".":                       true,
+		"/etc/passwd":             true, // Joined below dir, not absolute.
+		"../evil":                 false,
+		"plugin/../../evil":       false,
+		"../staging-evil/file":    false,
+	} {
+		p, err := archiveEntryPath(dir, name)
+
+		if ok && err != nil {
+			t.Errorf("%s: unexpected error %v", name, err)
+		}
+
+		if !ok && !errors.Is(err, ErrInvalidArchive) {
+			t.Errorf("%s: expected ErrInvalidArchive, got %q and %v", name, p, err)
+		}
+	}
+}
+
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	buf := bytes.Buffer{}
+	zw := zip.NewWriter(&buf)
+
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func tarGzArchive(t *testing.T, files map[string]string) []byte {
+	buf := bytes.Buffer{}
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	for name, content := range files {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(content))
+	}
+
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func TestExtractArchive(t *testing.T) {
+	for format, archive := range map[string]func(*testing.T, map[string]string) []byte{"zip": zipArchive, "tar.gz": tarGzArchive} {
+		dir := t.TempDir()
+		data := archive(t, map[string]string{"plugin/manifest.json": "{}", "plugin/plugin": "binary"})
+
+		if err := extractArchive(bytes.NewReader(data), dir, 0); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+
+		if content, err := os.ReadFile(filepath.Join(dir, "plugin", "plugin")); err != nil || string(content) != "binary" {
+			t.Fatalf("%s: binary wasn't extracted: %v", format, err)
+		}
+	}
+}
+
+func TestExtractArchiveRejectsZipSlip(t *testing.T) {
+	for format, archive := range map[string]func(*testing.T, map[string]string) []byte{"zip": zipArchive, "tar.gz": tarGzArchive} {
+		root := t.TempDir()
+		dir := filepath.Join(root, "staging")
+		os.Mkdir(dir, 0755)
+
+		data := archive(t, map[string]string{"../evil": "evil"})
+
+		if err := extractArchive(bytes.NewReader(data), dir, 0); !errors.Is(err, ErrInvalidArchive) {
+			t.Fatalf("%s: expected ErrInvalidArchive, got %v", format, err)
+		}
+
+		if _, err := os.Stat(filepath.Join(root, "evil")); !os.IsNotExist(err) {
+			t.Fatalf("%s: file was written outside of the staging directory", format)
+		}
+	}
+}
+
+func TestExtractArchiveLimitsSize(t *testing.T) {
+	for format, archive := range map[string]func(*testing.T, map[string]string) []byte{"zip": zipArchive, "tar.gz": tarGzArchive} {
+		// Neither file exceeds the limit on its own, together they do.
+		data := archive(t, map[string]string{"a": string(make([]byte, 600)), "b": string(make([]byte, 600))})
+
+		if err := extractArchive(bytes.NewReader(data), t.TempDir(), 1000); !errors.Is(err, ErrInvalidArchive) {
+			t.Fatalf("%s: expected ErrInvalidArchive, got %v", format, err)
+		}
+
+		if err := extractArchive(bytes.NewReader(data), t.TempDir(), 1200); err != nil {
+			t.Fatalf("%s: archive of exactly the limit was rejected: %v", format, err)
+		}
+	}
+}
+
+func TestExtractArchiveRejectsUnknownFormats(t *testing.T) {
+	if err := extractArchive(bytes.NewReader([]byte("not an archive")), t.TempDir(), 0); !errors.Is(err, ErrInvalidArchive) {
+		t.Fatalf("expected ErrInvalidArchive, got %v", err)
+	}
+}
@@ -0,0 +1,242 @@
+package plugin
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/f-taxes/f-taxes/backend/global"
+	"github.com/go-cmd/cmd"
+)
+
+const stderrLines = 50 // Number of stderr lines kept per plugin.
+const minRestartDelay = time.Second
+const maxRestartDelay = time.Minute
+
+type RestartMode string
+
+const (
+	RESTART_NEVER      = RestartMode("never")
+	RESTART_ON_FAILURE = RestartMode("on-failure")
+	RESTART_ALWAYS     = RestartMode("always")
+)
+
+// Decides whether a plugin is started again after its process exited.
+type RestartPolicy struct {
+	Mode        RestartMode `json:"mode"`        // "never", "on-failure" (default) or "always".
+	MaxRestarts int         `json:"maxRestarts"` // Maximum number of restarts within the window before giving up. Defaults to 5.
+	Window      int         `json:"window"`      // Length of the window in seconds. Defaults to 300.
+}
+
+func (p RestartPolicy) withDefaults() RestartPolicy {
+	if p.Mode == "" {
+		p.Mode = RESTART_ON_FAILURE
+	}
+
+	if p.MaxRestarts <= 0 {
+		p.MaxRestarts = 5
+	}
+
+	if p.Window <= 0 {
+		p.Window = 300
+	}
+
+	return p
+}
+
+type ProcessState string
+
+const (
+	PROCESS_RUNNING = ProcessState("running")
+	PROCESS_BACKOFF = ProcessState("backoff") // Waiting to be restarted.
+	PROCESS_EXITED  = ProcessState("exited")  // Exited and won't be restarted according to the restart policy.
+	PROCESS_FAILED  = ProcessState("failed")  // Restarted too often within the window, gave up.
+	PROCESS_STOPPED = ProcessState("stopped") // Stopped on purpose, e.g. because the plugin was uninstalled.
+)
+
+type ProcessStatus struct {
+	ID            string         `json:"id"`
+	State         ProcessState   `json:"state"`
+	Policy        RestartPolicy  `json:"policy"`
+	StartedAt     time.Time      `json:"startedAt"`
+	ExitedAt      time.Time      `json:"exitedAt"`
+	ExitCode      int            `json:"exitCode"`
+	Error         string         `json:"error"`
+	Restarts      int            `json:"restarts"` // Number of restarts within the current window.
+	TotalRestarts int            `json:"totalRestarts"`
+	NextRestart   time.Time      `json:"nextRestart"`
+	Stderr        []string       `json:"stderr"` // The most recent lines the plugin wrote to stderr.
+	Limits        Limits         `json:"limits"`
+	Usage         *ResourceUsage `json:"usage,omitempty"` // Resources the process uses. Only set while it's running and on Linux.
+
+	restartTimes  []time.Time
+	stopRequested bool
+}
+
+// Keeps track of the processes of spawned plugins and decides when to restart them.
+type supervisor struct {
+	sync.Mutex
+	processes map[string]*ProcessStatus
+}
+
+func newSupervisor() *supervisor {
+	return &supervisor{
+		processes: map[string]*ProcessStatus{},
+	}
+}
+
+func (s *supervisor) started(manifest Manifest) {
+	s.Lock()
+	p, ok := s.processes[manifest.ID]
+	if !ok {
+		p = &ProcessStatus{ID: manifest.ID, Stderr: []string{}}
+		s.processes[manifest.ID] = p
+	}
+
+	p.State = PROCESS_RUNNING
+	p.Policy = manifest.Restart.withDefaults()
+	p.Limits = manifest.Limits
+	p.StartedAt = time.Now().UTC()
+	p.NextRestart = time.Time{}
+	p.stopRequested = false
+	status := p.copy()
+	s.Unlock()
+
+	pushStatus(status)
+}
+
+func (s *supervisor) stderr(id, line string) {
+	s.Lock()
+	defer s.Unlock()
+
+	if p, ok := s.processes[id]; ok {
+		p.Stderr = append(p.Stderr, line)
+
+		if len(p.Stderr) > stderrLines {
+			p.Stderr = p.Stderr[len(p.Stderr)-stderrLines:]
+		}
+	}
+}
+
+// Marks the process as stopped on purpose, so it won't be restarted once it exits or while it waits to be restarted.
+func (s *supervisor) requestStop(id string) {
+	s.Lock()
+	p, ok := s.processes[id]
+	if !ok {
+		s.Unlock()
+		return
+	}
+
+	p.stopRequested = true
+
+	if p.State != PROCESS_BACKOFF {
+		s.Unlock()
+		return
+	}
+
+	p.State = PROCESS_STOPPED
+	p.NextRestart = time.Time{}
+	status := p.copy()
+	s.Unlock()
+
+	pushStatus(status)
+}
+
+func (s *supervisor) isStopRequested(id string) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	p, ok := s.processes[id]
+	return ok && p.stopRequested
+}
+
+// Records the exit of a plugin's process and returns after which delay it should be restarted.
+// The second return value is false if the plugin shouldn't be restarted at all.
+func (s *supervisor) exited(id string, exit cmd.Status) (time.Duration, bool) {
+	s.Lock()
+	p, ok := s.processes[id]
+	if !ok {
+		s.Unlock()
+		return 0, false
+	}
+
+	now := time.Now().UTC()
+	p.ExitedAt = now
+	p.ExitCode = exit.Exit
+	p.Error = ""
+
+	if exit.Error != nil {
+		p.Error = exit.Error.Error()
+	}
+
+	failed := exit.Error != nil || exit.Exit != 0 || !exit.Complete
+	restart := false
+	var delay time.Duration
+
+	switch {
+	case p.stopRequested:
+		p.State = PROCESS_STOPPED
+	case p.Policy.Mode == RESTART_NEVER, p.Policy.Mode == RESTART_ON_FAILURE && !failed:
+		p.State = PROCESS_EXITED
+	default:
+		window := time.Duration(p.Policy.Window) * time.Second
+		recent := []time.Time{}
+
+		for _, ts := range p.restartTimes {
+			if now.Sub(ts) < window {
+				recent = append(recent, ts)
+			}
+		}
+
+		p.restartTimes = recent
+		p.Restarts = len(recent)
+
+		if p.Restarts >= p.Policy.MaxRestarts {
+			p.State = PROCESS_FAILED
+			break
+		}
+
+		delay = min(minRestartDelay<<min(p.Restarts, 6), maxRestartDelay)
+		restart = true
+
+		p.restartTimes = append(p.restartTimes, now)
+		p.Restarts++
+		p.TotalRestarts++
+		p.State = PROCESS_BACKOFF
+		p.NextRestart = now.Add(delay)
+	}
+
+	status := p.copy()
+	s.Unlock()
+
+	pushStatus(status)
+	return delay, restart
+}
+
+func (s *supervisor) list() []ProcessStatus {
+	s.Lock()
+	defer s.Unlock()
+
+	out := []ProcessStatus{}
+
+	for _, p := range s.processes {
+		out = append(out, p.copy())
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ID < out[j].ID
+	})
+
+	return out
+}
+
+func (p *ProcessStatus) copy() ProcessStatus {
+	c := *p
+	c.Stderr = append([]string{}, p.Stderr...)
+	c.restartTimes = nil
+	return c
+}
+
+func pushStatus(status ProcessStatus) {
+	global.PushToClients("plugin-status", status)
+}
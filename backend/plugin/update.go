@@ -0,0 +1,174 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/kataras/golog"
+)
+
+// Time the new version of a plugin has to send a heartbeat before the update is rolled back.
+const updateHeartbeatTimeout = 30 * time.Second
+
+// Replaces an installed plugin with the version offered by the registry.
+// The new version is downloaded into a staging directory first, so the installed version keeps running until the download is complete.
+// If the new version doesn't send a heartbeat within updateHeartbeatTimeout the previous version is restored.
+func (m *PluginManager) Update(ctx context.Context, id string) error {
+	list, err := m.dlIndex()
+	if err != nil {
+		return err
+	}
+
+	idx := m.indexOfManifest(id, list)
+	if idx == -1 {
+		return fmt.Errorf("manifest not found")
+	}
+
+	manifest := list[idx]
+
+	installedPath, ok := m.getPluginPath(id)
+	if !ok {
+		return fmt.Errorf("plugin %s seems to not be installed", id)
+	}
+
+	if m.IsSideloaded(id) {
+		return fmt.Errorf("plugin %s was side-loaded, upload a new version of it instead", id)
+	}
+
+	// Hidden directories are ignored when looking for installed plugins.
+	stagingPath := filepath.Join(m.PluginPath, ".staging", id)
+	backupPath := filepath.Join(m.PluginPath, ".backup", id)
+
+	os.RemoveAll(stagingPath)
+	defer os.RemoveAll(stagingPath)
+
+	if err := m.download(ctx, manifest, stagingPath); err != nil {
+		return err
+	}
+
+	if len(FindFileByName("manifest.json", stagingPath)) == 0 {
+		return fmt.Errorf("the download of version %s doesn't contain a manifest", manifest.Version)
+	}
+
+	checksum, err := m.verifyDownload(manifest, stagingPath)
+	if err != nil {
+		return err
+	}
+
+	previousChecksum, err := m.recordedChecksum(id)
+	if err != nil {
+		return err
+	}
+
+	previousPermissions := m.grantedPermissions(id)
+
+	if err := m.stop(id); err != nil {
+		return err
+	}
+
+	if err := swapDirs(installedPath, stagingPath, backupPath); err != nil {
+		m.restartAfterUpdate(id)
+		return err
+	}
+
+	if err := m.recordChecksum(id, checksum); err != nil {
+		golog.Errorf("Failed to record checksum of plugin %s: %v", id, err)
+	}
+
+	if err := m.grantPermissions(id, manifest.RequestedPermissions()); err != nil {
+		golog.Errorf("Failed to record permissions of plugin %s: %v", id, err)
+	}
+
+	if !m.IsEnabled(id) || manifest.NoSpawn {
+		os.RemoveAll(backupPath)
+		return nil
+	}
+
+	startedAt := time.Now().UTC()
+
+	if err := m.StartPlugin(id); err == nil && m.waitForHeartbeat(ctx, id, startedAt) {
+		os.RemoveAll(backupPath)
+		return nil
+	}
+
+	golog.Warnf("Version %s of plugin %s didn't report back, restoring the previous version", manifest.Version, id)
+	m.stop(id)
+
+	if err := os.RemoveAll(installedPath); err != nil {
+		return fmt.Errorf("version %s didn't start and the previous version couldn't be restored: %v", manifest.Version, err)
+	}
+
+	if err := os.Rename(backupPath, installedPath); err != nil {
+		return fmt.Errorf("version %s didn't start and the previous version couldn't be restored: %v", manifest.Version, err)
+	}
+
+	if err := m.recordChecksum(id, previousChecksum); err != nil {
+		golog.Errorf("Failed to record checksum of plugin %s: %v", id, err)
+	}
+
+	if err := m.grantPermissions(id, previousPermissions); err != nil {
+		golog.Errorf("Failed to record permissions of plugin %s: %v", id, err)
+	}
+
+	m.restartAfterUpdate(id)
+	return fmt.Errorf("version %s didn't send a heartbeat within %s, the previous version was restored", manifest.Version, updateHeartbeatTimeout)
+}
+
+// Moves the installed plugin to backupPath and the staged version in its place.
+func swapDirs(installedPath, stagingPath, backupPath string) error {
+	os.RemoveAll(backupPath)
+
+	if err := os.MkdirAll(filepath.Dir(backupPath), 0755); err != nil {
+		return err
+	}
+
+	if err := os.Rename(installedPath, backupPath); err != nil {
+		return err
+	}
+
+	if err := os.Rename(stagingPath, installedPath); err != nil {
+		os.Rename(backupPath, installedPath)
+		return err
+	}
+
+	return nil
+}
+
+func (m *PluginManager) restartAfterUpdate(id string) {
+	if !m.IsEnabled(id) {
+		return
+	}
+
+	if err := m.StartPlugin(id); err != nil {
+		golog.Errorf("Failed to start plugin %s after update: %v", id, err)
+	}
+}
+
+// Waits until the plugin sent a heartbeat after the given time.
+func (m *PluginManager) waitForHeartbeat(ctx context.Context, id string, after time.Time) bool {
+	timeout := time.After(updateHeartbeatTimeout)
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-timeout:
+			return false
+		case <-ticker.C:
+			m.Lock()
+			p := m.GetSpawnedPluginById(id)
+			ok := p != nil && p.Manifest.LastHeartbeat.After(after)
+			m.Unlock()
+
+			if ok {
+				return true
+			}
+		}
+	}
+}
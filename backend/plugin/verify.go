@@ -0,0 +1,244 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	. "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/kataras/golog"
+	"github.com/qiniu/qmgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"golang.org/x/crypto/blake2b"
+)
+
+var ErrChecksumMismatch = errors.New("checksum of the plugin binary doesn't match")
+
+// Returns the path of the plugin's executable within the given directory.
+func binaryPath(manifest Manifest, dir string) string {
+	p := filepath.Join(dir, manifest.Bin)
+
+	if runtime.GOOS == "windows" && filepath.Ext(p) == "" {
+		if _, err := os.Stat(p + ".exe"); err == nil {
+			return p + ".exe"
+		}
+	}
+
+	return p
+}
+
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Checks the downloaded binary in dir against the checksum and signature listed in the registry's manifest.
+// Returns the checksum of the binary to be recorded for later verification.
+func (m *PluginManager) verifyDownload(manifest Manifest, dir string) (string, error) {
+	bin := binaryPath(manifest, dir)
+	checksum, err := fileChecksum(bin)
+	if err != nil {
+		return "", err
+	}
+
+	expected := manifest.Download.ChecksumForOS(runtime.GOOS)
+	requireChecksum := m.RequireChecksum || manifest.RegistryTrust == TRUST_COMMUNITY || manifest.RegistryTrust == TRUST_UNTRUSTED
+	requireSignature := m.RequireSignature || manifest.RegistryTrust == TRUST_UNTRUSTED
+
+	switch {
+	case expected != "" && !strings.EqualFold(expected, checksum):
+		return "", fmt.Errorf("%w: expected %s but got %s", ErrChecksumMismatch, expected, checksum)
+	case expected == "" && requireChecksum:
+		return "", fmt.Errorf("plugin %s doesn't provide a checksum for %s", manifest.ID, runtime.GOOS)
+	case expected == "":
+		golog.Warnf("Plugin %s doesn't provide a checksum for %s, its integrity can't be verified", manifest.ID, runtime.GOOS)
+	}
+
+	signature := manifest.Download.SignatureForOS(runtime.GOOS)
+
+	if signature == "" {
+		if requireSignature {
+			return "", fmt.Errorf("plugin %s isn't signed", manifest.ID)
+		}
+		return checksum, nil
+	}
+
+	keys := []minisignKey{}
+	for _, k := range m.TrustedKeys {
+		key, err := parseMinisignPublicKey(k)
+		if err != nil {
+			golog.Errorf("Ignoring invalid trusted plugin key: %v", err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	// Without trusted keys every signature would be rejected. That only matters if a signature is required.
+	if len(keys) == 0 && !requireSignature {
+		golog.Warnf("Plugin %s is signed but no trusted keys are configured in \"plugins.trustedKeys\", its signature wasn't verified", manifest.ID)
+		return checksum, nil
+	}
+
+	f, err := os.Open(bin)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if err := verifyMinisign(f, signature, keys); err != nil {
+		return "", fmt.Errorf("signature of plugin %s is invalid: %w", manifest.ID, err)
+	}
+
+	return checksum, nil
+}
+
+// Verifies the binary of a plugin installed in dir against the checksum recorded when it was installed.
+// Plugins installed before checksums were recorded get their current checksum recorded.
+func (m *PluginManager) verifyInstalledBinary(manifest Manifest, dir string) error {
+	checksum, err := fileChecksum(binaryPath(manifest, dir))
+	if err != nil {
+		return err
+	}
+
+	recorded, err := m.recordedChecksum(manifest.ID)
+	if err != nil {
+		return err
+	}
+
+	if recorded == "" {
+		return m.recordChecksum(manifest.ID, checksum)
+	}
+
+	if recorded != checksum {
+		return fmt.Errorf("%w: the binary of plugin %s was modified after it was installed", ErrChecksumMismatch, manifest.ID)
+	}
+
+	return nil
+}
+
+func (m *PluginManager) recordedChecksum(id string) (string, error) {
+	state := pluginState{}
+	err := DBConn.Collection(COL_PLUGINS).Find(context.Background(), bson.M{"_id": id}).One(&state)
+
+	if qmgo.IsErrNoDocuments(err) {
+		return "", nil
+	}
+
+	return state.Checksum, err
+}
+
+func (m *PluginManager) recordChecksum(id, checksum string) error {
+	_, err := DBConn.Collection(COL_PLUGINS).Upsert(context.Background(), bson.M{"_id": id}, bson.M{"$set": bson.M{"checksum": checksum}})
+	return err
+}
+
+type minisignKey struct {
+	id  []byte
+	key ed25519.PublicKey
+}
+
+// Parses a minisign public key. Accepts either the contents of a .pub file or just the base64 encoded key.
+func parseMinisignPublicKey(s string) (minisignKey, error) {
+	line := ""
+
+	for _, l := range strings.Split(strings.TrimSpace(s), "\n") {
+		l = strings.TrimSpace(l)
+		if l != "" && !strings.HasPrefix(l, "untrusted comment:") {
+			line = l
+		}
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(line)
+	if err != nil {
+		return minisignKey{}, err
+	}
+
+	if len(raw) != 2+8+ed25519.PublicKeySize || string(raw[:2]) != "Ed" {
+		return minisignKey{}, errors.New("not a minisign ed25519 public key")
+	}
+
+	return minisignKey{id: raw[2:10], key: ed25519.PublicKey(raw[10:])}, nil
+}
+
+// Verifies a detached minisign signature (the contents of a .minisig file) of data against the trusted keys.
+// Both legacy ("Ed") and prehashed ("ED") signatures are supported.
+func verifyMinisign(data io.Reader, sigFile string, keys []minisignKey) error {
+	lines := []string{}
+	for _, l := range strings.Split(strings.TrimSpace(sigFile), "\n") {
+		lines = append(lines, strings.TrimRight(l, "\r"))
+	}
+
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return errors.New("malformed signature")
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(sig) != 2+8+ed25519.SignatureSize {
+		return errors.New("malformed signature")
+	}
+
+	globalSig, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil || len(globalSig) != ed25519.SignatureSize {
+		return errors.New("malformed signature")
+	}
+
+	var key *minisignKey
+	for i := range keys {
+		if bytes.Equal(keys[i].id, sig[2:10]) {
+			key = &keys[i]
+			break
+		}
+	}
+
+	if key == nil {
+		return fmt.Errorf("signed with untrusted key %X", sig[2:10])
+	}
+
+	var msg []byte
+
+	switch string(sig[:2]) {
+	case "Ed":
+		msg, err = io.ReadAll(data)
+	case "ED":
+		h, _ := blake2b.New512(nil)
+		_, err = io.Copy(h, data)
+		msg = h.Sum(nil)
+	default:
+		return fmt.Errorf("unsupported signature algorithm %q", sig[:2])
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(key.key, msg, sig[10:]) {
+		return errors.New("signature doesn't match")
+	}
+
+	trustedComment := strings.TrimPrefix(lines[2], "trusted comment: ")
+	if !ed25519.Verify(key.key, append(append([]byte{}, sig[10:]...), trustedComment...), globalSig) {
+		return errors.New("trusted comment doesn't match its signature")
+	}
+
+	return nil
+}
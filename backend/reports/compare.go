@@ -0,0 +1,73 @@
+package reports
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Differences between two report runs.
+type Comparison struct {
+	A             Run           `json:"a"`
+	B             Run           `json:"b"`
+	SameInput     bool          `json:"sameInput"` // Both reports are based on the same records.
+	SameVersion   bool          `json:"sameVersion"`
+	SameParams    bool          `json:"sameParams"`
+	Identical     bool          `json:"identical"` // Both runs produced the same files with the same content.
+	Files         []FileCompare `json:"files"`
+	RecordsChange int64         `json:"recordsChange"` // Number of records B covers more than A.
+}
+
+type FileCompare struct {
+	Name    string `json:"name"`
+	InA     bool   `json:"inA"`
+	InB     bool   `json:"inB"`
+	Changed bool   `json:"changed"` // The file exists in both runs but its content differs.
+	SizeA   int64  `json:"sizeA"`
+	SizeB   int64  `json:"sizeB"`
+}
+
+func Compare(a, b primitive.ObjectID) (Comparison, error) {
+	runA, err := GetRun(a)
+	if err != nil {
+		return Comparison{}, err
+	}
+
+	runB, err := GetRun(b)
+	if err != nil {
+		return Comparison{}, err
+	}
+
+	c := Comparison{
+		A:             runA,
+		B:             runB,
+		SameInput:     runA.InputHash == runB.InputHash,
+		SameVersion:   runA.Plugin == runB.Plugin && runA.PluginVersion == runB.PluginVersion,
+		SameParams:    runA.Params == runB.Params,
+		Identical:     true,
+		Files:         []FileCompare{},
+		RecordsChange: runB.Records - runA.Records,
+	}
+
+	for _, f := range runA.Files {
+		fc := FileCompare{Name: f.Name, InA: true, SizeA: f.Size}
+
+		if other, ok := runB.file(f.Name); ok {
+			fc.InB = true
+			fc.SizeB = other.Size
+			fc.Changed = other.Sha256 != f.Sha256
+		}
+
+		c.Files = append(c.Files, fc)
+	}
+
+	for _, f := range runB.Files {
+		if _, ok := runA.file(f.Name); !ok {
+			c.Files = append(c.Files, FileCompare{Name: f.Name, InB: true, SizeB: f.Size})
+		}
+	}
+
+	for _, fc := range c.Files {
+		if !fc.InA || !fc.InB || fc.Changed {
+			c.Identical = false
+		}
+	}
+
+	return c, nil
+}
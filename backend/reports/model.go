@@ -0,0 +1,375 @@
+package reports
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/f-taxes/f-taxes/backend/applog"
+	g "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/f-taxes/f-taxes/backend/plugin"
+	"github.com/f-taxes/f-taxes/backend/settings"
+	"github.com/f-taxes/f-taxes/proto"
+	"github.com/kataras/golog"
+	"github.com/knadh/koanf"
+	"github.com/qiniu/qmgo"
+	"github.com/qiniu/qmgo/options"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const COL_REPORT_RUNS = "report_runs"
+
+// Time a plugin gets to generate a report.
+const generateTimeout = time.Minute * 30
+
+var ErrRunNotFound = errors.New("report run not found")
+var ErrInvalidFileName = errors.New("invalid file name")
+var ErrRunRunning = errors.New("report is still being generated")
+var ErrTooLarge = errors.New("report is too large")
+
+type Status string
+
+const (
+	RUNNING  = Status("running")
+	FINISHED = Status("finished")
+	FAILED   = Status("failed")
+)
+
+type Params struct {
+	TaxYear  int32  `json:"taxYear" bson:"taxYear"`
+	Currency string `json:"currency" bson:"currency"`
+	Method   string `json:"method" bson:"method"`
+}
+
+// A file produced by a report run. The content is stored in the run's folder below "reports.path".
+type File struct {
+	Name        string `json:"name" bson:"name"`
+	ContentType string `json:"contentType" bson:"contentType"`
+	Size        int64  `json:"size" bson:"size"`
+	Sha256      string `json:"sha256" bson:"sha256"`
+}
+
+// A single generation of a report, including everything needed to tell whether two reports were created from the same data.
+type Run struct {
+	ID            primitive.ObjectID `json:"_id" bson:"_id"`
+	Plugin        string             `json:"plugin" bson:"plugin"`
+	PluginVersion string             `json:"pluginVersion" bson:"pluginVersion"`
+	Params        Params             `json:"params" bson:"params"`
+	InputHash     string             `json:"inputHash" bson:"inputHash"` // SHA-256 over all records up to the end of the tax year.
+	Records       int64              `json:"records" bson:"records"`     // Number of records the input hash covers.
+	Status        Status             `json:"status" bson:"status"`
+	Error         string             `json:"error,omitempty" bson:"error,omitempty"`
+	Files         []File             `json:"files" bson:"files"`
+	Created       time.Time          `json:"created" bson:"created"`
+	Finished      time.Time          `json:"finished,omitempty" bson:"finished,omitempty"`
+}
+
+func (r Run) file(name string) (File, bool) {
+	for _, f := range r.Files {
+		if f.Name == name {
+			return f, true
+		}
+	}
+
+	return File{}, false
+}
+
+// Creates indexes and marks runs that were interrupted by a shutdown as failed.
+func Setup() {
+	col := g.DBConn.Collection(COL_REPORT_RUNS)
+	col.CreateIndexes(context.Background(), []options.IndexModel{
+		{Key: []string{"plugin", "-created"}, Background: true},
+	})
+
+	_, err := col.UpdateAll(context.Background(), bson.M{"status": RUNNING}, bson.M{"$set": bson.M{
+		"status": FAILED,
+		"error":  "F-Taxes was stopped before the report was finished",
+	}})
+
+	if err != nil {
+		golog.Errorf("Failed to update interrupted report runs: %v", err)
+	}
+}
+
+func GetRun(id primitive.ObjectID) (Run, error) {
+	run := Run{}
+	err := g.DBConn.Collection(COL_REPORT_RUNS).Find(context.Background(), bson.M{"_id": id}).One(&run)
+
+	if qmgo.IsErrNoDocuments(err) {
+		return run, ErrRunNotFound
+	}
+
+	return run, err
+}
+
+// Lists past runs, newest first. Runs of all plugins are returned if pluginID is empty.
+func ListRuns(pluginID string) ([]Run, error) {
+	filter := bson.M{}
+	if pluginID != "" {
+		filter["plugin"] = pluginID
+	}
+
+	runs := []Run{}
+	err := g.DBConn.Collection(COL_REPORT_RUNS).Find(context.Background(), filter).Sort("-created").All(&runs)
+	return runs, err
+}
+
+// Deletes a run and its files. Runs that are still being generated can't be deleted, the plugin would keep writing into their folder.
+func DeleteRun(cfg *koanf.Koanf, id primitive.ObjectID) error {
+	run, err := GetRun(id)
+	if err != nil {
+		return err
+	}
+
+	if run.Status == RUNNING {
+		return ErrRunRunning
+	}
+
+	err = g.DBConn.Collection(COL_REPORT_RUNS).RemoveId(context.Background(), id)
+
+	if qmgo.IsErrNoDocuments(err) {
+		return ErrRunNotFound
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(runPath(cfg, id))
+}
+
+// Path of a file produced by a run.
+func FilePath(cfg *koanf.Koanf, run Run, name string) (string, error) {
+	if _, ok := run.file(name); !ok {
+		return "", ErrInvalidFileName
+	}
+
+	return filepath.Join(runPath(cfg, run.ID), name), nil
+}
+
+func runPath(cfg *koanf.Koanf, id primitive.ObjectID) string {
+	return filepath.Join(cfg.MustString("reports.path"), id.Hex())
+}
+
+// Starts generating a report with the given plugin. The run is stored right away and updated in the background.
+func Generate(cfg *koanf.Koanf, pluginID string, params Params) (Run, error) {
+	p, err := plugin.Manager.GetAvailablePluginById(pluginID)
+	if err != nil {
+		return Run{}, err
+	}
+
+	if !g.ContainsAny(p.Manifest.ReportMethods, params.Method) {
+		return Run{}, fmt.Errorf("plugin %s doesn't support the method '%s'", pluginID, params.Method)
+	}
+
+	digest, count, err := inputHash(params.TaxYear)
+	if err != nil {
+		return Run{}, fmt.Errorf("failed to hash records: %w", err)
+	}
+
+	run := Run{
+		ID:            primitive.NewObjectID(),
+		Plugin:        p.Manifest.ID,
+		PluginVersion: p.Manifest.Version,
+		Params:        params,
+		InputHash:     digest,
+		Records:       count,
+		Status:        RUNNING,
+		Files:         []File{},
+		Created:       time.Now().UTC(),
+	}
+
+	if _, err := g.DBConn.Collection(COL_REPORT_RUNS).InsertOne(context.Background(), run); err != nil {
+		return run, err
+	}
+
+	go func() {
+		files, err := receive(cfg, p, run)
+		run.Files = files
+		run.Finished = time.Now().UTC()
+		run.Status = FINISHED
+
+		if err != nil {
+			run.Status = FAILED
+			run.Error = err.Error()
+			applog.Send(applog.Error, fmt.Sprintf("[%s] Failed to generate report for %d: %v", p.Manifest.Label, params.TaxYear, err))
+		}
+
+		if err := g.DBConn.Collection(COL_REPORT_RUNS).ReplaceOne(context.Background(), bson.M{"_id": run.ID}, run); err != nil {
+			golog.Errorf("Failed to store report run %s: %v", run.ID.Hex(), err)
+		}
+
+		g.PushToClients("report-run-changed", run)
+	}()
+
+	return run, nil
+}
+
+// Calls the plugin and writes the files it sends into the run's folder.
+func receive(cfg *koanf.Koanf, p plugin.AvailablePlugin, run Run) ([]File, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), generateTimeout)
+	defer cancel()
+
+	stream, err := p.Ctl.GenerateReport(ctx, &proto.ReportRequest{
+		RunID:     run.ID.Hex(),
+		TaxYear:   run.Params.TaxYear,
+		Currency:  run.Params.Currency,
+		Method:    run.Params.Method,
+		InputHash: run.InputHash,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	dir := runPath(cfg, run.ID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	w := artifactWriter{
+		dir:         dir,
+		maxFileSize: int64(cfg.Int("reports.maxFileSize")) << 20,
+		maxRunSize:  int64(cfg.Int("reports.maxRunSize")) << 20,
+	}
+	defer w.close()
+
+	for {
+		artifact, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return w.files, err
+		}
+
+		if err := w.write(artifact); err != nil {
+			return w.files, err
+		}
+	}
+
+	return w.files, w.close()
+}
+
+// Writes the chunks of files streamed by a plugin to disk.
+type artifactWriter struct {
+	dir         string
+	maxFileSize int64 // Unlimited if 0.
+	maxRunSize  int64 // Unlimited if 0.
+	size        int64
+	files       []File
+	open        map[string]*os.File
+	hash        map[string]hash.Hash
+}
+
+func (w *artifactWriter) write(a *proto.ReportArtifact) error {
+	name := a.Name
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("%w: '%s'", ErrInvalidFileName, a.Name)
+	}
+
+	if w.maxRunSize > 0 && w.size+int64(len(a.Data)) > w.maxRunSize {
+		return fmt.Errorf("%w: files are larger than %d MB", ErrTooLarge, w.maxRunSize>>20)
+	}
+
+	if w.open == nil {
+		w.open = map[string]*os.File{}
+		w.hash = map[string]hash.Hash{}
+	}
+
+	file, ok := w.open[name]
+	if !ok {
+		var err error
+		if file, err = os.Create(filepath.Join(w.dir, name)); err != nil {
+			return err
+		}
+
+		w.open[name] = file
+		w.hash[name] = sha256.New()
+		w.files = append(w.files, File{Name: name, ContentType: a.ContentType})
+	}
+
+	f := &w.files[slices.IndexFunc(w.files, func(f File) bool { return f.Name == name })]
+
+	if w.maxFileSize > 0 && f.Size+int64(len(a.Data)) > w.maxFileSize {
+		return fmt.Errorf("%w: %s is larger than %d MB", ErrTooLarge, name, w.maxFileSize>>20)
+	}
+
+	if _, err := file.Write(a.Data); err != nil {
+		return err
+	}
+
+	w.hash[name].Write(a.Data)
+	f.Size += int64(len(a.Data))
+	w.size += int64(len(a.Data))
+
+	return nil
+}
+
+// Closes all files and records their checksums.
+func (w *artifactWriter) close() error {
+	var firstErr error
+
+	for i := range w.files {
+		name := w.files[i].Name
+		file, ok := w.open[name]
+		if !ok {
+			continue
+		}
+
+		if err := file.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+
+		w.files[i].Sha256 = hex.EncodeToString(w.hash[name].Sum(nil))
+		delete(w.open, name)
+	}
+
+	return firstErr
+}
+
+// Hashes all records that aren't in the trash up to the end of the tax year in the user's time zone.
+// Earlier years are included because they affect the cost basis of the tax year.
+func inputHash(taxYear int32) (string, int64, error) {
+	loc := time.UTC
+	if s, err := settings.Get(); err == nil && s.TimeZone != "" {
+		if l, err := time.LoadLocation(s.TimeZone); err == nil {
+			loc = l
+		}
+	}
+
+	end := time.Date(int(taxYear)+1, 1, 1, 0, 0, 0, 0, loc)
+	h := sha256.New()
+	count := int64(0)
+
+	for _, col := range []string{g.COL_TRADES, g.COL_TRANSFERS} {
+		cursor := g.DBConn.Collection(col).Find(context.Background(), g.NotDeleted(bson.M{"ts": bson.M{"$lt": end}})).Sort("_id").Cursor()
+
+		doc := bson.Raw{}
+		for cursor.Next(&doc) {
+			h.Write([]byte(col))
+			h.Write(doc)
+			count++
+		}
+
+		if err := cursor.Err(); err != nil {
+			cursor.Close()
+			return "", 0, err
+		}
+
+		cursor.Close()
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), count, nil
+}
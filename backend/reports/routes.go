@@ -0,0 +1,155 @@
+package reports
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/f-taxes/f-taxes/backend/applog"
+	g "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/kataras/golog"
+	"github.com/kataras/iris/v12"
+	"github.com/knadh/koanf"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func RegisterRoutes(app *iris.Application, cfg *koanf.Koanf) {
+	Setup()
+
+	app.Post("/reports/generate", func(ctx iris.Context) {
+		reqData := struct {
+			Plugin string `json:"plugin"`
+			Params
+		}{}
+
+		if !g.ReadJSON(ctx, &reqData) {
+			return
+		}
+
+		run, err := Generate(cfg, reqData.Plugin, reqData.Params)
+
+		if err != nil {
+			applog.Send(applog.Error, fmt.Sprintf("Failed to generate report: %s", err.Error()))
+
+			ctx.JSON(g.Resp{
+				Result: false,
+				Data:   err.Error(),
+			})
+			return
+		}
+
+		g.PushToClients("report-run-changed", run)
+
+		ctx.JSON(g.Resp{
+			Result: true,
+			Data:   run,
+		})
+	})
+
+	app.Post("/reports/runs", func(ctx iris.Context) {
+		reqData := struct {
+			Plugin string `json:"plugin"`
+		}{}
+
+		if ctx.GetContentLength() > 0 && !g.ReadJSON(ctx, &reqData) {
+			return
+		}
+
+		runs, err := ListRuns(reqData.Plugin)
+
+		if err != nil {
+			golog.Errorf("Failed to list report runs: %v", err)
+			ctx.JSON(g.Resp{
+				Result: false,
+			})
+			return
+		}
+
+		ctx.JSON(g.Resp{
+			Result: true,
+			Data:   runs,
+		})
+	})
+
+	app.Get("/reports/runs/{id:string}/files/{name:string}", func(ctx iris.Context) {
+		id, err := primitive.ObjectIDFromHex(ctx.Params().GetString("id"))
+
+		if err != nil {
+			ctx.StatusCode(iris.StatusBadRequest)
+			return
+		}
+
+		run, err := GetRun(id)
+
+		if errors.Is(err, ErrRunNotFound) {
+			ctx.StatusCode(iris.StatusNotFound)
+			return
+		}
+
+		if err != nil {
+			golog.Errorf("Failed to fetch report run %s: %v", id.Hex(), err)
+			ctx.StatusCode(iris.StatusInternalServerError)
+			return
+		}
+
+		name := ctx.Params().GetString("name")
+		fPath, err := FilePath(cfg, run, name)
+
+		if err != nil {
+			ctx.StatusCode(iris.StatusNotFound)
+			return
+		}
+
+		ctx.SendFile(fPath, name)
+	})
+
+	app.Post("/reports/compare", func(ctx iris.Context) {
+		reqData := struct {
+			A primitive.ObjectID `json:"a"`
+			B primitive.ObjectID `json:"b"`
+		}{}
+
+		if !g.ReadJSON(ctx, &reqData) {
+			return
+		}
+
+		comparison, err := Compare(reqData.A, reqData.B)
+
+		if err != nil {
+			ctx.JSON(g.Resp{
+				Result: false,
+				Data:   err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(g.Resp{
+			Result: true,
+			Data:   comparison,
+		})
+	})
+
+	app.Post("/reports/runs/delete", func(ctx iris.Context) {
+		reqData := struct {
+			ID primitive.ObjectID `json:"_id"`
+		}{}
+
+		if !g.ReadJSON(ctx, &reqData) {
+			return
+		}
+
+		if err := DeleteRun(cfg, reqData.ID); err != nil {
+			applog.Send(applog.Error, fmt.Sprintf("Failed to delete report run: %s", err.Error()))
+
+			ctx.JSON(g.Resp{
+				Result: false,
+			})
+			return
+		}
+
+		g.PushToClients("report-run-deleted", map[string]any{"_id": reqData.ID})
+
+		ctx.JSON(g.Resp{
+			Result: true,
+		})
+	})
+}
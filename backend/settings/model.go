@@ -3,7 +3,9 @@ package settings
 import (
 	"context"
 
+	"github.com/f-taxes/f-taxes/backend/events"
 	. "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/f-taxes/f-taxes/backend/history"
 	"github.com/kataras/golog"
 	"github.com/qiniu/qmgo"
 	"github.com/thlib/go-timezone-local/tzlocal"
@@ -134,7 +136,7 @@ func ensureDefaultSettings() {
 		}
 	}
 
-	err = Save(s)
+	err = Save(s, history.Actor{Source: history.SOURCE_SYSTEM, Name: "default settings"})
 
 	if err != nil {
 		golog.Errorf("Failed to save default settings document from database: %v", err)
@@ -159,7 +161,8 @@ func Get() (UserSettings, error) {
 }
 
 // This code ensures we only store one document with settings.
-func Save(updatedSettings UserSettings) error {
+// The change is recorded in the history on behalf of the given actor.
+func Save(updatedSettings UserSettings, actor history.Actor) error {
 	s, err := Get()
 
 	if err != nil {
@@ -168,7 +171,25 @@ func Save(updatedSettings UserSettings) error {
 
 	updatedSettings.ID = s.ID
 
+	before, err := history.Current(COL_SETTINGS, s.ID)
+	if err != nil {
+		return err
+	}
+
 	col := DBConn.Collection(COL_SETTINGS)
 	_, err = col.UpsertId(context.Background(), s.ID, updatedSettings)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if after, err := history.Current(COL_SETTINGS, s.ID); err == nil {
+		history.Record(COL_SETTINGS, s.ID, before, after, actor)
+	}
+
+	// Plugins only see the settings returned by GetSettings, changes to the tables don't concern them.
+	if s.DateTimeFormat != updatedSettings.DateTimeFormat || s.TimeZone != updatedSettings.TimeZone {
+		events.Publish(events.Event{Type: events.SETTINGS_CHANGED})
+	}
+
+	return nil
 }
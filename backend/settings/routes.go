@@ -2,6 +2,7 @@ package settings
 
 import (
 	. "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/f-taxes/f-taxes/backend/history"
 	"github.com/kataras/golog"
 	"github.com/kataras/iris/v12"
 )
@@ -32,7 +33,7 @@ func RegisterRoutes(app *iris.Application) {
 			return
 		}
 
-		err := Save(reqData)
+		err := Save(reqData, history.UI())
 
 		if err != nil {
 			golog.Errorf("Failed to save settings: %v", err)
@@ -0,0 +1,245 @@
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Snapshot files are encrypted in chunks, so they can be written and read as a stream.
+// Each chunk is sealed with AES-GCM. The nonce consists of a random prefix, the chunk counter and a flag marking the last chunk,
+// which makes reordered, dropped or truncated chunks fail authentication.
+const encChunkSize = 64 * 1024
+const encNoncePrefixSize = 7
+
+var encMagic = []byte("FTAXENC1")
+
+var ErrPassphraseRequired = errors.New("passphrase required")
+var ErrWrongPassphrase = errors.New("wrong passphrase")
+
+// Parameters used to derive the key of an encrypted snapshot from the user's passphrase.
+type Encryption struct {
+	Kdf     string `json:"kdf"`
+	Salt    []byte `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"` // In KiB.
+	Threads uint8  `json:"threads"`
+	// MAC of a fixed message with the derived key. Tells a wrong passphrase apart from a corrupted file.
+	// Snapshots created before it was added don't have one.
+	KeyCheck []byte `json:"keyCheck,omitempty"`
+}
+
+func newEncryption() (Encryption, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return Encryption{}, err
+	}
+
+	return Encryption{
+		Kdf:     "argon2id",
+		Salt:    salt,
+		Time:    3,
+		Memory:  64 * 1024,
+		Threads: 4,
+	}, nil
+}
+
+func (e Encryption) deriveKey(passphrase string) ([]byte, error) {
+	if e.Kdf != "argon2id" {
+		return nil, fmt.Errorf("unsupported key derivation function '%s'", e.Kdf)
+	}
+
+	return argon2.IDKey([]byte(passphrase), e.Salt, e.Time, e.Memory, e.Threads, 32), nil
+}
+
+func keyCheck(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("f-taxes snapshot key check"))
+	return mac.Sum(nil)
+}
+
+// Fails with ErrWrongPassphrase if the key wasn't derived from the passphrase the snapshot was created with.
+func (e Encryption) verifyKey(key []byte) error {
+	if e.KeyCheck != nil && !hmac.Equal(e.KeyCheck, keyCheck(key)) {
+		return ErrWrongPassphrase
+	}
+
+	return nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+type encryptingWriter struct {
+	dst     io.Writer
+	aead    cipher.AEAD
+	nonce   []byte
+	counter uint32
+	buf     []byte
+}
+
+func newEncryptingWriter(dst io.Writer, key []byte) (io.WriteCloser, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &encryptingWriter{
+		dst:   dst,
+		aead:  aead,
+		nonce: make([]byte, aead.NonceSize()),
+		buf:   make([]byte, 0, encChunkSize),
+	}
+
+	if _, err := rand.Read(w.nonce[:encNoncePrefixSize]); err != nil {
+		return nil, err
+	}
+
+	if _, err := dst.Write(append(append([]byte{}, encMagic...), w.nonce[:encNoncePrefixSize]...)); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *encryptingWriter) Write(p []byte) (int, error) {
+	written := 0
+
+	for len(p) > 0 {
+		// A full chunk is only sealed once more data arrives, so the last chunk can always be flagged as such on Close.
+		if len(w.buf) == encChunkSize {
+			if err := w.flush(false); err != nil {
+				return written, err
+			}
+		}
+
+		n := min(encChunkSize-len(w.buf), len(p))
+		w.buf = append(w.buf, p[:n]...)
+		p = p[n:]
+		written += n
+	}
+
+	return written, nil
+}
+
+func (w *encryptingWriter) flush(last bool) error {
+	if w.counter == ^uint32(0) {
+		return errors.New("snapshot file is too large to be encrypted")
+	}
+
+	setChunkNonce(w.nonce, w.counter, last)
+	_, err := w.dst.Write(w.aead.Seal(nil, w.nonce, w.buf, nil))
+	w.buf = w.buf[:0]
+	w.counter++
+	return err
+}
+
+// Seals the remaining data as the last chunk. Doesn't close the underlying writer.
+func (w *encryptingWriter) Close() error {
+	return w.flush(true)
+}
+
+type decryptingReader struct {
+	src     *bufio.Reader
+	aead    cipher.AEAD
+	nonce   []byte
+	counter uint32
+	plain   []byte
+	done    bool
+}
+
+func newDecryptingReader(src io.Reader, key []byte) (io.Reader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &decryptingReader{
+		src:   bufio.NewReader(src),
+		aead:  aead,
+		nonce: make([]byte, aead.NonceSize()),
+	}
+
+	header := make([]byte, len(encMagic)+encNoncePrefixSize)
+	if _, err := io.ReadFull(r.src, header); err != nil || !bytes.Equal(header[:len(encMagic)], encMagic) {
+		return nil, errors.New("file isn't an encrypted snapshot file")
+	}
+
+	copy(r.nonce, header[len(encMagic):])
+	return r, nil
+}
+
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	for len(r.plain) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		if err := r.readChunk(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.plain)
+	r.plain = r.plain[n:]
+	return n, nil
+}
+
+func (r *decryptingReader) readChunk() error {
+	chunk := make([]byte, encChunkSize+r.aead.Overhead())
+	n, err := io.ReadFull(r.src, chunk)
+	last := false
+
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		last = true
+	case err != nil:
+		return err
+	default:
+		_, err := r.src.Peek(1)
+		last = err == io.EOF
+	}
+
+	setChunkNonce(r.nonce, r.counter, last)
+	plain, err := r.aead.Open(nil, r.nonce, chunk[:n], nil)
+
+	if err != nil {
+		// A first chunk that fails with either value of the last-chunk flag was sealed with a different key.
+		// Only relevant for snapshots without a key check, the passphrase of others was verified before.
+		setChunkNonce(r.nonce, r.counter, !last)
+		if _, flippedErr := r.aead.Open(nil, r.nonce, chunk[:n], nil); r.counter == 0 && flippedErr != nil {
+			return ErrWrongPassphrase
+		}
+		return errors.New("encrypted snapshot file is corrupted")
+	}
+
+	r.plain = plain
+	r.done = last
+	r.counter++
+	return nil
+}
+
+func setChunkNonce(nonce []byte, counter uint32, last bool) {
+	binary.BigEndian.PutUint32(nonce[encNoncePrefixSize:], counter)
+	nonce[len(nonce)-1] = 0
+
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+}
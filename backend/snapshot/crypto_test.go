@@ -0,0 +1,145 @@
+package snapshot
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+)
+
+func randomKey(t *testing.T) []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func encrypt(t *testing.T, key, plain []byte) []byte {
+	buf := bytes.Buffer{}
+
+	w, err := newEncryptingWriter(&buf, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := w.Write(plain); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func decrypt(key, sealed []byte) ([]byte, error) {
+	r, err := newDecryptingReader(bytes.NewReader(sealed), key)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(r)
+}
+
+func TestEncryptionRoundTrip(t *testing.T) {
+	key := randomKey(t)
+
+	// Sizes around chunk boundaries, where the last chunk is either full or empty.
+	for _, size := range []int{0, 1, encChunkSize - 1, encChunkSize, encChunkSize + 1, 3 * encChunkSize} {
+		plain := make([]byte, size)
+		rand.Read(plain)
+
+		got, err := decrypt(key, encrypt(t, key, plain))
+		if err != nil {
+			t.Fatalf("size %d: %v", size, err)
+		}
+
+		if !bytes.Equal(got, plain) {
+			t.Fatalf("size %d: decrypted data differs", size)
+		}
+	}
+}
+
+func TestEncryptionDetectsTruncation(t *testing.T) {
+	key := randomKey(t)
+	plain := make([]byte, 2*encChunkSize+100)
+	sealed := encrypt(t, key, plain)
+
+	header := len(encMagic) + encNoncePrefixSize
+	sealedChunk := encChunkSize + 16
+
+	// Dropping the last chunk leaves a file that ends after a chunk that wasn't flagged as the last one.
+	if _, err := decrypt(key, sealed[:header+2*sealedChunk]); err == nil {
+		t.Fatal("file without its last chunk was decrypted")
+	}
+
+	if _, err := decrypt(key, sealed[:len(sealed)-1]); err == nil {
+		t.Fatal("file with a truncated last chunk was decrypted")
+	}
+}
+
+func TestEncryptionDetectsReorderedChunks(t *testing.T) {
+	key := randomKey(t)
+	plain := make([]byte, 3*encChunkSize)
+	rand.Read(plain)
+	sealed := encrypt(t, key, plain)
+
+	header := len(encMagic) + encNoncePrefixSize
+	sealedChunk := encChunkSize + 16
+	first := sealed[header : header+sealedChunk]
+	second := sealed[header+sealedChunk : header+2*sealedChunk]
+
+	swapped := append([]byte{}, sealed[:header]...)
+	swapped = append(swapped, second...)
+	swapped = append(swapped, first...)
+	swapped = append(swapped, sealed[header+2*sealedChunk:]...)
+
+	if _, err := decrypt(key, swapped); err == nil {
+		t.Fatal("file with reordered chunks was decrypted")
+	}
+}
+
+func TestEncryptionWrongKey(t *testing.T) {
+	sealed := encrypt(t, randomKey(t), []byte("trades"))
+
+	if _, err := decrypt(randomKey(t), sealed); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("expected ErrWrongPassphrase, got %v", err)
+	}
+}
+
+func TestEncryptionKeyCheck(t *testing.T) {
+	enc, err := newEncryption()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := enc.deriveKey("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enc.KeyCheck = keyCheck(key)
+
+	if err := enc.verifyKey(key); err != nil {
+		t.Fatalf("key derived from the right passphrase was rejected: %v", err)
+	}
+
+	wrong, err := enc.deriveKey("battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := enc.verifyKey(wrong); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("expected ErrWrongPassphrase, got %v", err)
+	}
+
+	// Snapshots created before key checks were added can't be verified up front.
+	enc.KeyCheck = nil
+	if err := enc.verifyKey(wrong); err != nil {
+		t.Fatalf("snapshot without key check was rejected: %v", err)
+	}
+}
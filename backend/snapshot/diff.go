@@ -0,0 +1,420 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+	"time"
+
+	g "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/knadh/koanf"
+	"github.com/qiniu/qmgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var errNotSorted = errors.New("records are not sorted by the requested key")
+
+// Describes which two states of the database should be compared.
+// A nil timestamp refers to the live data in the database.
+type DiffRequest struct {
+	From           *time.Time `json:"from"`
+	FromPassphrase string     `json:"fromPassphrase"` // Required if the snapshot referenced by From is encrypted.
+	To             *time.Time `json:"to"`
+	ToPassphrase   string     `json:"toPassphrase"`
+	Key            string     `json:"key"`   // Field used to match records. Either "_id" (default) or "txId", which matches on plugin, account and txId.
+	Limit          int        `json:"limit"` // Maximum number of added, removed and modified records returned per collection.
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+type ModifiedRecord struct {
+	Key     string        `json:"key"` // The record's _id, or plugin, account and txId separated by "/" when matching by txId.
+	Changes []FieldChange `json:"changes"`
+}
+
+type CollectionDiff struct {
+	Added         []map[string]any `json:"added"`
+	Removed       []map[string]any `json:"removed"`
+	Modified      []ModifiedRecord `json:"modified"`
+	AddedCount    int64            `json:"addedCount"`
+	RemovedCount  int64            `json:"removedCount"`
+	ModifiedCount int64            `json:"modifiedCount"`
+	Truncated     bool             `json:"truncated"`  // True if there are more differences than the limit allowed to return.
+	Duplicates    []string         `json:"duplicates"` // Keys that match more than one record in either state. Such records may be reported as added and removed.
+}
+
+type Diff struct {
+	Trades    CollectionDiff `json:"trades"`
+	Transfers CollectionDiff `json:"transfers"`
+}
+
+// A single record prepared for comparison.
+type diffRecord struct {
+	key   string // Sorts like the fields returned by sortFields.
+	label string
+	doc   map[string]any
+	flat  map[string]any
+}
+
+// Fields records are sorted by to match them by key. Records without a txId are matched by their _id instead.
+func sortFields(key string) []string {
+	if key == "txId" {
+		return []string{"plugin", "account", "txId", "_id"}
+	}
+
+	return []string{"_id"}
+}
+
+// Yields records in ascending order of their key.
+type recordSource interface {
+	next() (diffRecord, bool, error)
+	close()
+}
+
+// Compares trades and transfers of two snapshots or of a snapshot and the live data.
+// Both sides are read in key order and merged, so only one record per side is held in memory at a time.
+func Compare(cfg *koanf.Koanf, req DiffRequest) (Diff, error) {
+	out := Diff{}
+
+	if req.From == nil && req.To == nil {
+		return out, fmt.Errorf("at least one snapshot is required")
+	}
+
+	if req.Key == "" {
+		req.Key = "_id"
+	}
+
+	if req.Key != "_id" && req.Key != "txId" {
+		return out, fmt.Errorf("records can't be compared by '%s'", req.Key)
+	}
+
+	if req.Limit <= 0 {
+		req.Limit = 1000
+	}
+
+	var fromArchive, toArchive *archive
+
+	for _, side := range []struct {
+		ts         *time.Time
+		passphrase string
+		archive    **archive
+	}{{req.From, req.FromPassphrase, &fromArchive}, {req.To, req.ToPassphrase, &toArchive}} {
+		if side.ts == nil {
+			continue
+		}
+
+		a, err := openArchive(cfg, *side.ts, side.passphrase)
+		if err != nil {
+			return out, err
+		}
+
+		*side.archive = &a
+	}
+
+	var err error
+
+	out.Trades, err = compareCollection[g.Trade](fromArchive, toArchive, req, g.COL_TRADES, "trades.json")
+	if err != nil {
+		return out, err
+	}
+
+	out.Transfers, err = compareCollection[g.Transfer](fromArchive, toArchive, req, g.COL_TRANSFERS, "transfers.json")
+	return out, err
+}
+
+// Compares a collection between two archives. A nil archive refers to the live data.
+func compareCollection[T any](fromArchive, toArchive *archive, req DiffRequest, colName, fileName string) (CollectionDiff, error) {
+	out := CollectionDiff{
+		Added:      []map[string]any{},
+		Removed:    []map[string]any{},
+		Modified:   []ModifiedRecord{},
+		Duplicates: []string{},
+	}
+
+	from, err := openRecordSource[T](fromArchive, colName, fileName, req.Key)
+	if err != nil {
+		return out, err
+	}
+	defer from.close()
+
+	to, err := openRecordSource[T](toArchive, colName, fileName, req.Key)
+	if err != nil {
+		return out, err
+	}
+	defer to.close()
+
+	// Reads the next record of a side and reports its key if the previous record had the same one.
+	next := func(src recordSource, prev diffRecord, hasPrev bool) (diffRecord, bool, error) {
+		r, ok, err := src.next()
+		if ok && hasPrev && r.key == prev.key && !slices.Contains(out.Duplicates, r.label) && len(out.Duplicates) < req.Limit {
+			out.Duplicates = append(out.Duplicates, r.label)
+		}
+
+		return r, ok, err
+	}
+
+	a, hasA, err := next(from, diffRecord{}, false)
+	if err != nil {
+		return out, err
+	}
+
+	b, hasB, err := next(to, diffRecord{}, false)
+	if err != nil {
+		return out, err
+	}
+
+	for hasA || hasB {
+		switch {
+		case hasA && (!hasB || a.key < b.key):
+			out.RemovedCount++
+			if len(out.Removed) < req.Limit {
+				out.Removed = append(out.Removed, a.doc)
+			}
+
+			if a, hasA, err = next(from, a, hasA); err != nil {
+				return out, err
+			}
+		case hasB && (!hasA || b.key < a.key):
+			out.AddedCount++
+			if len(out.Added) < req.Limit {
+				out.Added = append(out.Added, b.doc)
+			}
+
+			if b, hasB, err = next(to, b, hasB); err != nil {
+				return out, err
+			}
+		default:
+			if changes := compareFields(a.flat, b.flat); len(changes) > 0 {
+				out.ModifiedCount++
+				if len(out.Modified) < req.Limit {
+					out.Modified = append(out.Modified, ModifiedRecord{Key: a.label, Changes: changes})
+				}
+			}
+
+			if a, hasA, err = next(from, a, hasA); err != nil {
+				return out, err
+			}
+
+			if b, hasB, err = next(to, b, hasB); err != nil {
+				return out, err
+			}
+		}
+	}
+
+	out.Truncated = out.AddedCount > int64(len(out.Added)) || out.RemovedCount > int64(len(out.Removed)) || out.ModifiedCount > int64(len(out.Modified))
+	return out, nil
+}
+
+func compareFields(a, b map[string]any) []FieldChange {
+	fields := []string{}
+
+	for f := range a {
+		fields = append(fields, f)
+	}
+
+	for f := range b {
+		if _, ok := a[f]; !ok {
+			fields = append(fields, f)
+		}
+	}
+
+	sort.Strings(fields)
+	changes := []FieldChange{}
+
+	for _, f := range fields {
+		if !reflect.DeepEqual(a[f], b[f]) {
+			changes = append(changes, FieldChange{Field: f, From: a[f], To: b[f]})
+		}
+	}
+
+	return changes
+}
+
+// Opens the records of a collection either from the live database (a is nil) or from a snapshot.
+// Snapshot files that aren't sorted by the key are loaded into a temporary collection to let the database sort them.
+func openRecordSource[T any](a *archive, colName, fileName, key string) (recordSource, error) {
+	if a == nil {
+		cursor := g.DBConn.Collection(colName).Find(context.Background(), bson.M{}).Sort(sortFields(key)...).Cursor()
+		return &cursorSource[T]{cursor: cursor, key: key}, nil
+	}
+
+	sorted, err := isFileSorted[T](*a, fileName, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if sorted {
+		return openFileSource[T](*a, fileName, key)
+	}
+
+	tmpCol := g.DBConn.Collection(fmt.Sprintf("%s_diff_%s", colName, primitive.NewObjectID().Hex()))
+	if _, err := restoreCollection[T](tmpCol, *a, fileName); err != nil {
+		tmpCol.DropCollection(context.Background())
+		return nil, err
+	}
+
+	return &cursorSource[T]{
+		cursor: tmpCol.Find(context.Background(), bson.M{}).Sort(sortFields(key)...).Cursor(),
+		key:    key,
+		onClose: func() {
+			tmpCol.DropCollection(context.Background())
+		},
+	}, nil
+}
+
+func isFileSorted[T any](a archive, fileName, key string) (bool, error) {
+	src, err := openFileSource[T](a, fileName, key)
+	if err != nil {
+		return false, err
+	}
+	defer src.close()
+
+	for {
+		_, ok, err := src.next()
+		if errors.Is(err, errNotSorted) {
+			return false, nil
+		}
+
+		if err != nil || !ok {
+			return err == nil, err
+		}
+	}
+}
+
+type cursorSource[T any] struct {
+	cursor  qmgo.CursorI
+	key     string
+	onClose func()
+}
+
+func (s *cursorSource[T]) next() (diffRecord, bool, error) {
+	var doc T
+	if !s.cursor.Next(&doc) {
+		return diffRecord{}, false, s.cursor.Err()
+	}
+
+	r, err := toDiffRecord(doc, s.key)
+	return r, err == nil, err
+}
+
+func (s *cursorSource[T]) close() {
+	s.cursor.Close()
+
+	if s.onClose != nil {
+		s.onClose()
+	}
+}
+
+type fileSource[T any] struct {
+	file    io.ReadCloser
+	decoder *json.Decoder
+	key     string
+	lastKey string
+	started bool
+}
+
+func openFileSource[T any](a archive, fileName, key string) (*fileSource[T], error) {
+	file, err := a.open(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileSource[T]{
+		file:    file,
+		decoder: json.NewDecoder(file),
+		key:     key,
+	}, nil
+}
+
+func (s *fileSource[T]) next() (diffRecord, bool, error) {
+	if !s.decoder.More() {
+		return diffRecord{}, false, nil
+	}
+
+	var doc T
+	if err := s.decoder.Decode(&doc); err != nil {
+		return diffRecord{}, false, err
+	}
+
+	r, err := toDiffRecord(doc, s.key)
+	if err != nil {
+		return r, false, err
+	}
+
+	if s.started && r.key < s.lastKey {
+		return r, false, errNotSorted
+	}
+
+	s.started = true
+	s.lastKey = r.key
+	return r, true, nil
+}
+
+func (s *fileSource[T]) close() {
+	s.file.Close()
+}
+
+// Converts a trade or transfer into its JSON representation, so that records from files and from the database look alike.
+func toDiffRecord(doc any, key string) (diffRecord, error) {
+	r := diffRecord{
+		doc:  map[string]any{},
+		flat: map[string]any{},
+	}
+
+	content, err := json.Marshal(doc)
+	if err != nil {
+		return r, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&r.doc); err != nil {
+		return r, err
+	}
+
+	flatten("", r.doc, r.flat)
+	r.key, r.label = recordKey(r.doc, key)
+	return r, nil
+}
+
+// Builds the key a record is matched by and a readable form of it.
+// The parts are separated by \x00, so comparing keys orders records like sorting them by sortFields.
+func recordKey(doc map[string]any, key string) (string, string) {
+	id := fmt.Sprintf("%v", doc["_id"])
+
+	if key != "txId" {
+		return id, id
+	}
+
+	plugin, account, txID := fmt.Sprintf("%v", doc["plugin"]), fmt.Sprintf("%v", doc["account"]), fmt.Sprintf("%v", doc["txId"])
+
+	if txID == "" {
+		return plugin + "\x00" + account + "\x00\x00" + id, id
+	}
+
+	return plugin + "\x00" + account + "\x00" + txID, plugin + "/" + account + "/" + txID
+}
+
+// Flattens nested objects into a single map with dot separated field names (e.g. "fee.amount").
+func flatten(prefix string, doc map[string]any, out map[string]any) {
+	for k, v := range doc {
+		if nested, ok := v.(map[string]any); ok {
+			flatten(prefix+k+".", nested, out)
+			continue
+		}
+
+		out[prefix+k] = v
+	}
+}
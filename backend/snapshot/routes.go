@@ -1,6 +1,7 @@
 package snapshot
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -12,7 +13,15 @@ import (
 
 func RegisterRoutes(app iris.Party, cfg *koanf.Koanf) {
 	app.Post("/snapshots/create", func(ctx iris.Context) {
-		err := Create(cfg)
+		reqData := struct {
+			Passphrase string `json:"passphrase"`
+		}{}
+
+		if ctx.GetContentLength() > 0 && !g.ReadJSON(ctx, &reqData) {
+			return
+		}
+
+		err := Create(cfg, reqData.Passphrase)
 
 		if err != nil {
 			applog.Send(applog.Error, fmt.Sprintf("Failed to create snapshot: %v.", err.Error()))
@@ -50,17 +59,27 @@ func RegisterRoutes(app iris.Party, cfg *koanf.Koanf) {
 
 	app.Post("/snapshots/restore", func(ctx iris.Context) {
 		reqData := struct {
-			Ts time.Time `json:"ts"`
+			Ts         time.Time `json:"ts"`
+			Passphrase string    `json:"passphrase"`
 		}{}
 
 		if !g.ReadJSON(ctx, &reqData) {
 			return
 		}
 
-		err := RestoreFromSnapshot(cfg, reqData.Ts)
+		err := RestoreFromSnapshot(cfg, reqData.Ts, reqData.Passphrase)
+
+		// Lets the UI ask the user for the passphrase and try again.
+		if errors.Is(err, ErrPassphraseRequired) || errors.Is(err, ErrWrongPassphrase) {
+			ctx.JSON(g.Resp{
+				Result: false,
+				Data:   err.Error(),
+			})
+			return
+		}
 
 		if err != nil {
-			applog.Send(applog.Error, fmt.Sprintf("Failed to restore snapshot %s: %v.", reqData.Ts.Format("2006-01-02T15_04_05"), err.Error()))
+			applog.Send(applog.Error, fmt.Sprintf("Failed to restore snapshot %s: %v.", reqData.Ts.Format(FOLDER_FORMAT), err.Error()))
 
 			ctx.JSON(g.Resp{
 				Result: false,
@@ -73,6 +92,39 @@ func RegisterRoutes(app iris.Party, cfg *koanf.Koanf) {
 		})
 	})
 
+	app.Post("/snapshots/diff", func(ctx iris.Context) {
+		reqData := DiffRequest{}
+
+		if !g.ReadJSON(ctx, &reqData) {
+			return
+		}
+
+		diff, err := Compare(cfg, reqData)
+
+		if errors.Is(err, ErrPassphraseRequired) || errors.Is(err, ErrWrongPassphrase) {
+			ctx.JSON(g.Resp{
+				Result: false,
+				Data:   err.Error(),
+			})
+			return
+		}
+
+		if err != nil {
+			applog.Send(applog.Error, fmt.Sprintf("Failed to compare snapshots: %v.", err.Error()))
+
+			ctx.JSON(g.Resp{
+				Result: false,
+				Data:   err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(g.Resp{
+			Result: true,
+			Data:   diff,
+		})
+	})
+
 	app.Post("/snapshots/remove", func(ctx iris.Context) {
 		reqData := struct {
 			Ts time.Time `json:"ts"`
@@ -85,7 +137,7 @@ func RegisterRoutes(app iris.Party, cfg *koanf.Koanf) {
 		err := RemoveSnapshot(cfg, reqData.Ts)
 
 		if err != nil {
-			applog.Send(applog.Error, fmt.Sprintf("Failed to remove snapshot %s: %v.", reqData.Ts.Format("2006-01-02T15_04_05"), err.Error()))
+			applog.Send(applog.Error, fmt.Sprintf("Failed to remove snapshot %s: %v.", reqData.Ts.Format(FOLDER_FORMAT), err.Error()))
 
 			ctx.JSON(g.Resp{
 				Result: false,
@@ -93,7 +145,7 @@ func RegisterRoutes(app iris.Party, cfg *koanf.Koanf) {
 			return
 		}
 
-		applog.Send(applog.Info, fmt.Sprintf("Removed snapshot %s.", reqData.Ts.Format("2006-01-02T15_04_05")))
+		applog.Send(applog.Info, fmt.Sprintf("Removed snapshot %s.", reqData.Ts.Format(FOLDER_FORMAT)))
 		list, _ := ListSnapshots(cfg)
 
 		ctx.JSON(g.Resp{
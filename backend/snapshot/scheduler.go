@@ -0,0 +1,179 @@
+package snapshot
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/f-taxes/f-taxes/backend/applog"
+	"github.com/kataras/golog"
+	"github.com/knadh/koanf"
+	"github.com/robfig/cron/v3"
+)
+
+var appCfg *koanf.Koanf
+
+// Decides which automatic snapshots are kept. A snapshot is kept if any of the rules selects it.
+type RetentionPolicy struct {
+	KeepLast    int // Number of most recent snapshots to keep.
+	KeepDaily   int // Number of days for which the most recent snapshot of each day is kept.
+	KeepWeekly  int // Number of weeks for which the most recent snapshot of each week is kept.
+	KeepMonthly int // Number of months for which the most recent snapshot of each month is kept.
+}
+
+func retentionPolicyFromConfig(cfg *koanf.Koanf) RetentionPolicy {
+	return RetentionPolicy{
+		KeepLast:    cfg.Int("snapshots.retention.keepLast"),
+		KeepDaily:   cfg.Int("snapshots.retention.keepDaily"),
+		KeepWeekly:  cfg.Int("snapshots.retention.keepWeekly"),
+		KeepMonthly: cfg.Int("snapshots.retention.keepMonthly"),
+	}
+}
+
+// Returns the snapshots that aren't selected by any rule of the policy.
+// The most recent snapshot is always kept.
+func (p RetentionPolicy) Expired(snapshots []time.Time) []time.Time {
+	sorted := append([]time.Time{}, snapshots...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].After(sorted[j])
+	})
+
+	keep := map[time.Time]bool{}
+
+	for i := range sorted {
+		if i < max(p.KeepLast, 1) {
+			keep[sorted[i]] = true
+		}
+	}
+
+	keepPerPeriod := func(limit int, period func(t time.Time) string) {
+		seen := map[string]bool{}
+
+		for _, ts := range sorted {
+			if len(seen) >= limit {
+				return
+			}
+
+			key := period(ts)
+			if !seen[key] {
+				seen[key] = true
+				keep[ts] = true
+			}
+		}
+	}
+
+	keepPerPeriod(p.KeepDaily, func(t time.Time) string {
+		return t.Format("2006-01-02")
+	})
+
+	keepPerPeriod(p.KeepWeekly, func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-%d", year, week)
+	})
+
+	keepPerPeriod(p.KeepMonthly, func(t time.Time) string {
+		return t.Format("2006-01")
+	})
+
+	expired := []time.Time{}
+
+	for _, ts := range sorted {
+		if !keep[ts] {
+			expired = append(expired, ts)
+		}
+	}
+
+	return expired
+}
+
+// Starts taking snapshots according to the cron expression in "snapshots.schedule".
+// Also remembers the config to take snapshots before destructive operations.
+func Setup(cfg *koanf.Koanf) {
+	appCfg = cfg
+	schedule := cfg.String("snapshots.schedule")
+
+	if schedule == "" {
+		golog.Debug("No snapshot schedule configured")
+		return
+	}
+
+	scheduler := cron.New()
+	_, err := scheduler.AddFunc(schedule, func() {
+		if err := CreateAutomatic(cfg, "scheduled"); err != nil {
+			applog.Send(applog.Error, fmt.Sprintf("Failed to create scheduled snapshot: %v.", err.Error()), "Snapshot")
+			return
+		}
+
+		Prune(cfg)
+	})
+
+	if err != nil {
+		golog.Errorf("Invalid snapshot schedule '%s': %v", schedule, err)
+		applog.Send(applog.Error, fmt.Sprintf("The snapshot schedule '%s' is invalid. No snapshots will be taken automatically.", schedule), "Snapshot")
+		return
+	}
+
+	golog.Infof("Taking snapshots on schedule '%s'", schedule)
+	scheduler.Start()
+}
+
+// Takes a snapshot before an operation that destroys data. The operation should be aborted if an error is returned.
+func BeforeDestructiveOperation(operation string) error {
+	if appCfg == nil || !appCfg.Bool("snapshots.beforeDestructive") {
+		return nil
+	}
+
+	if err := CreateAutomatic(appCfg, "before "+operation); err != nil {
+		applog.Send(applog.Error, fmt.Sprintf("Failed to create snapshot before %s: %v.", operation, err.Error()), "Snapshot")
+		return err
+	}
+
+	Prune(appCfg)
+	return nil
+}
+
+// Removes automatic snapshots that are no longer covered by the retention policy.
+func Prune(cfg *koanf.Koanf) {
+	snapshots, err := listAutomaticSnapshots(cfg)
+	if err != nil {
+		applog.Send(applog.Error, fmt.Sprintf("Failed to list snapshots for pruning: %v.", err.Error()), "Snapshot")
+		return
+	}
+
+	expired := retentionPolicyFromConfig(cfg).Expired(snapshots)
+	removed := 0
+
+	for _, ts := range expired {
+		if err := RemoveSnapshot(cfg, ts); err != nil {
+			applog.Send(applog.Error, fmt.Sprintf("Failed to remove expired snapshot %s: %v.", ts.Format(FOLDER_FORMAT), err.Error()), "Snapshot")
+			continue
+		}
+
+		removed++
+	}
+
+	if removed > 0 {
+		applog.Send(applog.Info, fmt.Sprintf("Removed %d expired snapshots.", removed), "Snapshot")
+	}
+}
+
+func listAutomaticSnapshots(cfg *koanf.Koanf) ([]time.Time, error) {
+	snapshots, err := ListSnapshots(cfg)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	automatic := []time.Time{}
+
+	for _, s := range snapshots {
+		if s.Automatic {
+			automatic = append(automatic, s.Ts)
+		}
+	}
+
+	return automatic, nil
+}
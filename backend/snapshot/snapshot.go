@@ -3,7 +3,9 @@ package snapshot
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -16,139 +18,492 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 )
 
-func Create(cfg *koanf.Koanf) error {
+// Names of snapshot folders. Snapshots taken before milliseconds were added to the name use LEGACY_FOLDER_FORMAT.
+const FOLDER_FORMAT = "2006-01-02T15_04_05.000"
+const LEGACY_FOLDER_FORMAT = "2006-01-02T15_04_05"
+
+// Environment variable holding the passphrase automatic snapshots are encrypted with.
+// Takes precedence over "snapshots.passphrase", which keeps the passphrase in plain text.
+const ENV_PASSPHRASE = "FTAXES_SNAPSHOT_PASSPHRASE"
+
+// Creates a snapshot on behalf of the user. These are never pruned automatically.
+// The snapshot is encrypted if a passphrase is given.
+func Create(cfg *koanf.Koanf, passphrase string) error {
+	return create(cfg, Manifest{Reason: "manual"}, passphrase)
+}
+
+// Creates a snapshot that is subject to the retention policy configured in "snapshots.retention".
+// It is encrypted if a passphrase is configured, see automaticPassphrase.
+func CreateAutomatic(cfg *koanf.Koanf, reason string) error {
+	return create(cfg, Manifest{Automatic: true, Reason: reason}, automaticPassphrase(cfg))
+}
+
+func automaticPassphrase(cfg *koanf.Koanf) string {
+	if passphrase := os.Getenv(ENV_PASSPHRASE); passphrase != "" {
+		return passphrase
+	}
+
+	return cfg.String("snapshots.passphrase")
+}
+
+func create(cfg *koanf.Koanf, manifest Manifest, passphrase string) error {
 	// Make sure the snapshots directory exists.
 	err := os.MkdirAll(cfg.MustString("snapshots.path"), 0755)
 	if err != nil {
 		return err
 	}
 
-	// Create timestamped folder for the new snapshot.
-	folderName := time.Now().UTC().Format("2006-01-02T15_04_05")
-	snapshotPath := filepath.Join(cfg.MustString("snapshots.path"), folderName)
-	err = os.MkdirAll(snapshotPath, 0755)
+	// Create timestamped folder for the new snapshot. An existing snapshot is never written into.
+	a := archive{
+		path: filepath.Join(cfg.MustString("snapshots.path"), time.Now().UTC().Format(FOLDER_FORMAT)),
+	}
+
+	err = os.Mkdir(a.path, 0755)
+	if os.IsExist(err) {
+		return fmt.Errorf("snapshot %s already exists", filepath.Base(a.path))
+	}
+
 	if err != nil {
 		return err
 	}
 
+	if passphrase != "" {
+		enc, err := newEncryption()
+		if err != nil {
+			return err
+		}
+
+		if a.key, err = enc.deriveKey(passphrase); err != nil {
+			return err
+		}
+
+		enc.KeyCheck = keyCheck(a.key)
+
+		manifest.Encryption = &enc
+	}
+
 	var tradeCount int64
-	if tradeCount, err = exportCollection[g.Trade](g.DBConn.Collection(g.COL_TRADES), filepath.Join(snapshotPath, "trades.json")); err != nil {
+	if tradeCount, err = exportCollection[g.Trade](g.DBConn.Collection(g.COL_TRADES), a, "trades.json"); err != nil {
 		return err
 	}
 
 	var txCount int64
-	if txCount, err = exportCollection[g.Transfer](g.DBConn.Collection(g.COL_TRANSFERS), filepath.Join(snapshotPath, "transfers.json")); err != nil {
+	if txCount, err = exportCollection[g.Transfer](g.DBConn.Collection(g.COL_TRANSFERS), a, "transfers.json"); err != nil {
+		return err
+	}
+
+	manifest.Trades = tradeCount
+	manifest.Transfers = txCount
+
+	err = a.writeManifest(manifest)
+	if err != nil {
 		return err
 	}
 
-	applog.Send(applog.Info, fmt.Sprintf("Wrote %d trades and %d transfers to %s.", tradeCount, txCount, snapshotPath))
+	applog.Send(applog.Info, fmt.Sprintf("Wrote %d trades and %d transfers to %s.", tradeCount, txCount, a.path))
 
 	return nil
 }
 
-func exportCollection[T any](col *qmgo.Collection, path string) (int64, error) {
-	// Write all trades to a file.
-	file, err := os.Create(path)
+// Describes the contents of a snapshot. Stored next to the exported collections and used to verify a restore.
+type Manifest struct {
+	Trades     int64       `json:"trades,omitempty"`
+	Transfers  int64       `json:"transfers,omitempty"`
+	Automatic  bool        `json:"automatic"`            // Automatic snapshots are pruned according to the retention policy.
+	Reason     string      `json:"reason"`               // Why the snapshot was taken, e.g. "manual", "scheduled" or "before /trades/clear".
+	Encryption *Encryption `json:"encryption,omitempty"` // Set if the snapshot is encrypted.
+}
+
+// Summary of a snapshot as presented to the user.
+type SnapshotInfo struct {
+	Ts        time.Time `json:"ts"`
+	Automatic bool      `json:"automatic"`
+	Reason    string    `json:"reason"`
+	Encrypted bool      `json:"encrypted"`
+	Trades    int64     `json:"trades"` // Counts of encrypted snapshots aren't revealed without the passphrase and are always zero.
+	Transfers int64     `json:"transfers"`
+}
+
+// A snapshot folder. Files of encrypted snapshots are transparently encrypted and decrypted with key.
+type archive struct {
+	path        string
+	key         []byte
+	manifest    Manifest
+	hasManifest bool // Snapshots created before manifests were introduced don't have one.
+}
+
+// Opens an existing snapshot. Encrypted snapshots require the passphrase they were created with.
+func openArchive(cfg *koanf.Koanf, ts time.Time, passphrase string) (archive, error) {
+	a := archive{
+		path: snapshotPath(cfg, ts),
+	}
+
+	manifest, hasManifest, err := readManifest(a.path)
 	if err != nil {
-		return 0, err
+		return a, fmt.Errorf("failed to read snapshot manifest: %w", err)
+	}
+
+	a.manifest = manifest
+	a.hasManifest = hasManifest
+
+	if manifest.Encryption == nil {
+		return a, nil
+	}
+
+	if passphrase == "" {
+		return a, ErrPassphraseRequired
+	}
+
+	if a.key, err = manifest.Encryption.deriveKey(passphrase); err != nil {
+		return a, err
+	}
+
+	if err := manifest.Encryption.verifyKey(a.key); err != nil {
+		return a, err
+	}
+
+	// The full manifest including the counts is only stored encrypted. Reading it also verifies the passphrase.
+	r, err := a.open("manifest.json")
+	if err != nil {
+		return a, err
+	}
+	defer r.Close()
+
+	err = json.NewDecoder(r).Decode(&a.manifest)
+	return a, err
+}
+
+// Opens a file of the snapshot for reading.
+func (a archive) open(name string) (io.ReadCloser, error) {
+	if a.key == nil {
+		return os.Open(filepath.Join(a.path, name))
+	}
+
+	file, err := os.Open(filepath.Join(a.path, name+".enc"))
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := newDecryptingReader(file, a.key)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{r, file}, nil
+}
+
+// Writes a file of the snapshot.
+func (a archive) writeFile(name string, write func(w io.Writer) error) error {
+	if a.key != nil {
+		name += ".enc"
+	}
+
+	file, err := os.Create(filepath.Join(a.path, name))
+	if err != nil {
+		return err
 	}
 	defer file.Close()
 
-	encoder := json.NewEncoder(file)
-	cursor := col.Find(context.Background(), bson.M{}).Cursor()
-	var count int64
+	if a.key == nil {
+		if err := write(file); err != nil {
+			return err
+		}
 
-	for {
-		var doc T
-		if !cursor.Next(&doc) {
-			break
+		return file.Close()
+	}
+
+	w, err := newEncryptingWriter(file, a.key)
+	if err != nil {
+		return err
+	}
+
+	if err := write(w); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+// Encrypted snapshots only store the encryption parameters and the reason for their creation in plain text.
+func (a archive) writeManifest(manifest Manifest) error {
+	public := manifest
+
+	if a.key != nil {
+		err := a.writeFile("manifest.json", func(w io.Writer) error {
+			return json.NewEncoder(w).Encode(manifest)
+		})
+		if err != nil {
+			return err
 		}
 
-		count++
-		encoder.Encode(doc)
+		public.Trades = 0
+		public.Transfers = 0
 	}
 
-	return count, nil
+	content, err := json.Marshal(public)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(a.path, "manifest.json"), content, 0644)
+}
+
+// Reads the plain text manifest of a snapshot. If there is none, false is returned.
+func readManifest(snapshotPath string) (Manifest, bool, error) {
+	manifest := Manifest{}
+	content, err := os.ReadFile(filepath.Join(snapshotPath, "manifest.json"))
+
+	if errors.Is(err, os.ErrNotExist) {
+		return manifest, false, nil
+	}
+
+	if err != nil {
+		return manifest, false, err
+	}
+
+	err = json.Unmarshal(content, &manifest)
+	return manifest, err == nil, err
+}
+
+func exportCollection[T any](col *qmgo.Collection, a archive, name string) (int64, error) {
+	var count int64
+
+	err := a.writeFile(name, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		// Sorted by id so snapshots can be compared without loading them into the database first.
+		cursor := col.Find(context.Background(), bson.M{}).Sort("_id").Cursor()
+		defer cursor.Close()
+
+		for {
+			var doc T
+			if !cursor.Next(&doc) {
+				break
+			}
+
+			if err := encoder.Encode(doc); err != nil {
+				return err
+			}
+
+			count++
+		}
+
+		return cursor.Err()
+	})
+
+	return count, err
 }
 
 // Lists all available snapshots in the snapshots directory.
-func ListSnapshots(cfg *koanf.Koanf) ([]time.Time, error) {
+func ListSnapshots(cfg *koanf.Koanf) ([]SnapshotInfo, error) {
 	files, err := os.ReadDir(cfg.MustString("snapshots.path"))
 
 	if err != nil {
 		return nil, err
 	}
 
-	var snapshots []time.Time
+	snapshots := []SnapshotInfo{}
 
 	for _, file := range files {
 		if !file.IsDir() {
 			continue
 		}
 
-		// Parse the timestamp from the folder name.
-		ts, err := time.Parse("2006-01-02T15_04_05", file.Name())
+		// Parse the timestamp from the folder name. Milliseconds are parsed even though the layout doesn't mention them.
+		ts, err := time.Parse(LEGACY_FOLDER_FORMAT, file.Name())
 		if err != nil {
 			continue
 		}
-		snapshots = append(snapshots, ts)
+
+		manifest, _, err := readManifest(filepath.Join(cfg.MustString("snapshots.path"), file.Name()))
+		if err != nil {
+			golog.Warnf("Failed to read manifest of snapshot %s: %v", file.Name(), err)
+		}
+
+		snapshots = append(snapshots, SnapshotInfo{
+			Ts:        ts,
+			Automatic: manifest.Automatic,
+			Reason:    manifest.Reason,
+			Encrypted: manifest.Encryption != nil,
+			Trades:    manifest.Trades,
+			Transfers: manifest.Transfers,
+		})
 	}
 
 	return snapshots, nil
 }
 
 func RemoveSnapshot(cfg *koanf.Koanf, ts time.Time) error {
-	err := os.RemoveAll(filepath.Join(cfg.MustString("snapshots.path"), ts.Format("2006-01-02T15_04_05")))
+	err := os.RemoveAll(snapshotPath(cfg, ts))
 	return err
 }
 
-func RestoreFromSnapshot(cfg *koanf.Koanf, ts time.Time) error {
-	basePath := filepath.Join(cfg.MustString("snapshots.path"), ts.Format("2006-01-02T15_04_05"))
+// Folder of the snapshot taken at ts.
+func snapshotPath(cfg *koanf.Koanf, ts time.Time) string {
+	if ts.Nanosecond() == 0 {
+		legacy := filepath.Join(cfg.MustString("snapshots.path"), ts.Format(LEGACY_FOLDER_FORMAT))
+		if _, err := os.Stat(legacy); err == nil {
+			return legacy
+		}
+	}
+
+	return filepath.Join(cfg.MustString("snapshots.path"), ts.Format(FOLDER_FORMAT))
+}
 
-	tradesCount, err := restoreCollection[g.Trade](g.DBConn.Collection(g.COL_TRADES), filepath.Join(basePath, "trades.json"))
+// Restores trades and transfers from a snapshot.
+// The snapshot is first loaded into staging collections and verified against its manifest. Only then a snapshot of the
+// current data is taken and the staging collections are swapped in. If anything fails along the way the live data is left untouched.
+func RestoreFromSnapshot(cfg *koanf.Koanf, ts time.Time, passphrase string) error {
+	a, err := openArchive(cfg, ts, passphrase)
 	if err != nil {
 		return err
 	}
 
-	transfersCount, err := restoreCollection[g.Transfer](g.DBConn.Collection(g.COL_TRANSFERS), filepath.Join(basePath, "transfers.json"))
+	stagingTrades := g.COL_TRADES + "_restore"
+	stagingTransfers := g.COL_TRANSFERS + "_restore"
+	defer g.DBConn.Collection(stagingTrades).DropCollection(context.Background())
+	defer g.DBConn.Collection(stagingTransfers).DropCollection(context.Background())
+
+	tradesCount, err := restoreCollection[g.Trade](g.DBConn.Collection(stagingTrades), a, "trades.json")
 	if err != nil {
 		return err
 	}
 
-	golog.Infof("Restored %d trades and %d transfers from %s.", tradesCount, transfersCount, basePath)
+	transfersCount, err := restoreCollection[g.Transfer](g.DBConn.Collection(stagingTransfers), a, "transfers.json")
+	if err != nil {
+		return err
+	}
+
+	if a.hasManifest && (a.manifest.Trades != tradesCount || a.manifest.Transfers != transfersCount) {
+		return fmt.Errorf("snapshot is incomplete: expected %d trades and %d transfers but found %d trades and %d transfers", a.manifest.Trades, a.manifest.Transfers, tradesCount, transfersCount)
+	}
+
+	// Without a configured passphrase, the current data is protected with the passphrase of the restored snapshot.
+	backupPassphrase := automaticPassphrase(cfg)
+	if backupPassphrase == "" {
+		backupPassphrase = passphrase
+	}
+
+	if err := create(cfg, Manifest{Automatic: true, Reason: "before restoring " + ts.Format(FOLDER_FORMAT)}, backupPassphrase); err != nil {
+		return fmt.Errorf("failed to create snapshot of current data before restoring: %w", err)
+	}
+
+	err = swapCollections(map[string]string{
+		stagingTrades:    g.COL_TRADES,
+		stagingTransfers: g.COL_TRANSFERS,
+	})
+	if err != nil {
+		return err
+	}
+
+	golog.Infof("Restored %d trades and %d transfers from %s.", tradesCount, transfersCount, a.path)
+
+	return nil
+}
+
+// Replaces each target collection with its staging collection (staging -> target).
+// The live collections are moved aside first, so they can be put back if one of the renames fails.
+func swapCollections(staged map[string]string) error {
+	undo := []func(){}
+	rollback := func() {
+		for i := len(undo) - 1; i >= 0; i-- {
+			undo[i]()
+		}
+	}
+
+	for staging, target := range staged {
+		backup := target + "_prerestore"
+		exists, err := g.CollectionExists(target)
+
+		if err != nil {
+			rollback()
+			return err
+		}
+
+		if exists {
+			if err := g.RenameCollection(target, backup); err != nil {
+				rollback()
+				return err
+			}
+
+			undo = append(undo, func() {
+				if err := g.RenameCollection(backup, target); err != nil {
+					golog.Errorf("Failed to move collection %s back to %s: %v", backup, target, err)
+				}
+			})
+		}
+
+		if err := g.RenameCollection(staging, target); err != nil {
+			rollback()
+			return err
+		}
+
+		undo = append(undo, func() {
+			if err := g.RenameCollection(target, staging); err != nil {
+				golog.Errorf("Failed to move collection %s back to %s: %v", target, staging, err)
+			}
+		})
+	}
+
+	for _, target := range staged {
+		g.DBConn.Collection(target + "_prerestore").DropCollection(context.Background())
+	}
 
 	return nil
 }
 
-func restoreCollection[T any](col *qmgo.Collection, path string) (int64, error) {
-	file, err := os.Open(path)
+// Loads all documents of a snapshot file into the given collection, which is emptied first and exists afterwards.
+func restoreCollection[T any](col *qmgo.Collection, a archive, name string) (int64, error) {
+	file, err := a.open(name)
 	if err != nil {
 		return 0, err
 	}
 	defer file.Close()
 
-	// Dump all the trades from the database.
-	_, err = col.RemoveAll(context.Background(), bson.M{})
+	err = col.DropCollection(context.Background())
+	if err != nil {
+		return 0, err
+	}
+
+	// The collection has to exist to be swapped in, even if the snapshot holds no records of it.
+	err = g.CreateCollection(col.GetCollectionName())
 	if err != nil {
 		return 0, err
 	}
 
 	decoder := json.NewDecoder(file)
+	batch := []T{}
 	var count int64
 
 	for decoder.More() {
 		var doc T
 		err := decoder.Decode(&doc)
 		if err != nil {
-			return 0, err
+			return count, fmt.Errorf("failed to parse record %d in %s: %w", count+1, name, err)
 		}
 
-		_, err = col.InsertOne(context.Background(), doc)
-		if err != nil {
-			return 0, err
+		batch = append(batch, doc)
+		count++
+
+		if len(batch) == 1000 {
+			if _, err := col.InsertMany(context.Background(), batch); err != nil {
+				return count, err
+			}
+			batch = []T{}
 		}
+	}
 
-		count++
+	if len(batch) > 0 {
+		if _, err := col.InsertMany(context.Background(), batch); err != nil {
+			return count, err
+		}
 	}
 
 	return count, nil
@@ -0,0 +1,83 @@
+package snapshot
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	g "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/knadh/koanf"
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/qiniu/qmgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MongoDB tests that need a database connect to, e.g. mongodb://localhost:27017. They are skipped if it isn't set.
+const envTestMongo = "FTAXES_TEST_MONGO"
+
+// Connects DBConn to a fresh database that is dropped after the test.
+func connectTestDB(t *testing.T) {
+	uri := os.Getenv(envTestMongo)
+	if uri == "" {
+		t.Skipf("%s isn't set", envTestMongo)
+	}
+
+	client, err := qmgo.NewClient(context.Background(), &qmgo.Config{Uri: uri})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.DBConn = client.Database("f-taxes-test-" + primitive.NewObjectID().Hex())
+
+	t.Cleanup(func() {
+		g.DBConn.DropDatabase(context.Background())
+		client.Close(context.Background())
+	})
+}
+
+func testConfig(t *testing.T) *koanf.Koanf {
+	cfg := koanf.New(".")
+	cfg.Load(confmap.Provider(map[string]interface{}{"snapshots.path": t.TempDir()}, "."), nil)
+	return cfg
+}
+
+func TestRestoreSnapshotWithoutTransfers(t *testing.T) {
+	connectTestDB(t)
+	cfg := testConfig(t)
+	ctx := context.Background()
+
+	if _, err := g.DBConn.Collection(g.COL_TRADES).InsertOne(ctx, g.Trade{ID: primitive.NewObjectID(), TxID: "1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Create(cfg, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots, err := ListSnapshots(cfg)
+	if err != nil || len(snapshots) != 1 {
+		t.Fatalf("expected one snapshot, got %v and %v", snapshots, err)
+	}
+
+	// Added after the snapshot was taken, so the restore has to remove it.
+	if _, err := g.DBConn.Collection(g.COL_TRANSFERS).InsertOne(ctx, g.Transfer{ID: primitive.NewObjectID(), TxID: "2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Keeps the snapshot taken before restoring from getting the same folder.
+	time.Sleep(time.Millisecond * 5)
+
+	if err := RestoreFromSnapshot(cfg, snapshots[0].Ts, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := g.DBConn.Collection(g.COL_TRADES).Find(ctx, bson.M{}).Count(); err != nil || n != 1 {
+		t.Fatalf("expected 1 trade, got %d and %v", n, err)
+	}
+
+	if n, err := g.DBConn.Collection(g.COL_TRANSFERS).Find(ctx, bson.M{}).Count(); err != nil || n != 0 {
+		t.Fatalf("expected no transfers, got %d and %v", n, err)
+	}
+}
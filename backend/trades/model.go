@@ -4,10 +4,16 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/f-taxes/f-taxes/backend/applog"
+	"github.com/f-taxes/f-taxes/backend/events"
 	g "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/f-taxes/f-taxes/backend/history"
 	"github.com/f-taxes/f-taxes/proto"
+	"github.com/qiniu/qmgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type PaginationResult struct {
@@ -25,27 +31,104 @@ func Paginate(q g.Query) (PaginationResult, error) {
 	}
 
 	col := g.DBConn.Collection(g.COL_TRADES)
-	count, err := col.Find(context.Background(), q.ConstructedFilter).Count()
+	count, err := col.Find(context.Background(), g.NotDeleted(q.ConstructedFilter)).Count()
+
+	if err != nil {
+		return out, err
+	}
+
+	total, err := col.Find(context.Background(), g.NotDeleted(bson.M{})).Count()
 
 	if err != nil {
 		return out, err
 	}
 
 	out.FilteredCount = count
-	out.TotalCount = count
+	out.TotalCount = total
 	out.Page = q.Page
 	out.Limit = q.Limit
 	out.TotalPages = int64(math.Ceil(float64(count) / float64(q.Limit)))
 
-	err = col.Find(context.Background(), q.ConstructedFilter).Sort(q.Sort).Skip((q.Page - 1) * q.Limit).Limit(q.Limit).All(&out.Items)
+	err = col.Find(context.Background(), g.NotDeleted(q.ConstructedFilter)).Sort(q.SortFields()...).Skip((q.Page - 1) * q.Limit).Limit(q.Limit).All(&out.Items)
 	return out, err
 }
 
-func StoreProtoTrade(trade *proto.Trade) {
+// Stores a trade submitted by a plugin. Returns its id and true if the trade didn't exist yet.
+// Record events aren't published for every single trade, the caller does that once per import.
+func StoreProtoTrade(trade *proto.Trade) (primitive.ObjectID, bool) {
 	t := g.ProtoTradeToTrade(trade)
-	err := t.Store()
+	inserted, err := t.Store()
 
 	if err != nil {
 		applog.Send(applog.Error, fmt.Sprintf("Failed to store trade in database: %v", err))
+		return primitive.NilObjectID, false
+	}
+
+	if inserted {
+		history.Record(g.COL_TRADES, t.ID, nil, t, history.Actor{Source: history.SOURCE_IMPORT, Name: t.Plugin})
+	}
+
+	return t.ID, inserted
+}
+
+// Changes an existing trade on behalf of a plugin. The proto representation of the stored trade is passed to apply to be modified.
+// Fails with ErrRecordModified if the trade was updated after expectedUpdated.
+func UpdateProtoTrade(id primitive.ObjectID, expectedUpdated time.Time, apply func(t *proto.Trade) error, actor history.Actor) (g.Trade, error) {
+	col := g.DBConn.Collection(g.COL_TRADES)
+	current := g.Trade{}
+
+	err := col.Find(context.Background(), g.NotDeleted(bson.M{"_id": id})).One(&current)
+	if qmgo.IsErrNoDocuments(err) {
+		return current, g.ErrRecordNotFound
+	}
+
+	if err != nil {
+		return current, err
 	}
+
+	if !current.Updated.Equal(expectedUpdated) {
+		return current, g.ErrRecordModified
+	}
+
+	before, err := history.Current(g.COL_TRADES, id)
+	if err != nil {
+		return current, err
+	}
+
+	t := g.TradeToProtoTrade(current)
+	if err := apply(t); err != nil {
+		return current, err
+	}
+
+	updated := g.ProtoTradeToTrade(t)
+
+	// Fields that identify the trade and those that aren't part of the proto message are kept as they are.
+	updated.ID = current.ID
+	updated.TxID = current.TxID
+	updated.Plugin = current.Plugin
+	updated.PluginVersion = current.PluginVersion
+	updated.Created = current.Created
+	updated.FeeDecimals = current.FeeDecimals
+	updated.QuoteFeeDecimals = current.QuoteFeeDecimals
+	updated.Updated = time.Now().UTC().Truncate(time.Millisecond)
+
+	err = col.ReplaceOne(context.Background(), g.NotDeleted(bson.M{"_id": id, "updated": current.Updated}), updated)
+	if qmgo.IsErrNoDocuments(err) {
+		return current, g.ErrRecordModified
+	}
+
+	if err != nil {
+		return current, err
+	}
+
+	history.Record(g.COL_TRADES, id, before, updated, actor)
+	g.PushToClients("record-edited", updated)
+	events.Publish(events.Event{
+		Type:       events.RECORD_UPDATED,
+		Collection: g.COL_TRADES,
+		RecordIDs:  []primitive.ObjectID{id},
+		Plugin:     actor.Name,
+	})
+
+	return updated, nil
 }
@@ -6,8 +6,11 @@ import (
 	"time"
 
 	"github.com/f-taxes/f-taxes/backend/applog"
+	"github.com/f-taxes/f-taxes/backend/events"
 	g "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/f-taxes/f-taxes/backend/history"
 	"github.com/f-taxes/f-taxes/backend/plugin"
+	"github.com/f-taxes/f-taxes/backend/trash"
 	"github.com/f-taxes/f-taxes/proto"
 	"github.com/kataras/golog"
 	"github.com/kataras/iris/v12"
@@ -76,7 +79,7 @@ func RegisterRoutes(app *iris.Application) {
 			return
 		}
 
-		result, err := g.DBConn.Collection(g.COL_TRADES).RemoveAll(context.Background(), f)
+		count, err := trash.MoveToTrash(g.COL_TRADES, f, history.UI())
 
 		if err != nil {
 			applog.Send(applog.Error, fmt.Sprintf("Failed to delete trades: %s. Please report this bug to the developers.", err.Error()), "Internal Error")
@@ -85,18 +88,18 @@ func RegisterRoutes(app *iris.Application) {
 			return
 		}
 
-		applog.Send(applog.Info, fmt.Sprintf("%d trades where deleted.", result.DeletedCount))
+		applog.Send(applog.Info, fmt.Sprintf("%d trades where moved to the trash.", count))
 
 		ctx.JSON(g.Resp{
 			Result: true,
 		})
 	})
 
-	app.Get("/trades/clear", func(ctx iris.Context) {
-		err := g.DBConn.Collection(g.COL_TRADES).DropCollection(context.Background())
+	app.Post("/trades/clear", func(ctx iris.Context) {
+		_, err := trash.MoveToTrash(g.COL_TRADES, bson.M{}, history.UI())
 
 		if err != nil {
-			golog.Errorf("Failed to drop collection for trades: %v", err)
+			golog.Errorf("Failed to move all trades to the trash: %v", err)
 			ctx.StatusCode(iris.StatusInternalServerError)
 			return
 		}
@@ -125,7 +128,12 @@ func RegisterRoutes(app *iris.Application) {
 		tx.Fee.AmountC = tx.Fee.Amount.Mul(tx.Fee.PriceC)
 		tx.QuoteFee.AmountC = tx.QuoteFee.Amount.Mul(tx.QuoteFee.PriceC)
 
-		_, err := g.DBConn.Collection(g.COL_TRADES).UpsertId(context.Background(), tx.ID, tx)
+		before, err := history.Current(g.COL_TRADES, tx.ID)
+		if err != nil {
+			golog.Errorf("Failed to fetch trade before saving: %v", err)
+		}
+
+		_, err = g.DBConn.Collection(g.COL_TRADES).UpsertId(context.Background(), tx.ID, tx)
 		if err != nil {
 			golog.Errorf("Failed to save trade: %v", err)
 
@@ -137,8 +145,15 @@ func RegisterRoutes(app *iris.Application) {
 			return
 		}
 
+		history.Record(g.COL_TRADES, tx.ID, before, tx, history.UI())
 		g.PushToClients("record-edited", tx)
 
+		if before == nil {
+			events.RecordsChanged(events.RECORD_CREATED, g.COL_TRADES, tx.ID)
+		} else {
+			events.RecordsChanged(events.RECORD_UPDATED, g.COL_TRADES, tx.ID)
+		}
+
 		ctx.JSON(g.Resp{
 			Result: true,
 		})
@@ -153,7 +168,7 @@ func RegisterRoutes(app *iris.Application) {
 			return
 		}
 
-		err := g.DBConn.Collection(g.COL_TRADES).RemoveId(context.Background(), reqData.ID)
+		_, err := trash.MoveToTrash(g.COL_TRADES, bson.M{"_id": reqData.ID}, history.UI())
 		if err != nil {
 			applog.Send(applog.Error, fmt.Sprintf("Failed to delete trade: %s", err.Error()))
 
@@ -180,12 +195,13 @@ func RegisterRoutes(app *iris.Application) {
 			return
 		}
 
-		p := plugin.Manager.GetSpawnedPluginById(reqData.Plugin)
-		if p == nil {
-			applog.Send(applog.Error, fmt.Sprintf("Plugin %s isn't available.", reqData.Plugin))
+		p, err := plugin.Manager.GetAvailablePluginById(reqData.Plugin)
+		if err != nil {
+			applog.Send(applog.Error, fmt.Sprintf("Can't convert prices: %s.", err.Error()))
 
 			ctx.JSON(g.Resp{
 				Result: false,
+				Data:   err.Error(),
 			})
 			return
 		}
@@ -208,17 +224,17 @@ func RegisterRoutes(app *iris.Application) {
 			filter = f
 		}
 
-		go func(plugin *plugin.SpawnedPlugin, currency string, applyFilter bool, filter bson.M) {
+		go func(plugin plugin.AvailablePlugin, currency string, applyFilter bool, filter bson.M) {
 			col := g.DBConn.Collection(g.COL_TRADES)
 			var cursor qmgo.CursorI
 			var count int64
 
 			if applyFilter {
-				count, _ = col.Find(context.Background(), filter).Count()
-				cursor = col.Find(context.Background(), filter).Cursor()
+				count, _ = col.Find(context.Background(), g.NotDeleted(filter)).Count()
+				cursor = col.Find(context.Background(), g.NotDeleted(filter)).Cursor()
 			} else {
-				count, _ = col.Find(context.Background(), bson.M{"$or": bson.A{bson.M{"priceC": 0}, bson.M{"quotePriceC": 0}, bson.M{"feeC": 0}, bson.M{"feePriceC": 0}}}).Count()
-				cursor = col.Find(context.Background(), bson.M{"$or": bson.A{bson.M{"priceC": 0}, bson.M{"quotePriceC": 0}, bson.M{"feeC": 0}, bson.M{"feePriceC": 0}}}).Cursor()
+				count, _ = col.Find(context.Background(), g.NotDeleted(bson.M{"$or": bson.A{bson.M{"priceC": 0}, bson.M{"quotePriceC": 0}, bson.M{"feeC": 0}, bson.M{"feePriceC": 0}}})).Count()
+				cursor = col.Find(context.Background(), g.NotDeleted(bson.M{"$or": bson.A{bson.M{"priceC": 0}, bson.M{"quotePriceC": 0}, bson.M{"feeC": 0}, bson.M{"feePriceC": 0}}})).Cursor()
 			}
 
 			jobID := primitive.NewObjectID().Hex()
@@ -229,6 +245,7 @@ func RegisterRoutes(app *iris.Application) {
 			})
 
 			c := 0
+			converted := int64(0)
 
 			for {
 				t := g.Trade{}
@@ -243,7 +260,7 @@ func RegisterRoutes(app *iris.Application) {
 				})
 				c++
 
-				updatedTrade, err := plugin.CtlClient.GrpcClient.ConvertPricesInTrade(context.Background(), &proto.TradeConversionJob{
+				updatedTrade, err := plugin.Ctl.ConvertPricesInTrade(context.Background(), &proto.TradeConversionJob{
 					Trade:          g.TradeToProtoTrade(t),
 					TargetCurrency: currency,
 				})
@@ -260,6 +277,12 @@ func RegisterRoutes(app *iris.Application) {
 				quoteFeeC := g.StrToDecimal(updatedTrade.QuoteFee.AmountC, decimal.Zero)
 				quoteFeePriceC := g.StrToDecimal(updatedTrade.QuoteFee.PriceC, decimal.Zero)
 
+				before, err := history.Current(g.COL_TRADES, t.ID)
+				if err != nil {
+					golog.Errorf("Failed to fetch trade before converting prices: %v", err)
+					continue
+				}
+
 				err = col.UpdateOne(context.Background(), bson.M{"_id": t.ID}, bson.M{
 					"$set": bson.M{
 						"priceC":               g.DecimalToMongoDecimal(priceC),
@@ -277,8 +300,24 @@ func RegisterRoutes(app *iris.Application) {
 
 				if err != nil {
 					golog.Errorf("Failed to save trade after converting prices: %v", err)
+					continue
+				}
+
+				if after, err := history.Current(g.COL_TRADES, t.ID); err == nil {
+					history.Record(g.COL_TRADES, t.ID, before, after, history.Actor{Source: history.SOURCE_CONVERSION, Name: plugin.Manifest.ID, Ref: jobID})
 				}
+
+				converted++
+				events.RecordsChanged(events.RECORD_UPDATED, g.COL_TRADES, t.ID)
 			}
+
+			events.Publish(events.Event{
+				Type:       events.CONVERSION_FINISHED,
+				Collection: g.COL_TRADES,
+				Plugin:     plugin.Manifest.ID,
+				JobID:      jobID,
+				Count:      converted,
+			})
 		}(p, reqData.Currency, reqData.ApplyFilter, filter)
 
 		ctx.JSON(g.Resp{
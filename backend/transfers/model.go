@@ -4,10 +4,16 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/f-taxes/f-taxes/backend/applog"
+	"github.com/f-taxes/f-taxes/backend/events"
 	. "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/f-taxes/f-taxes/backend/history"
 	"github.com/f-taxes/f-taxes/proto"
+	"github.com/qiniu/qmgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type PaginationResult struct {
@@ -25,27 +31,102 @@ func Paginate(q Query) (PaginationResult, error) {
 	}
 
 	col := DBConn.Collection(COL_TRANSFERS)
-	count, err := col.Find(context.Background(), q.ConstructedFilter).Count()
+	count, err := col.Find(context.Background(), NotDeleted(q.ConstructedFilter)).Count()
+
+	if err != nil {
+		return out, err
+	}
+
+	total, err := col.Find(context.Background(), NotDeleted(bson.M{})).Count()
 
 	if err != nil {
 		return out, err
 	}
 
 	out.FilteredCount = count
-	out.TotalCount = count
+	out.TotalCount = total
 	out.Page = q.Page
 	out.Limit = q.Limit
 	out.TotalPages = int64(math.Ceil(float64(count) / float64(q.Limit)))
 
-	err = col.Find(context.Background(), q.ConstructedFilter).Sort(q.Sort).Skip((q.Page - 1) * q.Limit).Limit(q.Limit).All(&out.Items)
+	err = col.Find(context.Background(), NotDeleted(q.ConstructedFilter)).Sort(q.SortFields()...).Skip((q.Page - 1) * q.Limit).Limit(q.Limit).All(&out.Items)
 	return out, err
 }
 
-func StoreProtoTransfer(transfer *proto.Transfer) {
+// Stores a transfer submitted by a plugin. Returns its id and true if the transfer didn't exist yet.
+// Record events aren't published for every single transfer, the caller does that once per import.
+func StoreProtoTransfer(transfer *proto.Transfer) (primitive.ObjectID, bool) {
 	t := ProtoTransferToTransfer(transfer)
-	err := t.Store()
+	inserted, err := t.Store()
 
 	if err != nil {
 		applog.Send(applog.Error, fmt.Sprintf("Failed to store transfer in database: %v", err))
+		return primitive.NilObjectID, false
+	}
+
+	if inserted {
+		history.Record(COL_TRANSFERS, t.ID, nil, t, history.Actor{Source: history.SOURCE_IMPORT, Name: t.Plugin})
+	}
+
+	return t.ID, inserted
+}
+
+// Changes an existing transfer on behalf of a plugin. The proto representation of the stored transfer is passed to apply to be modified.
+// Fails with ErrRecordModified if the transfer was updated after expectedUpdated.
+func UpdateProtoTransfer(id primitive.ObjectID, expectedUpdated time.Time, apply func(t *proto.Transfer) error, actor history.Actor) (Transfer, error) {
+	col := DBConn.Collection(COL_TRANSFERS)
+	current := Transfer{}
+
+	err := col.Find(context.Background(), NotDeleted(bson.M{"_id": id})).One(&current)
+	if qmgo.IsErrNoDocuments(err) {
+		return current, ErrRecordNotFound
+	}
+
+	if err != nil {
+		return current, err
 	}
+
+	if !current.Updated.Equal(expectedUpdated) {
+		return current, ErrRecordModified
+	}
+
+	before, err := history.Current(COL_TRANSFERS, id)
+	if err != nil {
+		return current, err
+	}
+
+	t := TransferToProtoTransfer(current)
+	if err := apply(t); err != nil {
+		return current, err
+	}
+
+	updated := ProtoTransferToTransfer(t)
+
+	// Fields that identify the transfer are kept as they are.
+	updated.ID = current.ID
+	updated.TxID = current.TxID
+	updated.Plugin = current.Plugin
+	updated.PluginVersion = current.PluginVersion
+	updated.Created = current.Created
+	updated.Updated = time.Now().UTC().Truncate(time.Millisecond)
+
+	err = col.ReplaceOne(context.Background(), NotDeleted(bson.M{"_id": id, "updated": current.Updated}), updated)
+	if qmgo.IsErrNoDocuments(err) {
+		return current, ErrRecordModified
+	}
+
+	if err != nil {
+		return current, err
+	}
+
+	history.Record(COL_TRANSFERS, id, before, updated, actor)
+	PushToClients("record-edited", updated)
+	events.Publish(events.Event{
+		Type:       events.RECORD_UPDATED,
+		Collection: COL_TRANSFERS,
+		RecordIDs:  []primitive.ObjectID{id},
+		Plugin:     actor.Name,
+	})
+
+	return updated, nil
 }
@@ -6,8 +6,11 @@ import (
 	"time"
 
 	"github.com/f-taxes/f-taxes/backend/applog"
+	"github.com/f-taxes/f-taxes/backend/events"
 	g "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/f-taxes/f-taxes/backend/history"
 	"github.com/f-taxes/f-taxes/backend/plugin"
+	"github.com/f-taxes/f-taxes/backend/trash"
 	"github.com/f-taxes/f-taxes/proto"
 	"github.com/kataras/golog"
 	"github.com/kataras/iris/v12"
@@ -76,7 +79,7 @@ func RegisterRoutes(app *iris.Application) {
 			return
 		}
 
-		result, err := g.DBConn.Collection(g.COL_TRANSFERS).RemoveAll(context.Background(), f)
+		count, err := trash.MoveToTrash(g.COL_TRANSFERS, f, history.UI())
 
 		if err != nil {
 			applog.Send(applog.Error, fmt.Sprintf("Failed to delete transfers: %s. Please report this bug to the developers.", err.Error()), "Internal Error")
@@ -85,18 +88,18 @@ func RegisterRoutes(app *iris.Application) {
 			return
 		}
 
-		applog.Send(applog.Info, fmt.Sprintf("%d transfers where deleted.", result.DeletedCount))
+		applog.Send(applog.Info, fmt.Sprintf("%d transfers where moved to the trash.", count))
 
 		ctx.JSON(g.Resp{
 			Result: true,
 		})
 	})
 
-	app.Get("/transfers/clear", func(ctx iris.Context) {
-		err := g.DBConn.Collection(g.COL_TRANSFERS).DropCollection(context.Background())
+	app.Post("/transfers/clear", func(ctx iris.Context) {
+		_, err := trash.MoveToTrash(g.COL_TRANSFERS, bson.M{}, history.UI())
 
 		if err != nil {
-			golog.Errorf("Failed to drop collection for transfers: %v", err)
+			golog.Errorf("Failed to move all transfers to the trash: %v", err)
 			ctx.StatusCode(iris.StatusInternalServerError)
 			return
 		}
@@ -122,7 +125,12 @@ func RegisterRoutes(app *iris.Application) {
 
 		transfer.FeeC = transfer.Fee.Mul(transfer.FeePriceC)
 
-		_, err := g.DBConn.Collection(g.COL_TRANSFERS).UpsertId(context.Background(), transfer.ID, transfer)
+		before, err := history.Current(g.COL_TRANSFERS, transfer.ID)
+		if err != nil {
+			golog.Errorf("Failed to fetch transfer before saving: %v", err)
+		}
+
+		_, err = g.DBConn.Collection(g.COL_TRANSFERS).UpsertId(context.Background(), transfer.ID, transfer)
 		if err != nil {
 			golog.Errorf("Failed to save trade: %v", err)
 
@@ -134,8 +142,15 @@ func RegisterRoutes(app *iris.Application) {
 			return
 		}
 
+		history.Record(g.COL_TRANSFERS, transfer.ID, before, transfer, history.UI())
 		g.PushToClients("record-edited", transfer)
 
+		if before == nil {
+			events.RecordsChanged(events.RECORD_CREATED, g.COL_TRANSFERS, transfer.ID)
+		} else {
+			events.RecordsChanged(events.RECORD_UPDATED, g.COL_TRANSFERS, transfer.ID)
+		}
+
 		ctx.JSON(g.Resp{
 			Result: true,
 		})
@@ -153,12 +168,13 @@ func RegisterRoutes(app *iris.Application) {
 			return
 		}
 
-		p := plugin.Manager.GetSpawnedPluginById(reqData.Plugin)
-		if p == nil {
-			applog.Send(applog.Error, fmt.Sprintf("Plugin %s isn't available.", reqData.Plugin))
+		p, err := plugin.Manager.GetAvailablePluginById(reqData.Plugin)
+		if err != nil {
+			applog.Send(applog.Error, fmt.Sprintf("Can't convert prices: %s.", err.Error()))
 
 			ctx.JSON(g.Resp{
 				Result: false,
+				Data:   err.Error(),
 			})
 			return
 		}
@@ -181,17 +197,17 @@ func RegisterRoutes(app *iris.Application) {
 			filter = f
 		}
 
-		go func(plugin *plugin.SpawnedPlugin, currency string, applyFilter bool, filter bson.M) {
+		go func(plugin plugin.AvailablePlugin, currency string, applyFilter bool, filter bson.M) {
 			col := g.DBConn.Collection(g.COL_TRANSFERS)
 			var cursor qmgo.CursorI
 			var count int64
 
 			if applyFilter {
-				count, _ = col.Find(context.Background(), filter).Count()
-				cursor = col.Find(context.Background(), filter).Cursor()
+				count, _ = col.Find(context.Background(), g.NotDeleted(filter)).Count()
+				cursor = col.Find(context.Background(), g.NotDeleted(filter)).Cursor()
 			} else {
-				count, _ = col.Find(context.Background(), bson.M{"$or": bson.A{bson.M{"feeC": 0}, bson.M{"feePriceC": 0}}}).Count()
-				cursor = col.Find(context.Background(), bson.M{"$or": bson.A{bson.M{"feeC": 0}, bson.M{"feePriceC": 0}}}).Cursor()
+				count, _ = col.Find(context.Background(), g.NotDeleted(bson.M{"$or": bson.A{bson.M{"feeC": 0}, bson.M{"feePriceC": 0}}})).Count()
+				cursor = col.Find(context.Background(), g.NotDeleted(bson.M{"$or": bson.A{bson.M{"feeC": 0}, bson.M{"feePriceC": 0}}})).Cursor()
 			}
 
 			jobID := primitive.NewObjectID().Hex()
@@ -202,6 +218,7 @@ func RegisterRoutes(app *iris.Application) {
 			})
 
 			c := 0
+			converted := int64(0)
 
 			for {
 				t := g.Transfer{}
@@ -216,7 +233,7 @@ func RegisterRoutes(app *iris.Application) {
 					"progress": fmt.Sprintf("%2.f", (float64(c)/float64(count))*100),
 				})
 
-				updatedTransfer, err := plugin.CtlClient.GrpcClient.ConvertPricesInTransfer(context.Background(), &proto.TransferConversionJob{
+				updatedTransfer, err := plugin.Ctl.ConvertPricesInTransfer(context.Background(), &proto.TransferConversionJob{
 					Transfer:       g.TransferToProtoTransfer(t),
 					TargetCurrency: currency,
 				})
@@ -225,6 +242,12 @@ func RegisterRoutes(app *iris.Application) {
 					continue
 				}
 
+				before, err := history.Current(g.COL_TRANSFERS, t.ID)
+				if err != nil {
+					golog.Errorf("Failed to fetch transfer before converting prices: %v", err)
+					continue
+				}
+
 				err = col.UpdateOne(context.Background(), bson.M{"_id": t.ID}, bson.M{
 					"$set": bson.M{
 						"feeC":           g.DecimalToMongoDecimal(g.StrToDecimal(updatedTransfer.FeeC, decimal.Zero)),
@@ -235,8 +258,24 @@ func RegisterRoutes(app *iris.Application) {
 
 				if err != nil {
 					golog.Errorf("Failed to save trade after converting prices: %v", err)
+					continue
+				}
+
+				if after, err := history.Current(g.COL_TRANSFERS, t.ID); err == nil {
+					history.Record(g.COL_TRANSFERS, t.ID, before, after, history.Actor{Source: history.SOURCE_CONVERSION, Name: plugin.Manifest.ID, Ref: jobID})
 				}
+
+				converted++
+				events.RecordsChanged(events.RECORD_UPDATED, g.COL_TRANSFERS, t.ID)
 			}
+
+			events.Publish(events.Event{
+				Type:       events.CONVERSION_FINISHED,
+				Collection: g.COL_TRANSFERS,
+				Plugin:     plugin.Manifest.ID,
+				JobID:      jobID,
+				Count:      converted,
+			})
 		}(p, reqData.Currency, reqData.ApplyFilter, filter)
 
 		ctx.JSON(g.Resp{
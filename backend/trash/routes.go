@@ -0,0 +1,131 @@
+package trash
+
+import (
+	"fmt"
+
+	"github.com/f-taxes/f-taxes/backend/applog"
+	g "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/f-taxes/f-taxes/backend/history"
+	"github.com/kataras/golog"
+	"github.com/kataras/iris/v12"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type selectionRequest struct {
+	Collection string               `json:"collection"`
+	IDs        []primitive.ObjectID `json:"ids"`
+	All        bool                 `json:"all"` // Selects every record of the collection that is in the trash.
+}
+
+func RegisterRoutes(app *iris.Application) {
+	app.Post("/trash/list", func(ctx iris.Context) {
+		reqData := struct {
+			Collection string `json:"collection"`
+			Page       int64  `json:"page"`
+			Limit      int64  `json:"limit"`
+		}{}
+
+		if !g.ReadJSON(ctx, &reqData) {
+			return
+		}
+
+		if !isTrashable(reqData.Collection) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			return
+		}
+
+		result, err := List(reqData.Collection, reqData.Page, reqData.Limit)
+
+		if err != nil {
+			golog.Errorf("Failed to list %s in the trash: %v", reqData.Collection, err)
+			ctx.StatusCode(iris.StatusInternalServerError)
+			return
+		}
+
+		ctx.JSON(g.Resp{
+			Result: true,
+			Data:   result,
+		})
+	})
+
+	app.Post("/trash/restore", func(ctx iris.Context) {
+		reqData := selectionRequest{}
+
+		if !g.ReadJSON(ctx, &reqData) {
+			return
+		}
+
+		if !isTrashable(reqData.Collection) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			return
+		}
+
+		filter, err := selection(reqData.IDs, reqData.All)
+		if err != nil {
+			applog.Send(applog.Error, fmt.Sprintf("Failed to restore %s from the trash: %s", reqData.Collection, err.Error()))
+			ctx.JSON(g.Resp{
+				Result: false,
+			})
+			return
+		}
+
+		count, err := Restore(reqData.Collection, filter, history.UI())
+
+		if err != nil {
+			applog.Send(applog.Error, fmt.Sprintf("Failed to restore %s from the trash: %s", reqData.Collection, err.Error()))
+			ctx.JSON(g.Resp{
+				Result: false,
+			})
+			return
+		}
+
+		applog.Send(applog.Info, fmt.Sprintf("%d %s were restored from the trash.", count, reqData.Collection))
+		g.PushToClients("records-restored", map[string]any{
+			"collection": reqData.Collection,
+		})
+
+		ctx.JSON(g.Resp{
+			Result: true,
+			Data:   count,
+		})
+	})
+
+	app.Post("/trash/purge", func(ctx iris.Context) {
+		reqData := selectionRequest{}
+
+		if !g.ReadJSON(ctx, &reqData) {
+			return
+		}
+
+		if !isTrashable(reqData.Collection) {
+			ctx.StatusCode(iris.StatusBadRequest)
+			return
+		}
+
+		filter, err := selection(reqData.IDs, reqData.All)
+		if err != nil {
+			applog.Send(applog.Error, fmt.Sprintf("Failed to purge %s from the trash: %s", reqData.Collection, err.Error()))
+			ctx.JSON(g.Resp{
+				Result: false,
+			})
+			return
+		}
+
+		count, err := Purge(reqData.Collection, filter, history.UI())
+
+		if err != nil {
+			applog.Send(applog.Error, fmt.Sprintf("Failed to purge %s from the trash: %s", reqData.Collection, err.Error()))
+			ctx.JSON(g.Resp{
+				Result: false,
+			})
+			return
+		}
+
+		applog.Send(applog.Info, fmt.Sprintf("%d %s were permanently deleted.", count, reqData.Collection))
+
+		ctx.JSON(g.Resp{
+			Result: true,
+			Data:   count,
+		})
+	})
+}
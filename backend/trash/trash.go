@@ -0,0 +1,213 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/f-taxes/f-taxes/backend/applog"
+	"github.com/f-taxes/f-taxes/backend/events"
+	g "github.com/f-taxes/f-taxes/backend/global"
+	"github.com/f-taxes/f-taxes/backend/history"
+	"github.com/f-taxes/f-taxes/backend/snapshot"
+	"github.com/kataras/golog"
+	"github.com/knadh/koanf"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrEmptySelection = errors.New("no records were selected, pass their ids or set \"all\"")
+
+// Collections whose records are moved to the trash instead of being deleted right away.
+var collections = []string{g.COL_TRADES, g.COL_TRANSFERS}
+
+type PaginationResult struct {
+	Items      any   `json:"items"`
+	TotalCount int64 `json:"totalCount"`
+	Page       int64 `json:"page"`
+	Limit      int64 `json:"limit"`
+	TotalPages int64 `json:"totalPages"`
+}
+
+func isTrashable(collection string) bool {
+	for _, c := range collections {
+		if c == collection {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Marks all records matching the filter as deleted. They are hidden from the app and plugins until restored or purged.
+func MoveToTrash(collection string, filter bson.M, actor history.Actor) (int64, error) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	filter = g.NotDeleted(filter)
+
+	ids, err := matchingIDs(collection, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	err = history.RecordUpdates(collection, filter, func(doc bson.M) {
+		doc["deletedAt"] = now
+	}, actor)
+
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := g.DBConn.Collection(collection).UpdateAll(context.Background(), filter, bson.M{"$set": bson.M{"deletedAt": now}})
+	if err != nil {
+		return 0, err
+	}
+
+	events.RecordsChanged(events.RECORD_DELETED, collection, ids...)
+	return result.ModifiedCount, nil
+}
+
+// Moves records matching the filter out of the trash.
+func Restore(collection string, filter bson.M, actor history.Actor) (int64, error) {
+	filter = g.Deleted(filter)
+
+	ids, err := matchingIDs(collection, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	err = history.RecordUpdates(collection, filter, func(doc bson.M) {
+		delete(doc, "deletedAt")
+	}, actor)
+
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := g.DBConn.Collection(collection).UpdateAll(context.Background(), filter, bson.M{"$unset": bson.M{"deletedAt": ""}})
+	if err != nil {
+		return 0, err
+	}
+
+	// Restored records are visible to plugins again, so they appear as new.
+	events.RecordsChanged(events.RECORD_CREATED, collection, ids...)
+	return result.ModifiedCount, nil
+}
+
+// Returns the ids of the records matching the filter, so they can be included in events after the records were changed.
+func matchingIDs(collection string, filter bson.M) ([]primitive.ObjectID, error) {
+	ids := []primitive.ObjectID{}
+	err := g.DBConn.Collection(collection).Find(context.Background(), filter).Distinct("_id", &ids)
+	return ids, err
+}
+
+// Permanently removes records in the trash that match the filter.
+func Purge(collection string, filter bson.M, actor history.Actor) (int64, error) {
+	filter = g.Deleted(filter)
+
+	if err := snapshot.BeforeDestructiveOperation("purging " + collection + " from the trash"); err != nil {
+		return 0, err
+	}
+
+	if err := history.RecordDeletions(collection, filter, actor); err != nil {
+		return 0, err
+	}
+
+	result, err := g.DBConn.Collection(collection).RemoveAll(context.Background(), filter)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
+
+func List(collection string, page, limit int64) (PaginationResult, error) {
+	switch collection {
+	case g.COL_TRADES:
+		return list[g.Trade](collection, page, limit)
+	case g.COL_TRANSFERS:
+		return list[g.Transfer](collection, page, limit)
+	}
+
+	return PaginationResult{}, fmt.Errorf("collection '%s' has no trash", collection)
+}
+
+func list[T any](collection string, page, limit int64) (PaginationResult, error) {
+	items := []T{}
+	out := PaginationResult{
+		Items: items,
+		Page:  max(page, 1),
+		Limit: limit,
+	}
+
+	if out.Limit <= 0 {
+		out.Limit = 100
+	}
+
+	col := g.DBConn.Collection(collection)
+	count, err := col.Find(context.Background(), g.Deleted(bson.M{})).Count()
+	if err != nil {
+		return out, err
+	}
+
+	out.TotalCount = count
+	out.TotalPages = int64(math.Ceil(float64(count) / float64(out.Limit)))
+
+	err = col.Find(context.Background(), g.Deleted(bson.M{})).Sort("-deletedAt").Skip((out.Page - 1) * out.Limit).Limit(out.Limit).All(&items)
+	out.Items = items
+	return out, err
+}
+
+// Builds a filter that selects the records with the given ids or all records if all is set.
+func selection(ids []primitive.ObjectID, all bool) (bson.M, error) {
+	if all {
+		return bson.M{}, nil
+	}
+
+	if len(ids) == 0 {
+		return nil, ErrEmptySelection
+	}
+
+	return bson.M{"_id": bson.M{"$in": ids}}, nil
+}
+
+// Lets reverted creations go through the trash and starts purging records that have been in the trash for longer than "trash.purgeAfterDays".
+func Setup(cfg *koanf.Koanf) {
+	history.MoveToTrash = MoveToTrash
+
+	days := cfg.Int("trash.purgeAfterDays")
+
+	if days <= 0 {
+		golog.Debug("Automatic purging of the trash is disabled")
+		return
+	}
+
+	maxAge := time.Duration(days) * 24 * time.Hour
+
+	go func() {
+		for {
+			purgeExpired(maxAge)
+			time.Sleep(time.Hour)
+		}
+	}()
+}
+
+func purgeExpired(maxAge time.Duration) {
+	filter := bson.M{"deletedAt": bson.M{"$lt": time.Now().UTC().Add(-maxAge)}}
+
+	for _, collection := range collections {
+		count, err := g.DBConn.Collection(collection).Find(context.Background(), filter).Count()
+		if err != nil || count == 0 {
+			continue
+		}
+
+		purged, err := Purge(collection, filter, history.Actor{Source: history.SOURCE_SYSTEM, Name: "trash auto-purge"})
+		if err != nil {
+			applog.Send(applog.Error, fmt.Sprintf("Failed to purge expired %s from the trash: %s", collection, err.Error()))
+			continue
+		}
+
+		applog.Send(applog.Info, fmt.Sprintf("Purged %d %s that were in the trash for too long.", purged, collection))
+	}
+}
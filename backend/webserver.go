@@ -7,11 +7,14 @@ import (
 
 	"github.com/f-taxes/f-taxes/backend/applog"
 	"github.com/f-taxes/f-taxes/backend/global"
+	"github.com/f-taxes/f-taxes/backend/history"
 	"github.com/f-taxes/f-taxes/backend/plugin"
+	"github.com/f-taxes/f-taxes/backend/reports"
 	"github.com/f-taxes/f-taxes/backend/settings"
 	"github.com/f-taxes/f-taxes/backend/snapshot"
 	"github.com/f-taxes/f-taxes/backend/trades"
 	"github.com/f-taxes/f-taxes/backend/transfers"
+	"github.com/f-taxes/f-taxes/backend/trash"
 	"github.com/kataras/golog"
 	"github.com/kataras/iris/v12"
 	"github.com/kataras/iris/v12/view"
@@ -33,12 +36,17 @@ func Start(cfg *koanf.Koanf, webAssets embed.FS) {
 	registerFrontend(app, cfg, webAssets)
 
 	applog.Setup()
+	snapshot.Setup(cfg)
+	trash.Setup(cfg)
 	applog.RegisterRoutes(app)
+	history.RegisterRoutes(app)
 	settings.RegisterRoutes(app)
 	plugin.RegisterRoutes(app, cfg)
 	trades.RegisterRoutes(app)
 	transfers.RegisterRoutes(app)
 	snapshot.RegisterRoutes(app, cfg)
+	trash.RegisterRoutes(app)
+	reports.RegisterRoutes(app, cfg)
 
 	global.SetupWebsocketServer(app)
 
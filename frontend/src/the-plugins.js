@@ -8,6 +8,10 @@ import '@tp/tp-button/tp-button.js';
 import '@tp/tp-icon/tp-icon.js';
 import '@tp/tp-dialog/tp-dialog.js';
 import '@tp/tp-tooltip/tp-tooltip-wrapper.js';
+import '@tp/tp-form/tp-form.js';
+import '@tp/tp-input/tp-input.js';
+import '@tp/tp-checkbox/tp-checkbox.js';
+import '@tp/tp-dropdown/tp-dropdown.js';
 import './elements/card-box.js';
 import './elements/the-source-form.js';
 import { LitElement, html, css } from 'lit';
@@ -44,6 +48,19 @@ class ThePlugins extends BaseElement {
           justify-content: space-between;
         }
 
+        header > div {
+          display: flex;
+          align-items: center;
+        }
+
+        header > div > * + * {
+          margin-left: 10px;
+        }
+
+        #sideloadInput {
+          display: none;
+        }
+
         card-box {
           max-width: 800px;
           margin: auto;
@@ -130,6 +147,26 @@ class ThePlugins extends BaseElement {
           margin-right: 10px;
         }
 
+        .health.degraded {
+          color: var(--amber);
+        }
+
+        .health.unreachable {
+          color: var(--red);
+        }
+
+        .config-grid {
+          display: grid;
+          grid-template-columns: auto 1fr;
+          grid-gap: 20px;
+          align-items: center;
+          margin-bottom: 20px;
+        }
+
+        .config-grid label {
+          font-weight: bold;
+        }
+
         #pluginSettingsDialog::part(dialog) {
           width: 80%;
           height: 80%;
@@ -157,7 +194,13 @@ class ThePlugins extends BaseElement {
         <h2>Plugins allow you to extend F-Taxes with new data sources for your tax reports.<br>All thanks to community contributions.</h2>
         <header>
           <h3>We found ${plugins.length} plugins</h3>
-          <tp-button id="reloadBtn" @click=${this.reloadList} extended>Reload <tp-icon .icon=${icons.refresh}></tp-icon></tp-button>
+          <div>
+            <tp-tooltip-wrapper text="Install a plugin from a zip or tar.gz archive" tooltipValign="top">
+              <tp-button id="sideloadBtn" @click=${() => this.$.sideloadInput.click()} extended>Upload <tp-icon .icon=${icons.add}></tp-icon></tp-button>
+            </tp-tooltip-wrapper>
+            <input id="sideloadInput" type="file" accept=".zip,.tar.gz,.tgz" @change=${this.sideload}>
+            <tp-button id="reloadBtn" @click=${this.reloadList} extended>Reload <tp-icon .icon=${icons.refresh}></tp-icon></tp-button>
+          </div>
         </header>
         <div class="list">
           ${plugins.length > 0 ? plugins.map(plugin => html`
@@ -181,6 +224,21 @@ class ThePlugins extends BaseElement {
               <div class="key">
                 <div><label>Version:</label>${plugin.version}</div>
                 <div><label>Status:</label>${this.pluginStatusToString(plugin.status)}</div>
+                ${plugin.local ? html`
+                  <div title="Side-loaded from an uploaded archive"><label>Registry:</label>local</div>
+                ` : null}
+                ${plugin.registry ? html`
+                  <div><label>Registry:</label>${plugin.registry}${plugin.registryTrust && plugin.registryTrust !== 'trusted' ? ` (${plugin.registryTrust})` : ''}</div>
+                ` : null}
+                ${this.processes[plugin.id] ? html`
+                  <div><label>Process:</label>${this.processStatusToString(this.processes[plugin.id])}</div>
+                ` : null}
+                ${this.processes[plugin.id]?.usage ? html`
+                  <div title=${this.limitsToString(this.processes[plugin.id])}><label>Resources:</label>${this.usageToString(this.processes[plugin.id].usage)}</div>
+                ` : null}
+                ${this.health[plugin.id] ? html`
+                  <div title=${this.health[plugin.id].reason}><label>Health:</label><span class="health ${this.health[plugin.id].state}">${this.healthToString(this.health[plugin.id])}</span></div>
+                ` : null}
               </div>
               <div class="actions">
                 ${plugin.web?.configPage ? html`
@@ -189,6 +247,12 @@ class ThePlugins extends BaseElement {
                   </tp-tooltip-wrapper>
                 ` : null}
 
+                ${plugin.status != 1 && plugin.configSchema ? html`
+                  <tp-tooltip-wrapper text="Configure this plugin" tooltipValign="top">
+                    <tp-button class="only-icon" extended @click=${e => this.showConfig(e, plugin)}><tp-icon .icon=${icons.settings}></tp-icon></tp-button>
+                  </tp-tooltip-wrapper>
+                ` : null}
+
                 ${plugin.status == 1 ? html`
                   <tp-tooltip-wrapper text="Install this plugin" tooltipValign="top">
                     <tp-button class="only-icon" extended @click=${e => this.install(e, plugin)}><tp-icon .icon=${icons.download}></tp-icon></tp-button>
@@ -201,6 +265,21 @@ class ThePlugins extends BaseElement {
                   </tp-tooltip-wrapper>
                 ` : null}
 
+                ${plugin.status != 1 && plugin.enabled ? html`
+                  <tp-tooltip-wrapper text="Restart this plugin" tooltipValign="top">
+                    <tp-button class="only-icon" extended @click=${e => this.lifecycle(e, plugin, 'restart')}><tp-icon .icon=${icons.refresh}></tp-icon></tp-button>
+                  </tp-tooltip-wrapper>
+                  <tp-tooltip-wrapper text="Disable this plugin" tooltipValign="top">
+                    <tp-button class="only-icon" extended @click=${e => this.lifecycle(e, plugin, 'disable')}><tp-icon .icon=${icons.close}></tp-icon></tp-button>
+                  </tp-tooltip-wrapper>
+                ` : null}
+
+                ${plugin.status != 1 && !plugin.enabled ? html`
+                  <tp-tooltip-wrapper text="Enable this plugin" tooltipValign="top">
+                    <tp-button class="only-icon" extended @click=${e => this.lifecycle(e, plugin, 'enable')}><tp-icon .icon=${icons.play}></tp-icon></tp-button>
+                  </tp-tooltip-wrapper>
+                ` : null}
+
                 ${plugin.status == 0 ? html`
                   <tp-tooltip-wrapper text="Uninstall this plugin" tooltipValign="top">
                     <tp-button class="only-icon" extended @click=${() => this.confirmUninstall(plugin)}><tp-icon .icon=${icons.delete}></tp-icon></tp-button>
@@ -221,6 +300,47 @@ class ThePlugins extends BaseElement {
         </div>
       </tp-dialog>
 
+      <tp-dialog id="permissionsDialog" showClose>
+        <h2>${this.pendingAction?.update ? 'Update' : 'Install'} "${this.pendingAction?.plugin.label}"?</h2>
+        ${Array.isArray(this.pendingAction?.plugin.permissions) ? html`
+          ${this.pendingAction.plugin.permissions.length > 0 ? html`
+            <p>This plugin requests the following permissions:</p>
+            <ul>
+              ${this.pendingAction.plugin.permissions.map(p => html`<li>${this.permissionToString(p)}</li>`)}
+            </ul>
+          ` : html`<p>This plugin doesn't request any permissions.</p>`}
+        ` : html`
+          <p>This plugin doesn't declare its permissions and will get full access to your records and settings.</p>
+        `}
+        <div class="buttons-justified">
+          <tp-button dialog-dismiss>Cancel</tp-button>
+          <tp-button @click=${() => this.confirmPendingAction()}>Yes, ${this.pendingAction?.update ? 'Update' : 'Install'}</tp-button>
+        </div>
+      </tp-dialog>
+
+      <tp-dialog id="pluginConfigDialog" showClose>
+        <h2>Configure "${this.configPlugin?.label}"</h2>
+        ${this.pluginConfig ? html`
+          <tp-form @submit=${this.saveConfig}>
+            <form>
+              <div class="config-grid">
+                ${Object.entries(this.pluginConfig.schema?.properties || {}).map(([key, prop]) => html`
+                  <label>${prop.title || key}${(this.pluginConfig.schema.required || []).includes(key) ? ' *' : ''}</label>
+                  <div>
+                    ${this.renderConfigField(key, prop)}
+                    ${prop.description ? html`<div class="hint">${prop.description}</div>` : null}
+                  </div>
+                `)}
+              </div>
+              <div class="buttons-justified">
+                <tp-button dialog-dismiss>Cancel</tp-button>
+                <tp-button submit>Save</tp-button>
+              </div>
+            </form>
+          </tp-form>
+        ` : null}
+      </tp-dialog>
+
       <tp-dialog id="pluginSettingsDialog" showClose>
         <div class="frame-wrap">
           <iframe id="settingsFrame" src="" frameborder="0"></iframe>
@@ -236,6 +356,11 @@ class ThePlugins extends BaseElement {
       plugins: { type: Array },
       settings: { type: Object },
       selPlugins: { type: Object },
+      processes: { type: Object },
+      health: { type: Object },
+      pendingAction: { type: Object },
+      configPlugin: { type: Object },
+      pluginConfig: { type: Object },
     };
   }
 
@@ -243,6 +368,8 @@ class ThePlugins extends BaseElement {
     super();
     this.plugins = [];
     this.selPlugins = {};
+    this.processes = {};
+    this.health = {};
   }
 
   firstUpdated() {
@@ -269,17 +396,98 @@ class ThePlugins extends BaseElement {
     } else if (btn) {
       btn.showError();
     }
+
+    const statusResp = await this.get('/plugins/status');
+
+    if (statusResp.result) {
+      this.processes = Object.fromEntries(statusResp.data.map(p => [ p.id, p ]));
+    }
+
+    const healthResp = await this.get('/plugins/health');
+
+    if (healthResp.result) {
+      this.health = Object.fromEntries(healthResp.data.map(h => [ h.id, h ]));
+    }
   }
 
-  async install(e, plugin) {
-    const btn = e.target;
+  install(e, plugin) {
+    this.pendingAction = { btn: closest(e.target, 'tp-button'), plugin, update: false };
+    this.$.permissionsDialog.show();
+  }
+
+  async confirmPendingAction() {
+    const { btn, plugin, update } = this.pendingAction;
+    this.$.permissionsDialog.close();
     btn.showSpinner();
-    const resp = await this.post('/plugins/install', plugin);
+    const resp = await this.post(update ? '/plugins/update' : '/plugins/install', plugin);
     if (!resp.result) {
       btn.showError();
     }
   }
 
+  async sideload(e) {
+    const file = e.target.files[0];
+    e.target.value = '';
+
+    if (!file) {
+      return;
+    }
+
+    const btn = this.$.sideloadBtn;
+    btn.showSpinner();
+
+    const body = new FormData();
+    body.append('file', file);
+
+    try {
+      const resp = await (await fetch('/plugins/sideload', { method: 'POST', body })).json();
+
+      if (resp.result) {
+        btn.showSuccess();
+      } else {
+        btn.showError();
+      }
+    } catch (err) {
+      btn.showError();
+    }
+  }
+
+  permissionToString(permission) {
+    switch (permission) {
+      case 'records:read':
+        return 'Read all your trades and transfers';
+      case 'records:write':
+        return 'Add trades and transfers';
+      case 'records:update':
+        return 'Change existing trades and transfers';
+      case 'settings:read':
+        return 'Read your settings';
+      case 'applog:write':
+        return 'Show messages in the app log';
+      case 'jobs:report':
+        return 'Show the progress of its jobs';
+    }
+
+    return permission;
+  }
+
+  updatePlugin(e, plugin) {
+    this.pendingAction = { btn: closest(e.target, 'tp-button'), plugin, update: true };
+    this.$.permissionsDialog.show();
+  }
+
+  async lifecycle(e, plugin, action) {
+    const btn = closest(e.target, 'tp-button');
+    btn.showSpinner();
+    const resp = await this.post(`/plugins/${action}`, { id: plugin.id });
+
+    if (resp.result) {
+      btn.showSuccess();
+    } else {
+      btn.showError();
+    }
+  }
+
   pluginStatusToString(status) {
     switch (status) {
       case 0:
@@ -291,6 +499,55 @@ class ThePlugins extends BaseElement {
     }
   }
 
+  processStatusToString(process) {
+    switch (process.state) {
+      case 'running':
+        return process.totalRestarts > 0 ? `Running (restarted ${process.totalRestarts} times)` : 'Running';
+      case 'backoff':
+        return `Exited with code ${process.exitCode}, restarting...`;
+      case 'failed':
+        return `Crashed too often (exit code ${process.exitCode})`;
+      case 'exited':
+        return `Exited with code ${process.exitCode}`;
+      case 'stopped':
+        return 'Stopped';
+    }
+  }
+
+  usageToString(usage) {
+    return `${(usage.memoryBytes / 1024 / 1024).toFixed(1)} MB, ${usage.cpuPercent.toFixed(1)}% CPU, ${usage.openFiles} files`;
+  }
+
+  limitsToString(process) {
+    const { limits, usage } = process;
+    const parts = [];
+
+    if (limits.memoryMB) {
+      parts.push(`${limits.memoryMB} MB`);
+    }
+
+    if (limits.cpuPercent) {
+      parts.push(`${limits.cpuPercent}% CPU`);
+    }
+
+    if (limits.openFiles) {
+      parts.push(`${limits.openFiles} files`);
+    }
+
+    return parts.length > 0 ? `Limited to ${parts.join(', ')} (${usage.enforcement})` : 'No limits';
+  }
+
+  healthToString(health) {
+    switch (health.state) {
+      case 'healthy':
+        return 'Healthy';
+      case 'degraded':
+        return `Degraded (${health.reason})`;
+      case 'unreachable':
+        return `Unreachable (${health.reason})`;
+    }
+  }
+
   confirmUninstall(plugin) {
     this.selPlugins = plugin;
     this.$.uninstallPluginDialog.show();
@@ -302,7 +559,7 @@ class ThePlugins extends BaseElement {
   }
 
   onMsg(msg) {
-    if (msg.event === 'plugin-install-result') {
+    if (msg.event === 'plugin-install-result' || msg.event === 'plugin-update-result') {
       const { data } = msg;
       const pluginEl = this.shadowRoot.getElementById(data.id);
 
@@ -317,14 +574,98 @@ class ThePlugins extends BaseElement {
       this.reloadList();
     }
 
-    if (msg.event === 'plugin-uninstalled') {
+    if (msg.event === 'plugin-uninstalled' || msg.event === 'plugin-lifecycle-changed') {
       this.reloadList();
     }
+
+    if (msg.event === 'plugin-status') {
+      this.processes = { ...this.processes, [msg.data.id]: msg.data };
+    }
+
+    if (msg.event === 'plugin-health') {
+      this.health = { ...this.health, [msg.data.id]: msg.data };
+    }
+  }
+
+  async showConfig(e, plugin) {
+    const btn = closest(e.target, 'tp-button');
+    this.pluginConfig = null;
+    this.configPlugin = plugin;
+
+    const resp = await this.get(`/plugins/config/${plugin.id}`);
+    if (!resp.result) {
+      btn.showError();
+      return;
+    }
+
+    this.pluginConfig = resp.data;
+    this.$.pluginConfigDialog.show();
+  }
+
+  renderConfigField(key, prop) {
+    const value = this.pluginConfig.values[key] ?? prop.default;
+
+    if (Array.isArray(prop.enum)) {
+      return html`<tp-dropdown name=${key} .value=${value} .items=${prop.enum.map(v => ({ value: v, label: String(v) }))}></tp-dropdown>`;
+    }
+
+    if (prop.type === 'boolean') {
+      return html`<tp-checkbox name=${key} .checked=${Boolean(value)}></tp-checkbox>`;
+    }
+
+    // Secrets are never sent to the UI. Leaving the field empty keeps the stored value.
+    if (prop.writeOnly || prop.format === 'password') {
+      return html`
+        <tp-input name=${key}>
+          <input type="password" placeholder=${this.pluginConfig.isSet[key] ? 'Unchanged' : ''}>
+        </tp-input>
+      `;
+    }
+
+    return html`
+      <tp-input name=${key} .value=${value ?? ''}>
+        <input type=${prop.type === 'number' || prop.type === 'integer' ? 'number' : 'text'}>
+      </tp-input>
+    `;
+  }
+
+  async saveConfig(e) {
+    const btn = e.target.submitButton;
+    const properties = this.pluginConfig.schema?.properties || {};
+    const values = {};
+
+    for (const [key, prop] of Object.entries(properties)) {
+      let value = e.detail[key];
+
+      if ((prop.writeOnly || prop.format === 'password') && !value) {
+        continue;
+      }
+
+      if (prop.type === 'number' || prop.type === 'integer') {
+        value = value === '' || value === undefined ? null : Number(value);
+      } else if (prop.type === 'boolean') {
+        value = Boolean(value);
+      } else if (value === '') {
+        value = null;
+      }
+
+      values[key] = value;
+    }
+
+    btn.showSpinner();
+    const resp = await this.post('/plugins/config', { id: this.configPlugin.id, values });
+
+    if (resp.result) {
+      btn.showSuccess();
+      this.$.pluginConfigDialog.close();
+    } else {
+      btn.showError();
+    }
   }
 
   showSettings(e, plugin) {
     console.log(plugin);
-    this.$.settingsFrame.src = `http://${plugin.web.address}${plugin.web.configPage}`;
+    this.$.settingsFrame.src = `/plugins/${plugin.id}/ui${plugin.web.configPage}`;
     this.$.pluginSettingsDialog.show();
   }
 }
@@ -8,6 +8,9 @@ import '@tp/tp-button/tp-button.js';
 import '@tp/tp-icon/tp-icon.js';
 import '@tp/tp-dialog/tp-dialog.js';
 import '@tp/tp-tooltip/tp-tooltip-wrapper.js';
+import '@tp/tp-form/tp-form.js';
+import '@tp/tp-input/tp-input.js';
+import '@tp/tp-dropdown/tp-dropdown.js';
 import './elements/card-box.js';
 import './elements/the-source-form.js';
 import { LitElement, html, css } from 'lit';
@@ -152,12 +155,51 @@ class TheReports extends BaseElement {
         #settingsFrame {
           flex: 1;
         }
+
+        .runs {
+          margin-top: 20px;
+        }
+
+        .run {
+          display: grid;
+          grid-template-columns: 1fr auto;
+          gap: 5px 20px;
+          margin-top: 10px;
+          background: var(--bg0);
+          padding: 10px;
+          border-radius: 4px;
+        }
+
+        .run.selected {
+          outline: solid 2px var(--hl1);
+        }
+
+        .run label {
+          color: var(--text-low);
+          margin-right: 5px;
+        }
+
+        .run .files a {
+          margin-right: 10px;
+        }
+
+        .run .error {
+          color: var(--red);
+        }
+
+        .form-grid {
+          display: grid;
+          grid-template-columns: auto 1fr;
+          gap: 10px 20px;
+          align-items: center;
+          margin-bottom: 20px;
+        }
       `
     ];
   }
 
   render() {
-    const { plugins } = this;
+    const { plugins, runs } = this;
 
     return html`
       <card-box>
@@ -189,6 +231,12 @@ class TheReports extends BaseElement {
                 <div><label>Status:</label>${this.pluginStatusToString(plugin.status)}</div>
               </div>
               <div class="actions">
+                ${plugin.reportMethods?.length > 0 ? html`
+                  <tp-tooltip-wrapper text="Generate and store a report" tooltipValign="top">
+                    <tp-button class="only-icon" extended @click=${() => this.showGenerate(plugin)}><tp-icon .icon=${icons.add}></tp-icon></tp-button>
+                  </tp-tooltip-wrapper>
+                ` : null}
+
                 ${plugin.web?.reportPage ? html`
                   <tp-tooltip-wrapper text="Start creating reports using this plugin" tooltipValign="top">
                     <tp-button class="only-icon" extended @click=${e => this.showReportPage(e, plugin)}><tp-icon .icon=${icons.play}></tp-icon></tp-button>
@@ -204,12 +252,88 @@ class TheReports extends BaseElement {
             </div>
           `): html`<div class="empty">Please wait until the list of available report plugins was loaded...</div>`}
         </div>
+
+        ${runs.length > 0 ? html`
+          <div class="runs">
+            <header>
+              <h3>Generated reports</h3>
+              <tp-button extended ?disabled=${this.selRuns.length !== 2} @click=${this.compareRuns}>Compare selected</tp-button>
+            </header>
+            ${runs.map(run => html`
+              <div class="run ${this.selRuns.includes(run._id) ? 'selected' : ''}" @click=${() => this.toggleRun(run)}>
+                <div>
+                  <div>
+                    <label>Plugin:</label>${run.plugin} ${run.pluginVersion}
+                    <label>Tax year:</label>${run.params.taxYear}
+                    <label>Method:</label>${run.params.method}
+                    <label>Currency:</label>${run.params.currency}
+                  </div>
+                  <div>
+                    <label>Created:</label>${new Date(run.created).toLocaleString()}
+                    <label>Records:</label>${run.records}
+                    <label>Status:</label>${run.status}
+                  </div>
+                  ${run.error ? html`<div class="error">${run.error}</div>` : null}
+                  <div class="files">
+                    ${run.files.map(f => html`<a href="/reports/runs/${run._id}/files/${encodeURIComponent(f.name)}" target="_blank" @click=${e => e.stopPropagation()}>${f.name}</a>`)}
+                  </div>
+                </div>
+                <div class="actions">
+                  <tp-button class="only-icon danger" extended @click=${e => this.deleteRun(e, run)}><tp-icon .icon=${icons.delete}></tp-icon></tp-button>
+                </div>
+              </div>
+            `)}
+          </div>
+        ` : null}
       </card-box>
 
       <div class="frame-page">
         <iframe id="reportFrame" src="" frameborder="0"></iframe>
       </div>
 
+      <tp-dialog id="generateDialog" showClose>
+        <h2>Generate report with "${this.generatePlugin?.label}"</h2>
+        <tp-form @submit=${this.generate}>
+          <form>
+            <div class="form-grid">
+              <label>Tax year</label>
+              <tp-input name="taxYear" required .value=${String(new Date().getFullYear() - 1)}>
+                <input type="number">
+              </tp-input>
+              <label>Currency</label>
+              <tp-input name="currency" required .value=${'EUR'}>
+                <input type="text">
+              </tp-input>
+              <label>Method</label>
+              <tp-dropdown name="method" required .value=${this.generatePlugin?.reportMethods?.[0]} .items=${(this.generatePlugin?.reportMethods || []).map(m => ({ value: m, label: m }))}></tp-dropdown>
+            </div>
+            <div class="buttons-justified">
+              <tp-button dialog-dismiss>Cancel</tp-button>
+              <tp-button submit>Generate</tp-button>
+            </div>
+          </form>
+        </tp-form>
+      </tp-dialog>
+
+      <tp-dialog id="compareDialog" showClose>
+        <h2>Comparison</h2>
+        ${this.comparison ? html`
+          <ul>
+            <li>${this.comparison.sameInput ? 'Both reports are based on the same records.' : `The records changed between both reports (${this.comparison.recordsChange >= 0 ? '+' : ''}${this.comparison.recordsChange} records).`}</li>
+            <li>${this.comparison.sameVersion ? 'Both reports were created by the same plugin version.' : `The plugin version changed from ${this.comparison.a.pluginVersion} to ${this.comparison.b.pluginVersion}.`}</li>
+            <li>${this.comparison.sameParams ? 'Both reports use the same parameters.' : 'The reports use different parameters.'}</li>
+            <li>${this.comparison.identical ? 'Both reports produced identical files.' : 'The produced files differ:'}</li>
+          </ul>
+          ${!this.comparison.identical ? html`
+            <ul>
+              ${this.comparison.files.filter(f => !f.inA || !f.inB || f.changed).map(f => html`
+                <li>${f.name}: ${!f.inA ? 'only in the newer report' : !f.inB ? 'only in the older report' : `changed (${f.sizeA} → ${f.sizeB} bytes)`}</li>
+              `)}
+            </ul>
+          ` : null}
+        ` : null}
+      </tp-dialog>
+
       <tp-dialog id="pluginSettingsDialog" showClose>
         <div class="frame-wrap">
           <iframe id="settingsFrame" src="" frameborder="0"></iframe>
@@ -225,6 +349,10 @@ class TheReports extends BaseElement {
       plugins: { type: Array },
       settings: { type: Object },
       selPlugins: { type: Object },
+      runs: { type: Array },
+      selRuns: { type: Array },
+      generatePlugin: { type: Object },
+      comparison: { type: Object },
     };
   }
 
@@ -232,14 +360,74 @@ class TheReports extends BaseElement {
     super();
     this.plugins = [];
     this.selPlugins = {};
+    this.runs = [];
+    this.selRuns = [];
   }
 
   firstUpdated() {
     setTimeout(() => {
       this.reloadList();
+      this.reloadRuns();
     }, 0);
   }
 
+  async reloadRuns() {
+    const resp = await this.post('/reports/runs', {});
+
+    if (resp.result) {
+      this.runs = resp.data;
+      this.selRuns = this.selRuns.filter(id => this.runs.some(r => r._id === id));
+    }
+  }
+
+  showGenerate(plugin) {
+    this.generatePlugin = plugin;
+    this.$.generateDialog.show();
+  }
+
+  async generate(e) {
+    const btn = e.target.submitButton;
+    btn.showSpinner();
+
+    const resp = await this.post('/reports/generate', {
+      plugin: this.generatePlugin.id,
+      taxYear: Number(e.detail.taxYear),
+      currency: e.detail.currency,
+      method: e.detail.method,
+    });
+
+    if (resp.result) {
+      btn.showSuccess();
+      this.$.generateDialog.close();
+    } else {
+      btn.showError();
+    }
+  }
+
+  toggleRun(run) {
+    if (this.selRuns.includes(run._id)) {
+      this.selRuns = this.selRuns.filter(id => id !== run._id);
+    } else {
+      this.selRuns = [ ...this.selRuns, run._id ].slice(-2);
+    }
+  }
+
+  async compareRuns() {
+    // Runs are listed newest first, so the older one is compared against the newer one.
+    const [ b, a ] = this.runs.filter(r => this.selRuns.includes(r._id)).map(r => r._id);
+    const resp = await this.post('/reports/compare', { a, b });
+
+    if (resp.result) {
+      this.comparison = resp.data;
+      this.$.compareDialog.show();
+    }
+  }
+
+  async deleteRun(e, run) {
+    e.stopPropagation();
+    await this.post('/reports/runs/delete', { _id: run._id });
+  }
+
   async reloadList(e) {
     let btn = e?.target;
 
@@ -309,15 +497,19 @@ class TheReports extends BaseElement {
     if (msg.event === 'plugin-uninstalled') {
       this.reloadList();
     }
+
+    if (msg.event === 'report-run-changed' || msg.event === 'report-run-deleted') {
+      this.reloadRuns();
+    }
   }
 
   showSettings(e, plugin) {
-    this.$.settingsFrame.src = `http://${plugin.web.address}${plugin.web.configPage}`;
+    this.$.settingsFrame.src = `/plugins/${plugin.id}/ui${plugin.web.configPage}`;
     this.$.pluginSettingsDialog.show();
   }
 
   showReportPage(e, plugin) {
-    this.$.reportFrame.src = `http://${plugin.web.address}${plugin.web.reportPage}`;
+    this.$.reportFrame.src = `/plugins/${plugin.id}/ui${plugin.web.reportPage}`;
   }
 }
 
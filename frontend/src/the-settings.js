@@ -155,16 +155,17 @@ class TheSettings extends fetchMixin(Store(LitElement)) {
             Manage Snapshots
           </div>
           <div>
-            <tp-button @click=${this.createSnapshot} extended>Create</tp-button>
+            <tp-button @click=${e => this.createSnapshot(e, false)} extended>Create</tp-button>
+            <tp-button @click=${e => this.createSnapshot(e, true)} extended>Create Encrypted</tp-button>
           </div>
         </h2>
         <div class="snapshots">
-          ${snapshots.map(ts => html`
+          ${snapshots.map(snapshot => html`
             <div class="snapshot">
-              <div class="timestamp">${formatTs(ts, this.settings?.dateTimeFormat, this.settings?.timeZone)}</div>
+              <div class="timestamp">${formatTs(snapshot.ts, this.settings?.dateTimeFormat, this.settings?.timeZone)}${snapshot.encrypted ? ' (encrypted)' : ''}${snapshot.automatic ? ` - ${snapshot.reason}` : ''}</div>
               <div class="actions">
-                <quick-confirm-button label="Restore" confirmLabel="Click To Restore" extended @confirm=${e => this.restoreSnapshot(e, ts)}></quick-confirm-button>
-                <quick-confirm-button label="Delete" confirmLabel="Click To Delete" danger extended @confirm=${e => this.deleteSnapshot(e, ts)}></quick-confirm-button>
+                <quick-confirm-button label="Restore" confirmLabel="Click To Restore" extended @confirm=${e => this.restoreSnapshot(e, snapshot)}></quick-confirm-button>
+                <quick-confirm-button label="Delete" confirmLabel="Click To Delete" danger extended @confirm=${e => this.deleteSnapshot(e, snapshot.ts)}></quick-confirm-button>
               </div>
             </div>
           `)}
@@ -219,7 +220,7 @@ class TheSettings extends fetchMixin(Store(LitElement)) {
   async deleteAllTrades(e) {
     const btn = e.target;
     btn.showSpinner();
-    const resp = await this.get('/trades/clear');
+    const resp = await this.post('/trades/clear');
 
     if (resp.result) {
       btn.showSuccess();
@@ -231,7 +232,7 @@ class TheSettings extends fetchMixin(Store(LitElement)) {
   async deleteAllTransfers(e) {
     const btn = e.target;
     btn.showSpinner();
-    const resp = await this.get('/transfers/clear');
+    const resp = await this.post('/transfers/clear');
 
     if (resp.result) {
       btn.showSuccess();
@@ -248,10 +249,16 @@ class TheSettings extends fetchMixin(Store(LitElement)) {
     }
   }
 
-  async createSnapshot(e) {
+  async createSnapshot(e, encrypted) {
     const btn = e.target;
+    const passphrase = encrypted ? window.prompt('Passphrase to encrypt the snapshot with') : '';
+
+    if (encrypted && !passphrase) {
+      return;
+    }
+
     btn.showSpinner();
-    const resp = await this.post('/snapshots/create');
+    const resp = await this.post('/snapshots/create', { passphrase });
 
     if (resp.result) {
       btn.showSuccess();
@@ -261,10 +268,16 @@ class TheSettings extends fetchMixin(Store(LitElement)) {
     }
   }
 
-  async restoreSnapshot(e, ts) {
+  async restoreSnapshot(e, snapshot) {
     const btn = e.target.button;
+    const passphrase = snapshot.encrypted ? window.prompt('Passphrase of the snapshot') : '';
+
+    if (snapshot.encrypted && !passphrase) {
+      return;
+    }
+
     btn.showSpinner();
-    const resp = await this.post('/snapshots/restore', { ts });
+    const resp = await this.post('/snapshots/restore', { ts: snapshot.ts, passphrase });
 
     if (resp.result) {
       btn.showSuccess();
@@ -205,6 +205,8 @@ class TheTrades extends BaseElement {
         this.items[idx] = msg.data;
         this.items = [ ...this.items ];
       }
+    } else if ((msg.event === 'record-reverted' || msg.event === 'records-restored') && msg.data.collection === 'trades') {
+      this.fetchTrades();
     }
   }
 
@@ -192,6 +192,8 @@ class TheTransfers extends BaseElement {
         this.items[idx] = msg.data;
         this.items = [ ...this.items ];
       }
+    } else if ((msg.event === 'record-reverted' || msg.event === 'records-restored') && msg.data.collection === 'transfers') {
+      this.fetchTransfers();
     }
   }
 
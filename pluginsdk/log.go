@@ -0,0 +1,65 @@
+package pluginsdk
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/f-taxes/f-taxes/proto"
+)
+
+// Sends messages to the app log of F-Taxes, where the user sees them.
+// Messages are written to stderr instead if F-Taxes can't be reached.
+type Logger struct {
+	plugin *Plugin
+	tags   []string
+}
+
+// Returns a logger that adds the given tags to each message.
+func (l *Logger) WithTags(tags ...string) *Logger {
+	return &Logger{plugin: l.plugin, tags: append(append([]string{}, l.tags...), tags...)}
+}
+
+func (l *Logger) Info(msg string) {
+	l.send(proto.LogLevel_INFO, msg)
+}
+
+func (l *Logger) Infof(format string, args ...any) {
+	l.send(proto.LogLevel_INFO, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) Warn(msg string) {
+	l.send(proto.LogLevel_WARN, msg)
+}
+
+func (l *Logger) Warnf(format string, args ...any) {
+	l.send(proto.LogLevel_WARN, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) Error(msg string) {
+	l.send(proto.LogLevel_ERR, msg)
+}
+
+func (l *Logger) Errorf(format string, args ...any) {
+	l.send(proto.LogLevel_ERR, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) send(level proto.LogLevel, msg string) {
+	client, err := l.plugin.Client()
+
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		_, err = client.AppLog(ctx, &proto.AppLogMsg{
+			Level:   level,
+			Message: msg,
+			Tags:    l.tags,
+		})
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[%s] %s\n", level, msg)
+	}
+}
@@ -0,0 +1,347 @@
+// Package pluginsdk implements the parts every F-Taxes plugin written in Go needs: connecting and authenticating
+// against the FTaxes grpc server, sending heartbeats, serving PluginCtl, reporting job progress and logging to the app log.
+//
+// A minimal plugin looks like this:
+//
+//	p := pluginsdk.New("my_conversion", "1.0.0")
+//	p.CtlAddress = "127.0.0.1:0"
+//	p.OnConvertTrade = func(ctx context.Context, job *proto.TradeConversionJob) (*proto.Trade, error) { ... }
+//
+//	if err := p.Run(context.Background()); err != nil {
+//		log.Fatal(err)
+//	}
+package pluginsdk
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/f-taxes/f-taxes/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Environment variables F-Taxes uses to hand a plugin its identity and secret token. See plugin.ENV_PLUGIN_ID.
+const ENV_PLUGIN_ID = "FTAXES_PLUGIN_ID"
+const ENV_PLUGIN_TOKEN = "FTAXES_PLUGIN_TOKEN"
+
+// Environment variables F-Taxes uses to hand a plugin free addresses for its servers. See plugin.ENV_CTL_ADDRESS.
+const ENV_CTL_ADDRESS = "FTAXES_CTL_ADDRESS"
+const ENV_WEB_ADDRESS = "FTAXES_WEB_ADDRESS"
+
+// Metadata keys carrying the credentials on every call. See gapi.MD_PLUGIN_ID.
+const MD_PLUGIN_ID = "x-plugin-id"
+const MD_PLUGIN_TOKEN = "x-plugin-token"
+
+// Address of the FTaxes grpc server if F-Taxes didn't pass one with -grpc-addr.
+const DefaultGrpcAddress = "127.0.0.1:4222"
+
+const DefaultHeartbeatInterval = time.Second * 5
+
+// Time Run waits for running PluginCtl calls to finish when shutting down.
+const shutdownTimeout = time.Second * 10
+
+var ErrNotConnected = errors.New("plugin isn't connected to F-Taxes")
+
+type Plugin struct {
+	ID                string        // Id of the plugin as in its manifest. Overridden by FTAXES_PLUGIN_ID if set.
+	Version           string        // Version of the plugin as in its manifest.
+	GrpcAddress       string        // Address of the FTaxes grpc server. Read from the -grpc-addr flag if empty.
+	Token             string        // Secret token to authenticate with. Read from FTAXES_PLUGIN_TOKEN if empty.
+	CtlAddress        string        // Address for the PluginCtl server to listen on, see "ctl.address" in the manifest. Overridden by FTAXES_CTL_ADDRESS if set. No server is started if empty.
+	WebAddress        string        // Address the plugin's own web server listens on, see "web.address" in the manifest. Overridden by FTAXES_WEB_ADDRESS if set. Only reported to F-Taxes, the sdk doesn't start a web server.
+	HeartbeatInterval time.Duration // Defaults to DefaultHeartbeatInterval.
+
+	// Handlers for calls F-Taxes makes to the plugin. Calls without a handler fail with UNIMPLEMENTED.
+	OnConvertTrade    func(ctx context.Context, job *proto.TradeConversionJob) (*proto.Trade, error)
+	OnConvertTransfer func(ctx context.Context, job *proto.TransferConversionJob) (*proto.Transfer, error)
+	OnGenerateReport  func(ctx context.Context, req *proto.ReportRequest, send func(*proto.ReportArtifact) error) error
+
+	// Called once the connection to F-Taxes is established, before the first heartbeat is sent.
+	// The plugin stops if it returns an error.
+	OnStart func(ctx context.Context) error
+
+	Log *Logger
+
+	lock    sync.Mutex
+	conn    *grpc.ClientConn
+	client  proto.FTaxesClient
+	ctlAddr string // Address the PluginCtl server actually listens on.
+}
+
+func New(id, version string) *Plugin {
+	p := &Plugin{
+		ID:      id,
+		Version: version,
+	}
+
+	p.Log = &Logger{plugin: p}
+	return p
+}
+
+// Connects to F-Taxes and serves PluginCtl until ctx is canceled or the process receives SIGINT or SIGTERM.
+// Running calls are given some time to finish before Run returns.
+func (p *Plugin) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := p.loadEnv(); err != nil {
+		return err
+	}
+
+	if err := p.Connect(ctx); err != nil {
+		return err
+	}
+	defer p.Close()
+
+	var server *grpc.Server
+	serveErr := make(chan error, 1)
+
+	if p.CtlAddress != "" {
+		lis, err := net.Listen("tcp", p.CtlAddress)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", p.CtlAddress, err)
+		}
+
+		p.lock.Lock()
+		p.ctlAddr = lis.Addr().String()
+		p.lock.Unlock()
+
+		server = grpc.NewServer()
+		proto.RegisterPluginCtlServer(server, &ctlServer{plugin: p})
+
+		go func() {
+			serveErr <- server.Serve(lis)
+		}()
+	}
+
+	if p.OnStart != nil {
+		if err := p.OnStart(ctx); err != nil {
+			if server != nil {
+				server.Stop()
+			}
+			return err
+		}
+	}
+
+	go p.heartbeat(ctx)
+
+	select {
+	case <-ctx.Done():
+	case err := <-serveErr:
+		return err
+	}
+
+	if server != nil {
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-time.After(shutdownTimeout):
+			server.Stop()
+		}
+	}
+
+	return nil
+}
+
+// Fills in settings that F-Taxes passes via flags and environment variables.
+func (p *Plugin) loadEnv() error {
+	if id := os.Getenv(ENV_PLUGIN_ID); id != "" {
+		p.ID = id
+	}
+
+	if p.Token == "" {
+		p.Token = os.Getenv(ENV_PLUGIN_TOKEN)
+	}
+
+	// F-Taxes only passes addresses for the servers the plugin's manifest declares.
+	if addr := os.Getenv(ENV_CTL_ADDRESS); addr != "" && p.CtlAddress != "" {
+		p.CtlAddress = addr
+	}
+
+	if addr := os.Getenv(ENV_WEB_ADDRESS); addr != "" {
+		p.WebAddress = addr
+	}
+
+	if p.GrpcAddress == "" {
+		flags := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		addr := flags.String("grpc-addr", DefaultGrpcAddress, "Address of the FTaxes grpc server")
+
+		if err := flags.Parse(grpcAddrArgs(os.Args[1:])); err != nil {
+			return err
+		}
+
+		p.GrpcAddress = *addr
+	}
+
+	if p.ID == "" {
+		return errors.New("plugin id is missing")
+	}
+
+	return nil
+}
+
+// Picks -grpc-addr from the arguments. Plugins may define flags of their own, which the sdk doesn't know about.
+func grpcAddrArgs(args []string) []string {
+	for i, a := range args {
+		switch {
+		case a == "-grpc-addr" || a == "--grpc-addr":
+			return args[i:min(len(args), i+2)]
+		case strings.HasPrefix(a, "-grpc-addr=") || strings.HasPrefix(a, "--grpc-addr="):
+			return args[i : i+1]
+		}
+	}
+
+	return nil
+}
+
+// Connects to the FTaxes grpc server. Run calls it, it's only needed when Run isn't used.
+func (p *Plugin) Connect(ctx context.Context) error {
+	conn, err := grpc.DialContext(ctx, p.GrpcAddress,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(p.unaryCredentials),
+		grpc.WithStreamInterceptor(p.streamCredentials),
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to connect to F-Taxes at %s: %w", p.GrpcAddress, err)
+	}
+
+	p.lock.Lock()
+	p.conn = conn
+	p.client = proto.NewFTaxesClient(conn)
+	p.lock.Unlock()
+
+	return nil
+}
+
+func (p *Plugin) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.conn == nil {
+		return nil
+	}
+
+	err := p.conn.Close()
+	p.conn = nil
+	p.client = nil
+	return err
+}
+
+// Address the PluginCtl server listens on. Differs from CtlAddress if that had port 0. Empty until Run started the server.
+func (p *Plugin) CtlListenAddress() string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.ctlAddr
+}
+
+// Client for the FTaxes grpc server. Calls made with it are authenticated automatically.
+func (p *Plugin) Client() (proto.FTaxesClient, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.client == nil {
+		return nil, ErrNotConnected
+	}
+
+	return p.client, nil
+}
+
+func (p *Plugin) withCredentials(ctx context.Context) context.Context {
+	if p.Token == "" {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, MD_PLUGIN_ID, p.ID, MD_PLUGIN_TOKEN, p.Token)
+}
+
+func (p *Plugin) unaryCredentials(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(p.withCredentials(ctx), method, req, reply, cc, opts...)
+}
+
+func (p *Plugin) streamCredentials(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(p.withCredentials(ctx), desc, cc, method, opts...)
+}
+
+// Sends heartbeats until ctx is canceled. F-Taxes connects back to the PluginCtl server after the first one.
+func (p *Plugin) heartbeat(ctx context.Context) {
+	interval := p.HeartbeatInterval
+	if interval <= 0 {
+		interval = DefaultHeartbeatInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if client, err := p.Client(); err == nil {
+			callCtx, cancel := context.WithTimeout(ctx, interval)
+			_, err = client.PluginHeartbeat(callCtx, &proto.PluginInfo{
+				ID:           p.ID,
+				Version:      p.Version,
+				HasCtlServer: p.CtlAddress != "",
+				CtlAddress:   p.CtlListenAddress(),
+				WebAddress:   p.WebAddress,
+			})
+			cancel()
+
+			if err != nil && ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "Failed to send heartbeat to F-Taxes: %v\n", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatches PluginCtl calls to the handlers of the plugin.
+type ctlServer struct {
+	proto.UnimplementedPluginCtlServer
+	plugin *Plugin
+}
+
+func (s *ctlServer) ConvertPricesInTrade(ctx context.Context, job *proto.TradeConversionJob) (*proto.Trade, error) {
+	if s.plugin.OnConvertTrade == nil {
+		return nil, status.Error(codes.Unimplemented, "plugin doesn't convert trades")
+	}
+
+	return s.plugin.OnConvertTrade(ctx, job)
+}
+
+func (s *ctlServer) ConvertPricesInTransfer(ctx context.Context, job *proto.TransferConversionJob) (*proto.Transfer, error) {
+	if s.plugin.OnConvertTransfer == nil {
+		return nil, status.Error(codes.Unimplemented, "plugin doesn't convert transfers")
+	}
+
+	return s.plugin.OnConvertTransfer(ctx, job)
+}
+
+func (s *ctlServer) GenerateReport(req *proto.ReportRequest, stream proto.PluginCtl_GenerateReportServer) error {
+	if s.plugin.OnGenerateReport == nil {
+		return status.Error(codes.Unimplemented, "plugin doesn't generate reports")
+	}
+
+	return s.plugin.OnGenerateReport(stream.Context(), req, stream.Send)
+}
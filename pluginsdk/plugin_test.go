@@ -0,0 +1,105 @@
+package pluginsdk_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/f-taxes/f-taxes/pluginsdk"
+	"github.com/f-taxes/f-taxes/pluginsdk/sdktest"
+	"github.com/f-taxes/f-taxes/proto"
+)
+
+func TestPluginAgainstFakeServer(t *testing.T) {
+	srv, err := sdktest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Stop()
+
+	p := pluginsdk.New("test_conversion", "1.0.0")
+	p.CtlAddress = "127.0.0.1:0"
+	p.HeartbeatInterval = time.Millisecond * 20
+	srv.Attach(p)
+
+	p.OnStart = func(ctx context.Context) error {
+		p.Log.Info("started")
+		return p.NewProgress("Converting").Done()
+	}
+
+	p.OnConvertTrade = func(ctx context.Context, job *proto.TradeConversionJob) (*proto.Trade, error) {
+		job.Trade.PriceC = "42"
+		job.Trade.PriceConvertedBy = p.ID
+		return job.Trade, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() {
+		done <- p.Run(ctx)
+	}()
+
+	if !srv.WaitFor(func() bool { return p.CtlListenAddress() != "" && len(srv.Heartbeats()) > 0 }, time.Second*5) {
+		t.Fatal("plugin didn't send a heartbeat")
+	}
+
+	hb := srv.Heartbeats()[0]
+	if hb.ID != "test_conversion" || hb.Version != "1.0.0" || !hb.HasCtlServer || hb.CtlAddress != p.CtlListenAddress() {
+		t.Fatalf("unexpected heartbeat %v", hb)
+	}
+
+	if logs := srv.Logs(); len(logs) != 1 || logs[0].Message != "started" {
+		t.Fatalf("unexpected logs %v", logs)
+	}
+
+	if progress := srv.Progress(); len(progress) != 1 || progress[0].Progress != "100.00" || progress[0].Plugin != "test_conversion" {
+		t.Fatalf("unexpected progress %v", progress)
+	}
+
+	ctl, conn, err := srv.DialPlugin(ctx, p.CtlListenAddress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	trade, err := ctl.ConvertPricesInTrade(ctx, &proto.TradeConversionJob{Trade: &proto.Trade{TxID: "1", Price: "2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if trade.PriceC != "42" || trade.PriceConvertedBy != "test_conversion" {
+		t.Fatalf("trade wasn't converted: %v", trade)
+	}
+
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("Run didn't return after the context was canceled")
+	}
+}
+
+func TestPluginWithoutCredentialsIsRejected(t *testing.T) {
+	srv, err := sdktest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Stop()
+
+	p := pluginsdk.New("test_conversion", "1.0.0")
+	p.GrpcAddress = srv.Addr()
+
+	if err := p.Connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	if err := p.NewProgress("Converting").Done(); err == nil {
+		t.Fatal("call without a token was accepted")
+	}
+}
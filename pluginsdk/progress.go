@@ -0,0 +1,92 @@
+package pluginsdk
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/f-taxes/f-taxes/proto"
+)
+
+// Reports the progress of a job to F-Taxes, which shows it in the app.
+// Finishing a job (see Done) also tells F-Taxes that an import is complete.
+type Progress struct {
+	sync.Mutex
+	plugin   *Plugin
+	id       string
+	label    string
+	lastSent time.Time
+}
+
+// Minimum time between two progress updates. Updates in between are skipped, except for the final one.
+const progressThrottle = time.Millisecond * 250
+
+// Starts reporting a new job.
+func (p *Plugin) NewProgress(label string) *Progress {
+	id := make([]byte, 12)
+	rand.Read(id)
+
+	return &Progress{
+		plugin: p,
+		id:     hex.EncodeToString(id),
+		label:  label,
+	}
+}
+
+func (pr *Progress) ID() string {
+	return pr.id
+}
+
+func (pr *Progress) SetLabel(label string) {
+	pr.Lock()
+	pr.label = label
+	pr.Unlock()
+}
+
+// Reports that done of total steps are finished.
+func (pr *Progress) Update(done, total int64) error {
+	percent := float64(100)
+	if total > 0 {
+		percent = float64(done) / float64(total) * 100
+	}
+
+	return pr.Set(percent)
+}
+
+// Reports the progress in percent. Values of 100 or more mark the job as finished.
+func (pr *Progress) Set(percent float64) error {
+	pr.Lock()
+	if percent < 100 && time.Since(pr.lastSent) < progressThrottle {
+		pr.Unlock()
+		return nil
+	}
+
+	pr.lastSent = time.Now()
+	label := pr.label
+	pr.Unlock()
+
+	client, err := pr.plugin.Client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	_, err = client.ShowJobProgress(ctx, &proto.JobProgress{
+		ID:       pr.id,
+		Label:    label,
+		Progress: fmt.Sprintf("%.2f", min(percent, 100)),
+		Plugin:   pr.plugin.ID,
+	})
+
+	return err
+}
+
+// Marks the job as finished.
+func (pr *Progress) Done() error {
+	return pr.Set(100)
+}
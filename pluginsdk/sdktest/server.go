@@ -0,0 +1,373 @@
+// Package sdktest provides an in-process fake of the FTaxes grpc server to test plugins built with pluginsdk.
+//
+//	srv, err := sdktest.NewServer()
+//	defer srv.Stop()
+//
+//	p := pluginsdk.New("my_import", "1.0.0")
+//	srv.Attach(p)
+//	go p.Run(ctx)
+//
+//	srv.WaitFor(func() bool { return len(srv.Trades()) == 10 }, time.Second)
+package sdktest
+
+import (
+	"context"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/f-taxes/f-taxes/pluginsdk"
+	"github.com/f-taxes/f-taxes/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Token the server expects from plugins attached with Attach.
+const Token = "sdktest-token"
+
+// Fake FTaxes server that keeps everything plugins send in memory.
+// Records added with AddRecords are served by StreamRecords and QueryRecords, without filtering.
+type Server struct {
+	proto.UnimplementedFTaxesServer
+
+	lock       sync.Mutex
+	grpc       *grpc.Server
+	lis        net.Listener
+	settings   *proto.Settings
+	trades     []*proto.Trade
+	transfers  []*proto.Transfer
+	fees       []*proto.SrcGenericFee
+	progress   []*proto.JobProgress
+	logs       []*proto.AppLogMsg
+	heartbeats []*proto.PluginInfo
+	records    []*proto.Record
+	config     map[string]*structpb.Struct
+	subs       map[chan *proto.Event]struct{}
+}
+
+// Starts a server listening on a random local port.
+func NewServer() (*Server, error) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		lis:      lis,
+		settings: &proto.Settings{DateTimeFormat: "Pp", TimeZone: "UTC"},
+		config:   map[string]*structpb.Struct{},
+		subs:     map[chan *proto.Event]struct{}{},
+	}
+
+	s.grpc = grpc.NewServer(grpc.UnaryInterceptor(s.unaryAuth), grpc.StreamInterceptor(s.streamAuth))
+	proto.RegisterFTaxesServer(s.grpc, s)
+
+	go s.grpc.Serve(lis)
+
+	return s, nil
+}
+
+func (s *Server) Addr() string {
+	return s.lis.Addr().String()
+}
+
+func (s *Server) Stop() {
+	s.grpc.Stop()
+}
+
+// Points the plugin to this server.
+func (s *Server) Attach(p *pluginsdk.Plugin) {
+	p.GrpcAddress = s.Addr()
+	p.Token = Token
+}
+
+// Connects to the PluginCtl server of a plugin, like F-Taxes does after the plugin's first heartbeat.
+func (s *Server) DialPlugin(ctx context.Context, addr string) (proto.PluginCtlClient, *grpc.ClientConn, error) {
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return proto.NewPluginCtlClient(conn), conn, nil
+}
+
+// Polls cond until it returns true or the timeout expires.
+func (s *Server) WaitFor(cond func() bool, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		if cond() {
+			return true
+		}
+
+		if time.Now().After(deadline) {
+			return false
+		}
+
+		time.Sleep(time.Millisecond * 10)
+	}
+}
+
+func (s *Server) SetSettings(settings *proto.Settings) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.settings = settings
+}
+
+func (s *Server) SetConfig(pluginID string, values map[string]any) error {
+	v, err := structpb.NewStruct(values)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.config[pluginID] = v
+	return nil
+}
+
+// Adds records that StreamRecords and QueryRecords return.
+func (s *Server) AddRecords(records ...*proto.Record) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.records = append(s.records, records...)
+}
+
+// Sends an event to all subscribed plugins. Events are dropped for plugins that have more than 100 events pending.
+func (s *Server) Publish(e *proto.Event) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for c := range s.subs {
+		select {
+		case c <- e:
+		default:
+		}
+	}
+}
+
+func (s *Server) Trades() []*proto.Trade {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]*proto.Trade{}, s.trades...)
+}
+
+func (s *Server) Transfers() []*proto.Transfer {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]*proto.Transfer{}, s.transfers...)
+}
+
+func (s *Server) Fees() []*proto.SrcGenericFee {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]*proto.SrcGenericFee{}, s.fees...)
+}
+
+func (s *Server) Progress() []*proto.JobProgress {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]*proto.JobProgress{}, s.progress...)
+}
+
+func (s *Server) Logs() []*proto.AppLogMsg {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]*proto.AppLogMsg{}, s.logs...)
+}
+
+func (s *Server) Heartbeats() []*proto.PluginInfo {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]*proto.PluginInfo{}, s.heartbeats...)
+}
+
+func (s *Server) Config(pluginID string) map[string]any {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.config[pluginID].AsMap()
+}
+
+// Rejects calls without the credentials Attach hands to plugins.
+func (s *Server) authenticate(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ids := md.Get(pluginsdk.MD_PLUGIN_ID)
+	tokens := md.Get(pluginsdk.MD_PLUGIN_TOKEN)
+
+	if len(ids) == 0 || ids[0] == "" || len(tokens) == 0 || tokens[0] != Token {
+		return status.Error(codes.Unauthenticated, "invalid or missing plugin credentials")
+	}
+
+	return nil
+}
+
+func (s *Server) unaryAuth(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := s.authenticate(ctx); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (s *Server) streamAuth(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.authenticate(ss.Context()); err != nil {
+		return err
+	}
+
+	return handler(srv, ss)
+}
+
+func (s *Server) SubmitTrade(ctx context.Context, t *proto.Trade) (*emptypb.Empty, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.trades = append(s.trades, t)
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) SubmitTransfer(ctx context.Context, t *proto.Transfer) (*emptypb.Empty, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.transfers = append(s.transfers, t)
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) SubmitGenericFee(ctx context.Context, f *proto.SrcGenericFee) (*emptypb.Empty, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.fees = append(s.fees, f)
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) ShowJobProgress(ctx context.Context, p *proto.JobProgress) (*emptypb.Empty, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.progress = append(s.progress, p)
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) GetSettings(ctx context.Context, _ *emptypb.Empty) (*proto.Settings, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.settings, nil
+}
+
+func (s *Server) AppLog(ctx context.Context, msg *proto.AppLogMsg) (*emptypb.Empty, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.logs = append(s.logs, msg)
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) PluginHeartbeat(ctx context.Context, info *proto.PluginInfo) (*emptypb.Empty, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.heartbeats = append(s.heartbeats, info)
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) StreamRecords(job *proto.StreamRecordsJob, stream proto.FTaxes_StreamRecordsServer) error {
+	s.lock.Lock()
+	records := append([]*proto.Record{}, s.records...)
+	s.lock.Unlock()
+
+	for _, r := range records {
+		if err := stream.Send(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) QueryRecords(ctx context.Context, req *proto.QueryRequest) (*proto.QueryResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	page := max(req.Page, 1)
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	total := int64(len(s.records))
+	from := min((page-1)*limit, total)
+	to := min(from+limit, total)
+
+	return &proto.QueryResponse{
+		Records:       s.records[from:to],
+		TotalCount:    total,
+		FilteredCount: total,
+		Page:          page,
+		Limit:         limit,
+		TotalPages:    (total + limit - 1) / limit,
+	}, nil
+}
+
+func (s *Server) GetPluginConfig(ctx context.Context, req *proto.PluginConfigRequest) (*proto.PluginConfig, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	values := s.config[req.Plugin]
+	if values == nil {
+		values = &structpb.Struct{Fields: map[string]*structpb.Value{}}
+	}
+
+	return &proto.PluginConfig{Plugin: req.Plugin, Values: values}, nil
+}
+
+func (s *Server) SetPluginConfig(ctx context.Context, cfg *proto.PluginConfig) (*emptypb.Empty, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	current := s.config[cfg.Plugin]
+	if current == nil {
+		current = &structpb.Struct{Fields: map[string]*structpb.Value{}}
+		s.config[cfg.Plugin] = current
+	}
+
+	// Like F-Taxes, values are merged into the stored configuration and null removes a key.
+	for k, v := range cfg.Values.GetFields() {
+		if _, isNull := v.GetKind().(*structpb.Value_NullValue); isNull {
+			delete(current.Fields, k)
+		} else {
+			current.Fields[k] = v
+		}
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) Subscribe(filter *proto.EventFilter, stream proto.FTaxes_SubscribeServer) error {
+	c := make(chan *proto.Event, 100)
+
+	s.lock.Lock()
+	s.subs[c] = struct{}{}
+	s.lock.Unlock()
+
+	defer func() {
+		s.lock.Lock()
+		delete(s.subs, c)
+		s.lock.Unlock()
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e := <-c:
+			if len(filter.Types) > 0 && !slices.Contains(filter.Types, e.Type) {
+				continue
+			}
+
+			if err := stream.Send(e); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -11,6 +11,8 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -262,6 +264,64 @@ func (LogLevel) EnumDescriptor() ([]byte, []int) {
 	return file_f_taxes_proto_rawDescGZIP(), []int{4}
 }
 
+type EventType int32
+
+const (
+	EventType_RECORD_CREATED      EventType = 0
+	EventType_RECORD_UPDATED      EventType = 1
+	EventType_RECORD_DELETED      EventType = 2
+	EventType_SETTINGS_CHANGED    EventType = 3
+	EventType_IMPORT_FINISHED     EventType = 4
+	EventType_CONVERSION_FINISHED EventType = 5
+)
+
+// Enum value maps for EventType.
+var (
+	EventType_name = map[int32]string{
+		0: "RECORD_CREATED",
+		1: "RECORD_UPDATED",
+		2: "RECORD_DELETED",
+		3: "SETTINGS_CHANGED",
+		4: "IMPORT_FINISHED",
+		5: "CONVERSION_FINISHED",
+	}
+	EventType_value = map[string]int32{
+		"RECORD_CREATED":      0,
+		"RECORD_UPDATED":      1,
+		"RECORD_DELETED":      2,
+		"SETTINGS_CHANGED":    3,
+		"IMPORT_FINISHED":     4,
+		"CONVERSION_FINISHED": 5,
+	}
+)
+
+func (x EventType) Enum() *EventType {
+	p := new(EventType)
+	*p = x
+	return p
+}
+
+func (x EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_f_taxes_proto_enumTypes[5].Descriptor()
+}
+
+func (EventType) Type() protoreflect.EnumType {
+	return &file_f_taxes_proto_enumTypes[5]
+}
+
+func (x EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventType.Descriptor instead.
+func (EventType) EnumDescriptor() ([]byte, []int) {
+	return file_f_taxes_proto_rawDescGZIP(), []int{5}
+}
+
 type Props struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -462,6 +522,7 @@ type Trade struct {
 	PluginVersion         string                 `protobuf:"bytes,9001,opt,name=PluginVersion,proto3" json:"PluginVersion,omitempty"`
 	Created               *timestamppb.Timestamp `protobuf:"bytes,9002,opt,name=Created,proto3" json:"Created,omitempty"`
 	Updated               *timestamppb.Timestamp `protobuf:"bytes,9003,opt,name=Updated,proto3" json:"Updated,omitempty"`
+	ID                    string                 `protobuf:"bytes,9004,opt,name=ID,proto3" json:"ID,omitempty"` // Database id of the record. Set when records are streamed to plugins, ignored when they are submitted.
 }
 
 func (x *Trade) Reset() {
@@ -699,6 +760,13 @@ func (x *Trade) GetUpdated() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Trade) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
 type Transfer struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -724,6 +792,7 @@ type Transfer struct {
 	PluginVersion  string                 `protobuf:"bytes,9001,opt,name=PluginVersion,proto3" json:"PluginVersion,omitempty"`
 	Created        *timestamppb.Timestamp `protobuf:"bytes,9002,opt,name=Created,proto3" json:"Created,omitempty"`
 	Updated        *timestamppb.Timestamp `protobuf:"bytes,9003,opt,name=Updated,proto3" json:"Updated,omitempty"`
+	ID             string                 `protobuf:"bytes,9004,opt,name=ID,proto3" json:"ID,omitempty"` // Database id of the record. Set when records are streamed to plugins, ignored when they are submitted.
 }
 
 func (x *Transfer) Reset() {
@@ -898,6 +967,13 @@ func (x *Transfer) GetUpdated() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Transfer) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
 type SrcGenericFee struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1093,8 +1169,9 @@ type Record struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Trade    *Trade    `protobuf:"bytes,1,opt,name=Trade,proto3" json:"Trade,omitempty"`
-	Transfer *Transfer `protobuf:"bytes,2,opt,name=Transfer,proto3" json:"Transfer,omitempty"`
+	Trade       *Trade    `protobuf:"bytes,1,opt,name=Trade,proto3" json:"Trade,omitempty"`
+	Transfer    *Transfer `protobuf:"bytes,2,opt,name=Transfer,proto3" json:"Transfer,omitempty"`
+	ResumeToken string    `protobuf:"bytes,3,opt,name=ResumeToken,proto3" json:"ResumeToken,omitempty"` // Pass it in StreamRecordsJob.ResumeToken to continue the stream after this record.
 }
 
 func (x *Record) Reset() {
@@ -1143,6 +1220,14 @@ func (x *Record) GetTransfer() *Transfer {
 	return nil
 }
 
+func (x *Record) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+// Records are streamed ordered by their timestamp. Empty fields don't restrict the selection.
 type StreamRecordsJob struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1150,6 +1235,13 @@ type StreamRecordsJob struct {
 
 	From          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=From,proto3" json:"From,omitempty"`
 	To            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=To,proto3" json:"To,omitempty"`
+	Accounts      []string               `protobuf:"bytes,3,rep,name=Accounts,proto3" json:"Accounts,omitempty"`
+	Assets        []string               `protobuf:"bytes,4,rep,name=Assets,proto3" json:"Assets,omitempty"`
+	Plugins       []string               `protobuf:"bytes,5,rep,name=Plugins,proto3" json:"Plugins,omitempty"`         // Only records submitted by these plugins.
+	Collections   []string               `protobuf:"bytes,6,rep,name=Collections,proto3" json:"Collections,omitempty"` // Types of records to stream, "trades" and/or "transfers".
+	Filter        string                 `protobuf:"bytes,7,opt,name=Filter,proto3" json:"Filter,omitempty"`           // JSON encoded filter tree ([][]Filter) as built by the filter builder of the UI.
+	ResumeToken   string                 `protobuf:"bytes,8,opt,name=ResumeToken,proto3" json:"ResumeToken,omitempty"` // Continues a previous stream after the record that carried this token.
+	Limit         int64                  `protobuf:"varint,9,opt,name=Limit,proto3" json:"Limit,omitempty"`            // Maximum number of records to send. The stream can be continued with the token of the last record.
 	Plugin        string                 `protobuf:"bytes,90,opt,name=Plugin,proto3" json:"Plugin,omitempty"`
 	PluginVersion string                 `protobuf:"bytes,91,opt,name=PluginVersion,proto3" json:"PluginVersion,omitempty"`
 }
@@ -1200,6 +1292,55 @@ func (x *StreamRecordsJob) GetTo() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *StreamRecordsJob) GetAccounts() []string {
+	if x != nil {
+		return x.Accounts
+	}
+	return nil
+}
+
+func (x *StreamRecordsJob) GetAssets() []string {
+	if x != nil {
+		return x.Assets
+	}
+	return nil
+}
+
+func (x *StreamRecordsJob) GetPlugins() []string {
+	if x != nil {
+		return x.Plugins
+	}
+	return nil
+}
+
+func (x *StreamRecordsJob) GetCollections() []string {
+	if x != nil {
+		return x.Collections
+	}
+	return nil
+}
+
+func (x *StreamRecordsJob) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+func (x *StreamRecordsJob) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *StreamRecordsJob) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
 func (x *StreamRecordsJob) GetPlugin() string {
 	if x != nil {
 		return x.Plugin
@@ -1269,18 +1410,22 @@ func (x *Settings) GetTimeZone() string {
 	return ""
 }
 
-type AppLogMsg struct {
+// Looks up a page of trades or transfers, like the tables of the app do.
+type QueryRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Level   LogLevel `protobuf:"varint,1,opt,name=Level,proto3,enum=FTaxesGrpc.LogLevel" json:"Level,omitempty"`
-	Message string   `protobuf:"bytes,2,opt,name=Message,proto3" json:"Message,omitempty"`
-	Tags    []string `protobuf:"bytes,3,rep,name=Tags,proto3" json:"Tags,omitempty"`
+	Plugin     string `protobuf:"bytes,1,opt,name=Plugin,proto3" json:"Plugin,omitempty"`
+	Collection string `protobuf:"bytes,2,opt,name=Collection,proto3" json:"Collection,omitempty"` // "trades" or "transfers".
+	Filter     string `protobuf:"bytes,3,opt,name=Filter,proto3" json:"Filter,omitempty"`         // JSON encoded filter tree ([][]Filter) as built by the filter builder of the UI.
+	Sort       string `protobuf:"bytes,4,opt,name=Sort,proto3" json:"Sort,omitempty"`             // Field to sort by, prefixed with "-" for descending order. Defaults to "ts". Ties are ordered by id.
+	Page       int64  `protobuf:"varint,5,opt,name=Page,proto3" json:"Page,omitempty"`            // Starts at 1.
+	Limit      int64  `protobuf:"varint,6,opt,name=Limit,proto3" json:"Limit,omitempty"`          // Records per page. Defaults to 100, at most 1000.
 }
 
-func (x *AppLogMsg) Reset() {
-	*x = AppLogMsg{}
+func (x *QueryRequest) Reset() {
+	*x = QueryRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_f_taxes_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1288,13 +1433,13 @@ func (x *AppLogMsg) Reset() {
 	}
 }
 
-func (x *AppLogMsg) String() string {
+func (x *QueryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AppLogMsg) ProtoMessage() {}
+func (*QueryRequest) ProtoMessage() {}
 
-func (x *AppLogMsg) ProtoReflect() protoreflect.Message {
+func (x *QueryRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_f_taxes_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1306,42 +1451,68 @@ func (x *AppLogMsg) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AppLogMsg.ProtoReflect.Descriptor instead.
-func (*AppLogMsg) Descriptor() ([]byte, []int) {
+// Deprecated: Use QueryRequest.ProtoReflect.Descriptor instead.
+func (*QueryRequest) Descriptor() ([]byte, []int) {
 	return file_f_taxes_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *AppLogMsg) GetLevel() LogLevel {
+func (x *QueryRequest) GetPlugin() string {
 	if x != nil {
-		return x.Level
+		return x.Plugin
 	}
-	return LogLevel_INFO
+	return ""
 }
 
-func (x *AppLogMsg) GetMessage() string {
+func (x *QueryRequest) GetCollection() string {
 	if x != nil {
-		return x.Message
+		return x.Collection
 	}
 	return ""
 }
 
-func (x *AppLogMsg) GetTags() []string {
+func (x *QueryRequest) GetFilter() string {
 	if x != nil {
-		return x.Tags
+		return x.Filter
 	}
-	return nil
+	return ""
 }
 
-type TxUpdate struct {
+func (x *QueryRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetPage() int64 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *QueryRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type QueryResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Since *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=Since,proto3" json:"Since,omitempty"`
+	Records       []*Record `protobuf:"bytes,1,rep,name=Records,proto3" json:"Records,omitempty"`
+	TotalCount    int64     `protobuf:"varint,2,opt,name=TotalCount,proto3" json:"TotalCount,omitempty"`       // Records in the collection, regardless of the filter.
+	FilteredCount int64     `protobuf:"varint,3,opt,name=FilteredCount,proto3" json:"FilteredCount,omitempty"` // Records matching the filter.
+	Page          int64     `protobuf:"varint,4,opt,name=Page,proto3" json:"Page,omitempty"`
+	Limit         int64     `protobuf:"varint,5,opt,name=Limit,proto3" json:"Limit,omitempty"`
+	TotalPages    int64     `protobuf:"varint,6,opt,name=TotalPages,proto3" json:"TotalPages,omitempty"`
 }
 
-func (x *TxUpdate) Reset() {
-	*x = TxUpdate{}
+func (x *QueryResponse) Reset() {
+	*x = QueryResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_f_taxes_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1349,13 +1520,13 @@ func (x *TxUpdate) Reset() {
 	}
 }
 
-func (x *TxUpdate) String() string {
+func (x *QueryResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TxUpdate) ProtoMessage() {}
+func (*QueryResponse) ProtoMessage() {}
 
-func (x *TxUpdate) ProtoReflect() protoreflect.Message {
+func (x *QueryResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_f_taxes_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1367,29 +1538,67 @@ func (x *TxUpdate) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TxUpdate.ProtoReflect.Descriptor instead.
-func (*TxUpdate) Descriptor() ([]byte, []int) {
+// Deprecated: Use QueryResponse.ProtoReflect.Descriptor instead.
+func (*QueryResponse) Descriptor() ([]byte, []int) {
 	return file_f_taxes_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *TxUpdate) GetSince() *timestamppb.Timestamp {
+func (x *QueryResponse) GetRecords() []*Record {
 	if x != nil {
-		return x.Since
+		return x.Records
 	}
 	return nil
 }
 
-type TradeConversionJob struct {
+func (x *QueryResponse) GetTotalCount() int64 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *QueryResponse) GetFilteredCount() int64 {
+	if x != nil {
+		return x.FilteredCount
+	}
+	return 0
+}
+
+func (x *QueryResponse) GetPage() int64 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *QueryResponse) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *QueryResponse) GetTotalPages() int64 {
+	if x != nil {
+		return x.TotalPages
+	}
+	return 0
+}
+
+// Changes the fields of an existing trade listed in UpdateMask, e.g. "Comment" or "Fee.AmountC".
+// Trade.ID selects the trade and Trade.Updated must match the value the plugin read, otherwise the update fails with ABORTED.
+type UpdateTradeRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Trade          *Trade `protobuf:"bytes,1,opt,name=Trade,proto3" json:"Trade,omitempty"`
-	TargetCurrency string `protobuf:"bytes,2,opt,name=TargetCurrency,proto3" json:"TargetCurrency,omitempty"`
+	Plugin     string                 `protobuf:"bytes,1,opt,name=Plugin,proto3" json:"Plugin,omitempty"`
+	Trade      *Trade                 `protobuf:"bytes,2,opt,name=Trade,proto3" json:"Trade,omitempty"`
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=UpdateMask,proto3" json:"UpdateMask,omitempty"`
 }
 
-func (x *TradeConversionJob) Reset() {
-	*x = TradeConversionJob{}
+func (x *UpdateTradeRequest) Reset() {
+	*x = UpdateTradeRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_f_taxes_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1397,13 +1606,13 @@ func (x *TradeConversionJob) Reset() {
 	}
 }
 
-func (x *TradeConversionJob) String() string {
+func (x *UpdateTradeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TradeConversionJob) ProtoMessage() {}
+func (*UpdateTradeRequest) ProtoMessage() {}
 
-func (x *TradeConversionJob) ProtoReflect() protoreflect.Message {
+func (x *UpdateTradeRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_f_taxes_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1415,36 +1624,45 @@ func (x *TradeConversionJob) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TradeConversionJob.ProtoReflect.Descriptor instead.
-func (*TradeConversionJob) Descriptor() ([]byte, []int) {
+// Deprecated: Use UpdateTradeRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTradeRequest) Descriptor() ([]byte, []int) {
 	return file_f_taxes_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *TradeConversionJob) GetTrade() *Trade {
+func (x *UpdateTradeRequest) GetPlugin() string {
+	if x != nil {
+		return x.Plugin
+	}
+	return ""
+}
+
+func (x *UpdateTradeRequest) GetTrade() *Trade {
 	if x != nil {
 		return x.Trade
 	}
 	return nil
 }
 
-func (x *TradeConversionJob) GetTargetCurrency() string {
+func (x *UpdateTradeRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
 	if x != nil {
-		return x.TargetCurrency
+		return x.UpdateMask
 	}
-	return ""
+	return nil
 }
 
-type TransferConversionJob struct {
+// Same as UpdateTradeRequest but for transfers.
+type UpdateTransferRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Transfer       *Transfer `protobuf:"bytes,1,opt,name=Transfer,proto3" json:"Transfer,omitempty"`
-	TargetCurrency string    `protobuf:"bytes,2,opt,name=TargetCurrency,proto3" json:"TargetCurrency,omitempty"`
+	Plugin     string                 `protobuf:"bytes,1,opt,name=Plugin,proto3" json:"Plugin,omitempty"`
+	Transfer   *Transfer              `protobuf:"bytes,2,opt,name=Transfer,proto3" json:"Transfer,omitempty"`
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=UpdateMask,proto3" json:"UpdateMask,omitempty"`
 }
 
-func (x *TransferConversionJob) Reset() {
-	*x = TransferConversionJob{}
+func (x *UpdateTransferRequest) Reset() {
+	*x = UpdateTransferRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_f_taxes_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1452,13 +1670,13 @@ func (x *TransferConversionJob) Reset() {
 	}
 }
 
-func (x *TransferConversionJob) String() string {
+func (x *UpdateTransferRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TransferConversionJob) ProtoMessage() {}
+func (*UpdateTransferRequest) ProtoMessage() {}
 
-func (x *TransferConversionJob) ProtoReflect() protoreflect.Message {
+func (x *UpdateTransferRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_f_taxes_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1470,37 +1688,42 @@ func (x *TransferConversionJob) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TransferConversionJob.ProtoReflect.Descriptor instead.
-func (*TransferConversionJob) Descriptor() ([]byte, []int) {
+// Deprecated: Use UpdateTransferRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTransferRequest) Descriptor() ([]byte, []int) {
 	return file_f_taxes_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *TransferConversionJob) GetTransfer() *Transfer {
+func (x *UpdateTransferRequest) GetPlugin() string {
+	if x != nil {
+		return x.Plugin
+	}
+	return ""
+}
+
+func (x *UpdateTransferRequest) GetTransfer() *Transfer {
 	if x != nil {
 		return x.Transfer
 	}
 	return nil
 }
 
-func (x *TransferConversionJob) GetTargetCurrency() string {
+func (x *UpdateTransferRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
 	if x != nil {
-		return x.TargetCurrency
+		return x.UpdateMask
 	}
-	return ""
+	return nil
 }
 
-type PluginInfo struct {
+type PluginConfigRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ID           string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	Version      string `protobuf:"bytes,2,opt,name=Version,proto3" json:"Version,omitempty"`
-	HasCtlServer bool   `protobuf:"varint,3,opt,name=HasCtlServer,proto3" json:"HasCtlServer,omitempty"`
+	Plugin string `protobuf:"bytes,1,opt,name=Plugin,proto3" json:"Plugin,omitempty"`
 }
 
-func (x *PluginInfo) Reset() {
-	*x = PluginInfo{}
+func (x *PluginConfigRequest) Reset() {
+	*x = PluginConfigRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_f_taxes_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1508,13 +1731,13 @@ func (x *PluginInfo) Reset() {
 	}
 }
 
-func (x *PluginInfo) String() string {
+func (x *PluginConfigRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PluginInfo) ProtoMessage() {}
+func (*PluginConfigRequest) ProtoMessage() {}
 
-func (x *PluginInfo) ProtoReflect() protoreflect.Message {
+func (x *PluginConfigRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_f_taxes_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1526,46 +1749,694 @@ func (x *PluginInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PluginInfo.ProtoReflect.Descriptor instead.
-func (*PluginInfo) Descriptor() ([]byte, []int) {
+// Deprecated: Use PluginConfigRequest.ProtoReflect.Descriptor instead.
+func (*PluginConfigRequest) Descriptor() ([]byte, []int) {
 	return file_f_taxes_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *PluginInfo) GetID() string {
+func (x *PluginConfigRequest) GetPlugin() string {
 	if x != nil {
-		return x.ID
+		return x.Plugin
 	}
 	return ""
 }
 
-func (x *PluginInfo) GetVersion() string {
+// Configuration of a plugin. Secrets are returned decrypted.
+// When setting the configuration, only the given keys are changed. A key set to null is removed.
+type PluginConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Plugin string           `protobuf:"bytes,1,opt,name=Plugin,proto3" json:"Plugin,omitempty"`
+	Values *structpb.Struct `protobuf:"bytes,2,opt,name=Values,proto3" json:"Values,omitempty"`
+}
+
+func (x *PluginConfig) Reset() {
+	*x = PluginConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_f_taxes_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PluginConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginConfig) ProtoMessage() {}
+
+func (x *PluginConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_f_taxes_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginConfig.ProtoReflect.Descriptor instead.
+func (*PluginConfig) Descriptor() ([]byte, []int) {
+	return file_f_taxes_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PluginConfig) GetPlugin() string {
 	if x != nil {
-		return x.Version
+		return x.Plugin
 	}
 	return ""
 }
 
-func (x *PluginInfo) GetHasCtlServer() bool {
+func (x *PluginConfig) GetValues() *structpb.Struct {
 	if x != nil {
-		return x.HasCtlServer
+		return x.Values
 	}
-	return false
+	return nil
 }
 
-var File_f_taxes_proto protoreflect.FileDescriptor
+// Selects the events to subscribe to. Empty lists match all events.
+type EventFilter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_f_taxes_proto_rawDesc = []byte{
-	0x0a, 0x0d, 0x66, 0x2d, 0x74, 0x61, 0x78, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
-	0x0a, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x1a, 0x1b, 0x67, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70,
-	0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
-	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
-	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x62, 0x75, 0x66, 0x2f, 0x76,
-	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x71, 0x0a, 0x05, 0x50, 0x72, 0x6f, 0x70, 0x73, 0x12,
-	0x24, 0x0a, 0x0d, 0x49, 0x73, 0x4d, 0x61, 0x72, 0x67, 0x69, 0x6e, 0x54, 0x72, 0x61, 0x64, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x49, 0x73, 0x4d, 0x61, 0x72, 0x67, 0x69, 0x6e,
-	0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x49, 0x73, 0x44, 0x65, 0x72, 0x69, 0x76,
+	Plugin      string      `protobuf:"bytes,1,opt,name=Plugin,proto3" json:"Plugin,omitempty"`
+	Types       []EventType `protobuf:"varint,2,rep,packed,name=Types,proto3,enum=FTaxesGrpc.EventType" json:"Types,omitempty"`
+	Collections []string    `protobuf:"bytes,3,rep,name=Collections,proto3" json:"Collections,omitempty"` // "trades" or "transfers".
+}
+
+func (x *EventFilter) Reset() {
+	*x = EventFilter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_f_taxes_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EventFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventFilter) ProtoMessage() {}
+
+func (x *EventFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_f_taxes_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventFilter.ProtoReflect.Descriptor instead.
+func (*EventFilter) Descriptor() ([]byte, []int) {
+	return file_f_taxes_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *EventFilter) GetPlugin() string {
+	if x != nil {
+		return x.Plugin
+	}
+	return ""
+}
+
+func (x *EventFilter) GetTypes() []EventType {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *EventFilter) GetCollections() []string {
+	if x != nil {
+		return x.Collections
+	}
+	return nil
+}
+
+type Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type       EventType              `protobuf:"varint,1,opt,name=Type,proto3,enum=FTaxesGrpc.EventType" json:"Type,omitempty"`
+	Ts         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=Ts,proto3" json:"Ts,omitempty"`
+	Collection string                 `protobuf:"bytes,3,opt,name=Collection,proto3" json:"Collection,omitempty"`
+	RecordIDs  []string               `protobuf:"bytes,4,rep,name=RecordIDs,proto3" json:"RecordIDs,omitempty"` // At most 1000, changes to more records are sent as several events.
+	Plugin     string                 `protobuf:"bytes,5,opt,name=Plugin,proto3" json:"Plugin,omitempty"`       // Plugin that caused the event, e.g. the one that imported or converted the records.
+	JobID      string                 `protobuf:"bytes,6,opt,name=JobID,proto3" json:"JobID,omitempty"`
+	Count      int64                  `protobuf:"varint,7,opt,name=Count,proto3" json:"Count,omitempty"` // Number of records imported or converted.
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_f_taxes_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_f_taxes_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_f_taxes_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Event) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_RECORD_CREATED
+}
+
+func (x *Event) GetTs() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Ts
+	}
+	return nil
+}
+
+func (x *Event) GetCollection() string {
+	if x != nil {
+		return x.Collection
+	}
+	return ""
+}
+
+func (x *Event) GetRecordIDs() []string {
+	if x != nil {
+		return x.RecordIDs
+	}
+	return nil
+}
+
+func (x *Event) GetPlugin() string {
+	if x != nil {
+		return x.Plugin
+	}
+	return ""
+}
+
+func (x *Event) GetJobID() string {
+	if x != nil {
+		return x.JobID
+	}
+	return ""
+}
+
+func (x *Event) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type AppLogMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Level   LogLevel `protobuf:"varint,1,opt,name=Level,proto3,enum=FTaxesGrpc.LogLevel" json:"Level,omitempty"`
+	Message string   `protobuf:"bytes,2,opt,name=Message,proto3" json:"Message,omitempty"`
+	Tags    []string `protobuf:"bytes,3,rep,name=Tags,proto3" json:"Tags,omitempty"`
+}
+
+func (x *AppLogMsg) Reset() {
+	*x = AppLogMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_f_taxes_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AppLogMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppLogMsg) ProtoMessage() {}
+
+func (x *AppLogMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_f_taxes_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppLogMsg.ProtoReflect.Descriptor instead.
+func (*AppLogMsg) Descriptor() ([]byte, []int) {
+	return file_f_taxes_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *AppLogMsg) GetLevel() LogLevel {
+	if x != nil {
+		return x.Level
+	}
+	return LogLevel_INFO
+}
+
+func (x *AppLogMsg) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *AppLogMsg) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type TxUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Since *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=Since,proto3" json:"Since,omitempty"`
+}
+
+func (x *TxUpdate) Reset() {
+	*x = TxUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_f_taxes_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TxUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxUpdate) ProtoMessage() {}
+
+func (x *TxUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_f_taxes_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxUpdate.ProtoReflect.Descriptor instead.
+func (*TxUpdate) Descriptor() ([]byte, []int) {
+	return file_f_taxes_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *TxUpdate) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+type TradeConversionJob struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Trade          *Trade `protobuf:"bytes,1,opt,name=Trade,proto3" json:"Trade,omitempty"`
+	TargetCurrency string `protobuf:"bytes,2,opt,name=TargetCurrency,proto3" json:"TargetCurrency,omitempty"`
+}
+
+func (x *TradeConversionJob) Reset() {
+	*x = TradeConversionJob{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_f_taxes_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TradeConversionJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TradeConversionJob) ProtoMessage() {}
+
+func (x *TradeConversionJob) ProtoReflect() protoreflect.Message {
+	mi := &file_f_taxes_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TradeConversionJob.ProtoReflect.Descriptor instead.
+func (*TradeConversionJob) Descriptor() ([]byte, []int) {
+	return file_f_taxes_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *TradeConversionJob) GetTrade() *Trade {
+	if x != nil {
+		return x.Trade
+	}
+	return nil
+}
+
+func (x *TradeConversionJob) GetTargetCurrency() string {
+	if x != nil {
+		return x.TargetCurrency
+	}
+	return ""
+}
+
+type TransferConversionJob struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Transfer       *Transfer `protobuf:"bytes,1,opt,name=Transfer,proto3" json:"Transfer,omitempty"`
+	TargetCurrency string    `protobuf:"bytes,2,opt,name=TargetCurrency,proto3" json:"TargetCurrency,omitempty"`
+}
+
+func (x *TransferConversionJob) Reset() {
+	*x = TransferConversionJob{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_f_taxes_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransferConversionJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferConversionJob) ProtoMessage() {}
+
+func (x *TransferConversionJob) ProtoReflect() protoreflect.Message {
+	mi := &file_f_taxes_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferConversionJob.ProtoReflect.Descriptor instead.
+func (*TransferConversionJob) Descriptor() ([]byte, []int) {
+	return file_f_taxes_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *TransferConversionJob) GetTransfer() *Transfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+func (x *TransferConversionJob) GetTargetCurrency() string {
+	if x != nil {
+		return x.TargetCurrency
+	}
+	return ""
+}
+
+type PluginInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ID           string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Version      string `protobuf:"bytes,2,opt,name=Version,proto3" json:"Version,omitempty"`
+	HasCtlServer bool   `protobuf:"varint,3,opt,name=HasCtlServer,proto3" json:"HasCtlServer,omitempty"`
+	CtlAddress   string `protobuf:"bytes,4,opt,name=CtlAddress,proto3" json:"CtlAddress,omitempty"` // Address the plugin's PluginCtl server actually listens on. F-Taxes falls back to "ctl.address" of the manifest if empty.
+	WebAddress   string `protobuf:"bytes,5,opt,name=WebAddress,proto3" json:"WebAddress,omitempty"` // Address the plugin's web server actually listens on. F-Taxes falls back to "web.address" of the manifest if empty.
+}
+
+func (x *PluginInfo) Reset() {
+	*x = PluginInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_f_taxes_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PluginInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginInfo) ProtoMessage() {}
+
+func (x *PluginInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_f_taxes_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginInfo.ProtoReflect.Descriptor instead.
+func (*PluginInfo) Descriptor() ([]byte, []int) {
+	return file_f_taxes_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PluginInfo) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *PluginInfo) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *PluginInfo) GetHasCtlServer() bool {
+	if x != nil {
+		return x.HasCtlServer
+	}
+	return false
+}
+
+func (x *PluginInfo) GetCtlAddress() string {
+	if x != nil {
+		return x.CtlAddress
+	}
+	return ""
+}
+
+func (x *PluginInfo) GetWebAddress() string {
+	if x != nil {
+		return x.WebAddress
+	}
+	return ""
+}
+
+type ReportRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RunID     string `protobuf:"bytes,1,opt,name=RunID,proto3" json:"RunID,omitempty"`
+	TaxYear   int32  `protobuf:"varint,2,opt,name=TaxYear,proto3" json:"TaxYear,omitempty"`
+	Currency  string `protobuf:"bytes,3,opt,name=Currency,proto3" json:"Currency,omitempty"`
+	Method    string `protobuf:"bytes,4,opt,name=Method,proto3" json:"Method,omitempty"`       // Cost basis method, one of the methods the plugin lists in "reportMethods" of its manifest.
+	InputHash string `protobuf:"bytes,5,opt,name=InputHash,proto3" json:"InputHash,omitempty"` // Hash of the records the report is based on. Plugins can use it to detect stale caches.
+}
+
+func (x *ReportRequest) Reset() {
+	*x = ReportRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_f_taxes_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportRequest) ProtoMessage() {}
+
+func (x *ReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_f_taxes_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportRequest.ProtoReflect.Descriptor instead.
+func (*ReportRequest) Descriptor() ([]byte, []int) {
+	return file_f_taxes_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ReportRequest) GetRunID() string {
+	if x != nil {
+		return x.RunID
+	}
+	return ""
+}
+
+func (x *ReportRequest) GetTaxYear() int32 {
+	if x != nil {
+		return x.TaxYear
+	}
+	return 0
+}
+
+func (x *ReportRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *ReportRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *ReportRequest) GetInputHash() string {
+	if x != nil {
+		return x.InputHash
+	}
+	return ""
+}
+
+// A chunk of a file produced by a report plugin. Chunks with the same name are appended to each other in the order they are sent.
+type ReportArtifact struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`               // File name, e.g. "report.pdf".
+	ContentType string `protobuf:"bytes,2,opt,name=ContentType,proto3" json:"ContentType,omitempty"` // Only read from the first chunk of a file.
+	Data        []byte `protobuf:"bytes,3,opt,name=Data,proto3" json:"Data,omitempty"`
+}
+
+func (x *ReportArtifact) Reset() {
+	*x = ReportArtifact{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_f_taxes_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReportArtifact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportArtifact) ProtoMessage() {}
+
+func (x *ReportArtifact) ProtoReflect() protoreflect.Message {
+	mi := &file_f_taxes_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportArtifact.ProtoReflect.Descriptor instead.
+func (*ReportArtifact) Descriptor() ([]byte, []int) {
+	return file_f_taxes_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ReportArtifact) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ReportArtifact) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *ReportArtifact) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+var File_f_taxes_proto protoreflect.FileDescriptor
+
+var file_f_taxes_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x66, 0x2d, 0x74, 0x61, 0x78, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x0a, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x1a, 0x1b, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70,
+	0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72, 0x75, 0x63,
+	0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x5f, 0x6d,
+	0x61, 0x73, 0x6b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x62, 0x75, 0x66, 0x2f, 0x76,
+	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x71, 0x0a, 0x05, 0x50, 0x72, 0x6f, 0x70, 0x73, 0x12,
+	0x24, 0x0a, 0x0d, 0x49, 0x73, 0x4d, 0x61, 0x72, 0x67, 0x69, 0x6e, 0x54, 0x72, 0x61, 0x64, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x49, 0x73, 0x4d, 0x61, 0x72, 0x67, 0x69, 0x6e,
+	0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x49, 0x73, 0x44, 0x65, 0x72, 0x69, 0x76,
 	0x61, 0x74, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x49, 0x73, 0x44,
 	0x65, 0x72, 0x69, 0x76, 0x61, 0x74, 0x69, 0x76, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x49, 0x73, 0x50,
 	0x68, 0x79, 0x73, 0x69, 0x63, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x49,
@@ -1583,7 +2454,7 @@ var file_f_taxes_proto_rawDesc = []byte{
 	0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x44, 0x65, 0x63, 0x69, 0x6d, 0x61, 0x6c, 0x73, 0x12, 0x20,
 	0x0a, 0x0b, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x65, 0x64, 0x42, 0x79, 0x18, 0x08, 0x20,
 	0x01, 0x28, 0x09, 0x52, 0x0b, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x65, 0x64, 0x42, 0x79,
-	0x22, 0xa9, 0x08, 0x0a, 0x05, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x1b, 0x0a, 0x04, 0x54, 0x78,
+	0x22, 0xba, 0x08, 0x0a, 0x05, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x1b, 0x0a, 0x04, 0x54, 0x78,
 	0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xba, 0x48, 0x04, 0x72, 0x02, 0x10,
 	0x01, 0x52, 0x04, 0x54, 0x78, 0x49, 0x44, 0x12, 0x2a, 0x0a, 0x02, 0x54, 0x73, 0x18, 0x02, 0x20,
 	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
@@ -1649,192 +2520,340 @@ var file_f_taxes_proto_rawDesc = []byte{
 	0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x35, 0x0a, 0x07, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64,
 	0x18, 0xab, 0x46, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
 	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
-	0x61, 0x6d, 0x70, 0x52, 0x07, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x22, 0xb1, 0x05, 0x0a,
-	0x08, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x1b, 0x0a, 0x04, 0x54, 0x78, 0x49,
-	0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xba, 0x48, 0x04, 0x72, 0x02, 0x10, 0x01,
-	0x52, 0x04, 0x54, 0x78, 0x49, 0x44, 0x12, 0x2a, 0x0a, 0x02, 0x54, 0x73, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02,
-	0x54, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x07, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06,
-	0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x6f,
-	0x75, 0x72, 0x63, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69,
-	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e,
-	0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74,
-	0x12, 0x15, 0x0a, 0x05, 0x41, 0x73, 0x73, 0x65, 0x74, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x05, 0x41, 0x73, 0x73, 0x65, 0x74, 0x12, 0x17, 0x0a, 0x06, 0x41, 0x6d, 0x6f, 0x75, 0x6e,
-	0x74, 0x18, 0xd1, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
-	0x12, 0x33, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0xd0, 0x0f, 0x20, 0x01, 0x28,
-	0x0e, 0x32, 0x1a, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x54,
-	0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x41,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x11, 0x0a, 0x03, 0x46, 0x65, 0x65, 0x18, 0xb8, 0x17, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x03, 0x46, 0x65, 0x65, 0x12, 0x13, 0x0a, 0x04, 0x46, 0x65, 0x65, 0x43,
-	0x18, 0xb9, 0x17, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x46, 0x65, 0x65, 0x43, 0x12, 0x27, 0x0a,
-	0x0e, 0x46, 0x65, 0x65, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x65, 0x64, 0x42, 0x79, 0x18,
-	0xba, 0x17, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x46, 0x65, 0x65, 0x43, 0x6f, 0x6e, 0x76, 0x65,
-	0x72, 0x74, 0x65, 0x64, 0x42, 0x79, 0x12, 0x21, 0x0a, 0x0b, 0x46, 0x65, 0x65, 0x43, 0x75, 0x72,
-	0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0xbb, 0x17, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x46, 0x65,
-	0x65, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1d, 0x0a, 0x09, 0x46, 0x65, 0x65,
-	0x50, 0x72, 0x69, 0x63, 0x65, 0x43, 0x18, 0xbc, 0x17, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x46,
-	0x65, 0x65, 0x50, 0x72, 0x69, 0x63, 0x65, 0x43, 0x12, 0x25, 0x0a, 0x0d, 0x41, 0x73, 0x73, 0x65,
-	0x74, 0x44, 0x65, 0x63, 0x69, 0x6d, 0x61, 0x6c, 0x73, 0x18, 0xa1, 0x1f, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x0d, 0x41, 0x73, 0x73, 0x65, 0x74, 0x44, 0x65, 0x63, 0x69, 0x6d, 0x61, 0x6c, 0x73, 0x12,
-	0x21, 0x0a, 0x0b, 0x46, 0x65, 0x65, 0x44, 0x65, 0x63, 0x69, 0x6d, 0x61, 0x6c, 0x73, 0x18, 0xa2,
-	0x1f, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x46, 0x65, 0x65, 0x44, 0x65, 0x63, 0x69, 0x6d, 0x61,
-	0x6c, 0x73, 0x12, 0x17, 0x0a, 0x06, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x18, 0xa8, 0x46, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x06, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x12, 0x25, 0x0a, 0x0d, 0x50,
-	0x6c, 0x75, 0x67, 0x69, 0x6e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0xa9, 0x46, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0d, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x56, 0x65, 0x72, 0x73, 0x69,
-	0x6f, 0x6e, 0x12, 0x35, 0x0a, 0x07, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0xaa, 0x46,
+	0x61, 0x6d, 0x70, 0x52, 0x07, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x12, 0x0f, 0x0a, 0x02,
+	0x49, 0x44, 0x18, 0xac, 0x46, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x44, 0x22, 0xc2, 0x05,
+	0x0a, 0x08, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x1b, 0x0a, 0x04, 0x54, 0x78,
+	0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xba, 0x48, 0x04, 0x72, 0x02, 0x10,
+	0x01, 0x52, 0x04, 0x54, 0x78, 0x49, 0x44, 0x12, 0x2a, 0x0a, 0x02, 0x54, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x02, 0x54, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x44, 0x65, 0x73, 0x74,
+	0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x65,
+	0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e,
+	0x74, 0x12, 0x15, 0x0a, 0x05, 0x41, 0x73, 0x73, 0x65, 0x74, 0x18, 0xe8, 0x07, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x41, 0x73, 0x73, 0x65, 0x74, 0x12, 0x17, 0x0a, 0x06, 0x41, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0xd1, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x41, 0x6d, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x33, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0xd0, 0x0f, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x11, 0x0a, 0x03, 0x46, 0x65, 0x65, 0x18, 0xb8, 0x17,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x46, 0x65, 0x65, 0x12, 0x13, 0x0a, 0x04, 0x46, 0x65, 0x65,
+	0x43, 0x18, 0xb9, 0x17, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x46, 0x65, 0x65, 0x43, 0x12, 0x27,
+	0x0a, 0x0e, 0x46, 0x65, 0x65, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x65, 0x64, 0x42, 0x79,
+	0x18, 0xba, 0x17, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x46, 0x65, 0x65, 0x43, 0x6f, 0x6e, 0x76,
+	0x65, 0x72, 0x74, 0x65, 0x64, 0x42, 0x79, 0x12, 0x21, 0x0a, 0x0b, 0x46, 0x65, 0x65, 0x43, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0xbb, 0x17, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x46,
+	0x65, 0x65, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1d, 0x0a, 0x09, 0x46, 0x65,
+	0x65, 0x50, 0x72, 0x69, 0x63, 0x65, 0x43, 0x18, 0xbc, 0x17, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x46, 0x65, 0x65, 0x50, 0x72, 0x69, 0x63, 0x65, 0x43, 0x12, 0x25, 0x0a, 0x0d, 0x41, 0x73, 0x73,
+	0x65, 0x74, 0x44, 0x65, 0x63, 0x69, 0x6d, 0x61, 0x6c, 0x73, 0x18, 0xa1, 0x1f, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0d, 0x41, 0x73, 0x73, 0x65, 0x74, 0x44, 0x65, 0x63, 0x69, 0x6d, 0x61, 0x6c, 0x73,
+	0x12, 0x21, 0x0a, 0x0b, 0x46, 0x65, 0x65, 0x44, 0x65, 0x63, 0x69, 0x6d, 0x61, 0x6c, 0x73, 0x18,
+	0xa2, 0x1f, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x46, 0x65, 0x65, 0x44, 0x65, 0x63, 0x69, 0x6d,
+	0x61, 0x6c, 0x73, 0x12, 0x17, 0x0a, 0x06, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x18, 0xa8, 0x46,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x12, 0x25, 0x0a, 0x0d,
+	0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0xa9, 0x46,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x35, 0x0a, 0x07, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0xaa,
+	0x46, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x07, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x35, 0x0a, 0x07, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x64, 0x18, 0xab, 0x46, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x64, 0x12, 0x0f, 0x0a, 0x02, 0x49, 0x44, 0x18, 0xac, 0x46, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x49, 0x44, 0x22, 0xe7, 0x02, 0x0a, 0x0d, 0x53, 0x72, 0x63, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x69,
+	0x63, 0x46, 0x65, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x78, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x54, 0x78, 0x49, 0x44, 0x12, 0x2a, 0x0a, 0x02, 0x54, 0x73, 0x18, 0x02,
 	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
-	0x52, 0x07, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x35, 0x0a, 0x07, 0x55, 0x70, 0x64,
-	0x61, 0x74, 0x65, 0x64, 0x18, 0xab, 0x46, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
-	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64,
-	0x22, 0xe7, 0x02, 0x0a, 0x0d, 0x53, 0x72, 0x63, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x69, 0x63, 0x46,
-	0x65, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x78, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x54, 0x78, 0x49, 0x44, 0x12, 0x2a, 0x0a, 0x02, 0x54, 0x73, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02,
-	0x54, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x07, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07,
-	0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43,
-	0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x11, 0x0a, 0x03, 0x46, 0x65, 0x65, 0x18, 0xb8, 0x17,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x46, 0x65, 0x65, 0x12, 0x21, 0x0a, 0x0b, 0x46, 0x65, 0x65,
-	0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0xb9, 0x17, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0b, 0x46, 0x65, 0x65, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x17, 0x0a, 0x06,
-	0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x18, 0xa8, 0x46, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x50,
-	0x6c, 0x75, 0x67, 0x69, 0x6e, 0x12, 0x25, 0x0a, 0x0d, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x56,
-	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0xa9, 0x46, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x50,
-	0x6c, 0x75, 0x67, 0x69, 0x6e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x35, 0x0a, 0x07,
-	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0xaa, 0x46, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x52, 0x02, 0x54, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x11, 0x0a, 0x03, 0x46, 0x65, 0x65, 0x18,
+	0xb8, 0x17, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x46, 0x65, 0x65, 0x12, 0x21, 0x0a, 0x0b, 0x46,
+	0x65, 0x65, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0xb9, 0x17, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x46, 0x65, 0x65, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x17,
+	0x0a, 0x06, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x18, 0xa8, 0x46, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x12, 0x25, 0x0a, 0x0d, 0x50, 0x6c, 0x75, 0x67, 0x69,
+	0x6e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0xa9, 0x46, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x35,
+	0x0a, 0x07, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0xaa, 0x46, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x35, 0x0a, 0x07, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64,
+	0x18, 0xab, 0x46, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x07, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x22, 0x67, 0x0a, 0x0b,
+	0x4a, 0x6f, 0x62, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x49,
+	0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x4c, 0x61, 0x62, 0x65,
+	0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x16, 0x0a,
+	0x06, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x50,
+	0x6c, 0x75, 0x67, 0x69, 0x6e, 0x22, 0x85, 0x01, 0x0a, 0x06, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x12, 0x27, 0x0a, 0x05, 0x54, 0x72, 0x61, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61,
+	0x64, 0x65, 0x52, 0x05, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x30, 0x0a, 0x08, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x66, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x46, 0x54,
+	0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x52, 0x08, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x52,
+	0x65, 0x73, 0x75, 0x6d, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0xec, 0x02,
+	0x0a, 0x10, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x4a,
+	0x6f, 0x62, 0x12, 0x2e, 0x0a, 0x04, 0x46, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x46, 0x72,
+	0x6f, 0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x54, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
 	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x43, 0x72, 0x65, 0x61,
-	0x74, 0x65, 0x64, 0x12, 0x35, 0x0a, 0x07, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x18, 0xab,
-	0x46, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
-	0x70, 0x52, 0x07, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x22, 0x67, 0x0a, 0x0b, 0x4a, 0x6f,
-	0x62, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x61, 0x62,
-	0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x12,
-	0x1a, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x08, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x50,
-	0x6c, 0x75, 0x67, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x50, 0x6c, 0x75,
-	0x67, 0x69, 0x6e, 0x22, 0x63, 0x0a, 0x06, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x27, 0x0a,
-	0x05, 0x54, 0x72, 0x61, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x46,
-	0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x64, 0x65, 0x52,
-	0x05, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x30, 0x0a, 0x08, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66,
-	0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65,
-	0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x08,
-	0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x22, 0xac, 0x01, 0x0a, 0x10, 0x53, 0x74, 0x72,
-	0x65, 0x61, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x4a, 0x6f, 0x62, 0x12, 0x2e, 0x0a,
-	0x04, 0x46, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
-	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x2a, 0x0a,
-	0x02, 0x54, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
-	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x54, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x50, 0x6c, 0x75,
-	0x67, 0x69, 0x6e, 0x18, 0x5a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x50, 0x6c, 0x75, 0x67, 0x69,
-	0x6e, 0x12, 0x24, 0x0a, 0x0d, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x56, 0x65, 0x72, 0x73, 0x69,
-	0x6f, 0x6e, 0x18, 0x5b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e,
-	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x4e, 0x0a, 0x08, 0x53, 0x65, 0x74, 0x74, 0x69,
-	0x6e, 0x67, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x44, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x46,
-	0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x44, 0x61, 0x74,
-	0x65, 0x54, 0x69, 0x6d, 0x65, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x54,
-	0x69, 0x6d, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x54,
-	0x69, 0x6d, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x22, 0x65, 0x0a, 0x09, 0x41, 0x70, 0x70, 0x4c, 0x6f,
-	0x67, 0x4d, 0x73, 0x67, 0x12, 0x2a, 0x0a, 0x05, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63,
-	0x2e, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x05, 0x4c, 0x65, 0x76, 0x65, 0x6c,
-	0x12, 0x18, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x61,
-	0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x54, 0x61, 0x67, 0x73, 0x22, 0x3c,
-	0x0a, 0x08, 0x54, 0x78, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x53, 0x69,
-	0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
-	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x53, 0x69, 0x6e, 0x63, 0x65, 0x22, 0x65, 0x0a, 0x12,
-	0x54, 0x72, 0x61, 0x64, 0x65, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x4a,
-	0x6f, 0x62, 0x12, 0x27, 0x0a, 0x05, 0x54, 0x72, 0x61, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x11, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x54,
-	0x72, 0x61, 0x64, 0x65, 0x52, 0x05, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x54,
-	0x61, 0x72, 0x67, 0x65, 0x74, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x43, 0x75, 0x72, 0x72, 0x65,
-	0x6e, 0x63, 0x79, 0x22, 0x71, 0x0a, 0x15, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x43,
-	0x6f, 0x6e, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x12, 0x30, 0x0a, 0x08,
-	0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14,
-	0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x6e,
-	0x73, 0x66, 0x65, 0x72, 0x52, 0x08, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x26,
-	0x0a, 0x0e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x43, 0x75,
-	0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x22, 0x5a, 0x0a, 0x0a, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e,
-	0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x02, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x22,
-	0x0a, 0x0c, 0x48, 0x61, 0x73, 0x43, 0x74, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x48, 0x61, 0x73, 0x43, 0x74, 0x6c, 0x53, 0x65, 0x72, 0x76,
-	0x65, 0x72, 0x2a, 0x1d, 0x0a, 0x08, 0x54, 0x78, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x07,
-	0x0a, 0x03, 0x42, 0x55, 0x59, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x53, 0x45, 0x4c, 0x4c, 0x10,
-	0x01, 0x2a, 0x2d, 0x0a, 0x0e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x41, 0x63, 0x74,
-	0x69, 0x6f, 0x6e, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x45, 0x50, 0x4f, 0x53, 0x49, 0x54, 0x10, 0x00,
-	0x12, 0x0e, 0x0a, 0x0a, 0x57, 0x49, 0x54, 0x48, 0x44, 0x52, 0x41, 0x57, 0x41, 0x4c, 0x10, 0x01,
-	0x2a, 0x21, 0x0a, 0x09, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x09, 0x0a,
-	0x05, 0x54, 0x41, 0x4b, 0x45, 0x52, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x4d, 0x41, 0x4b, 0x45,
-	0x52, 0x10, 0x01, 0x2a, 0x49, 0x0a, 0x08, 0x43, 0x6f, 0x73, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12,
-	0x11, 0x0a, 0x0d, 0x45, 0x58, 0x45, 0x43, 0x55, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x46, 0x45, 0x45,
-	0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x46, 0x55, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x46, 0x45,
-	0x45, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x42, 0x4f, 0x52, 0x52, 0x4f, 0x57, 0x5f, 0x46, 0x45,
-	0x45, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x4f, 0x54, 0x48, 0x45, 0x52, 0x10, 0x09, 0x2a, 0x27,
-	0x0a, 0x08, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x4e,
-	0x46, 0x4f, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x57, 0x41, 0x52, 0x4e, 0x10, 0x01, 0x12, 0x07,
-	0x0a, 0x03, 0x45, 0x52, 0x52, 0x10, 0x02, 0x32, 0x8b, 0x04, 0x0a, 0x06, 0x46, 0x54, 0x61, 0x78,
-	0x65, 0x73, 0x12, 0x38, 0x0a, 0x0b, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x54, 0x72, 0x61, 0x64,
-	0x65, 0x12, 0x11, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x54,
-	0x72, 0x61, 0x64, 0x65, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x3e, 0x0a, 0x0e,
-	0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x14,
-	0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x6e,
-	0x73, 0x66, 0x65, 0x72, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x45, 0x0a, 0x10,
-	0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x69, 0x63, 0x46, 0x65, 0x65,
-	0x12, 0x19, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x72,
-	0x63, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x69, 0x63, 0x46, 0x65, 0x65, 0x1a, 0x16, 0x2e, 0x67, 0x6f,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x54, 0x6f, 0x12, 0x1a,
+	0x0a, 0x08, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x08, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x73,
+	0x73, 0x65, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x41, 0x73, 0x73, 0x65,
+	0x74, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x73, 0x18, 0x05, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x07, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x73, 0x12, 0x20, 0x0a, 0x0b,
+	0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0b, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16,
+	0x0a, 0x06, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x52, 0x65, 0x73,
+	0x75, 0x6d, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x16,
+	0x0a, 0x06, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x18, 0x5a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x12, 0x24, 0x0a, 0x0d, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x5b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x50,
+	0x6c, 0x75, 0x67, 0x69, 0x6e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x4e, 0x0a, 0x08,
+	0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x44, 0x61, 0x74, 0x65,
+	0x54, 0x69, 0x6d, 0x65, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0e, 0x44, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74,
+	0x12, 0x1a, 0x0a, 0x08, 0x54, 0x69, 0x6d, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x54, 0x69, 0x6d, 0x65, 0x5a, 0x6f, 0x6e, 0x65, 0x22, 0x9c, 0x01, 0x0a,
+	0x0c, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x50,
+	0x6c, 0x75, 0x67, 0x69, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x43, 0x6f, 0x6c, 0x6c, 0x65,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x12, 0x0a,
+	0x04, 0x53, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x53, 0x6f, 0x72,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x50, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x04, 0x50, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0xcd, 0x01, 0x0a, 0x0d,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a,
+	0x07, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x52, 0x07, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x54,
+	0x6f, 0x74, 0x61, 0x6c, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0a, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x46,
+	0x69, 0x6c, 0x74, 0x65, 0x72, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0d, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x50, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x04, 0x50, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x54,
+	0x6f, 0x74, 0x61, 0x6c, 0x50, 0x61, 0x67, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0a, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x50, 0x61, 0x67, 0x65, 0x73, 0x22, 0x91, 0x01, 0x0a, 0x12,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x72, 0x61, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x12, 0x27, 0x0a, 0x05, 0x54, 0x72,
+	0x61, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x46, 0x54, 0x61, 0x78,
+	0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x64, 0x65, 0x52, 0x05, 0x54, 0x72,
+	0x61, 0x64, 0x65, 0x12, 0x3a, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x73,
+	0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4d,
+	0x61, 0x73, 0x6b, 0x52, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x73, 0x6b, 0x22,
+	0x9d, 0x01, 0x0a, 0x15, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66,
+	0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x50, 0x6c, 0x75,
+	0x67, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x50, 0x6c, 0x75, 0x67, 0x69,
+	0x6e, 0x12, 0x30, 0x0a, 0x08, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63,
+	0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x08, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x66, 0x65, 0x72, 0x12, 0x3a, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x73,
+	0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4d,
+	0x61, 0x73, 0x6b, 0x52, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x73, 0x6b, 0x22,
+	0x2d, 0x0a, 0x13, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x22, 0x57,
+	0x0a, 0x0c, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x16,
+	0x0a, 0x06, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x12, 0x2f, 0x0a, 0x06, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52,
+	0x06, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x74, 0x0a, 0x0b, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x12, 0x2b,
+	0x0a, 0x05, 0x54, 0x79, 0x70, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x15, 0x2e,
+	0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x05, 0x54, 0x79, 0x70, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x43,
+	0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0b, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xe0, 0x01,
+	0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72,
+	0x70, 0x63, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x2a, 0x0a, 0x02, 0x54, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x54, 0x73, 0x12, 0x1e,
+	0x0a, 0x0a, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c,
+	0x0a, 0x09, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x49, 0x44, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x09, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x49, 0x44, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x50, 0x6c,
+	0x75, 0x67, 0x69, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x4a, 0x6f, 0x62, 0x49, 0x44, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x4a, 0x6f, 0x62, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x22, 0x65, 0x0a, 0x09, 0x41, 0x70, 0x70, 0x4c, 0x6f, 0x67, 0x4d, 0x73, 0x67, 0x12, 0x2a, 0x0a,
+	0x05, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x46,
+	0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76,
+	0x65, 0x6c, 0x52, 0x05, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x61, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x04, 0x54, 0x61, 0x67, 0x73, 0x22, 0x3c, 0x0a, 0x08, 0x54, 0x78, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x53, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05,
+	0x53, 0x69, 0x6e, 0x63, 0x65, 0x22, 0x65, 0x0a, 0x12, 0x54, 0x72, 0x61, 0x64, 0x65, 0x43, 0x6f,
+	0x6e, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x12, 0x27, 0x0a, 0x05, 0x54,
+	0x72, 0x61, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x46, 0x54, 0x61,
+	0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x64, 0x65, 0x52, 0x05, 0x54,
+	0x72, 0x61, 0x64, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x43, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x54, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x22, 0x71, 0x0a, 0x15,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x12, 0x30, 0x0a, 0x08, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73,
+	0x47, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x08, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x26, 0x0a, 0x0e, 0x54, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x22,
+	0x9a, 0x01, 0x0a, 0x0a, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0e,
+	0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x44, 0x12, 0x18,
+	0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x48, 0x61, 0x73, 0x43,
+	0x74, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c,
+	0x48, 0x61, 0x73, 0x43, 0x74, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0a,
+	0x43, 0x74, 0x6c, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x43, 0x74, 0x6c, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1e, 0x0a, 0x0a,
+	0x57, 0x65, 0x62, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x57, 0x65, 0x62, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0x91, 0x01, 0x0a,
+	0x0d, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14,
+	0x0a, 0x05, 0x52, 0x75, 0x6e, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x52,
+	0x75, 0x6e, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x54, 0x61, 0x78, 0x59, 0x65, 0x61, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x54, 0x61, 0x78, 0x59, 0x65, 0x61, 0x72, 0x12, 0x1a,
+	0x0a, 0x08, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x4d, 0x65,
+	0x74, 0x68, 0x6f, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x4d, 0x65, 0x74, 0x68,
+	0x6f, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x48, 0x61, 0x73, 0x68, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x48, 0x61, 0x73, 0x68,
+	0x22, 0x5a, 0x0a, 0x0e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61,
+	0x63, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x43, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x61,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x44, 0x61, 0x74, 0x61, 0x2a, 0x1d, 0x0a, 0x08,
+	0x54, 0x78, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x55, 0x59, 0x10,
+	0x00, 0x12, 0x08, 0x0a, 0x04, 0x53, 0x45, 0x4c, 0x4c, 0x10, 0x01, 0x2a, 0x2d, 0x0a, 0x0e, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0b, 0x0a,
+	0x07, 0x44, 0x45, 0x50, 0x4f, 0x53, 0x49, 0x54, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x57, 0x49,
+	0x54, 0x48, 0x44, 0x52, 0x41, 0x57, 0x41, 0x4c, 0x10, 0x01, 0x2a, 0x21, 0x0a, 0x09, 0x4f, 0x72,
+	0x64, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x54, 0x41, 0x4b, 0x45, 0x52,
+	0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x4d, 0x41, 0x4b, 0x45, 0x52, 0x10, 0x01, 0x2a, 0x49, 0x0a,
+	0x08, 0x43, 0x6f, 0x73, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x11, 0x0a, 0x0d, 0x45, 0x58, 0x45,
+	0x43, 0x55, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x46, 0x45, 0x45, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b,
+	0x46, 0x55, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x46, 0x45, 0x45, 0x10, 0x01, 0x12, 0x0e, 0x0a,
+	0x0a, 0x42, 0x4f, 0x52, 0x52, 0x4f, 0x57, 0x5f, 0x46, 0x45, 0x45, 0x10, 0x02, 0x12, 0x09, 0x0a,
+	0x05, 0x4f, 0x54, 0x48, 0x45, 0x52, 0x10, 0x09, 0x2a, 0x27, 0x0a, 0x08, 0x4c, 0x6f, 0x67, 0x4c,
+	0x65, 0x76, 0x65, 0x6c, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x4e, 0x46, 0x4f, 0x10, 0x00, 0x12, 0x08,
+	0x0a, 0x04, 0x57, 0x41, 0x52, 0x4e, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x45, 0x52, 0x52, 0x10,
+	0x02, 0x2a, 0x8b, 0x01, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x12, 0x0a, 0x0e, 0x52, 0x45, 0x43, 0x4f, 0x52, 0x44, 0x5f, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45,
+	0x44, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x52, 0x45, 0x43, 0x4f, 0x52, 0x44, 0x5f, 0x55, 0x50,
+	0x44, 0x41, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x52, 0x45, 0x43, 0x4f, 0x52,
+	0x44, 0x5f, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x02, 0x12, 0x14, 0x0a, 0x10, 0x53,
+	0x45, 0x54, 0x54, 0x49, 0x4e, 0x47, 0x53, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x47, 0x45, 0x44, 0x10,
+	0x03, 0x12, 0x13, 0x0a, 0x0f, 0x49, 0x4d, 0x50, 0x4f, 0x52, 0x54, 0x5f, 0x46, 0x49, 0x4e, 0x49,
+	0x53, 0x48, 0x45, 0x44, 0x10, 0x04, 0x12, 0x17, 0x0a, 0x13, 0x43, 0x4f, 0x4e, 0x56, 0x45, 0x52,
+	0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x46, 0x49, 0x4e, 0x49, 0x53, 0x48, 0x45, 0x44, 0x10, 0x05, 0x32,
+	0xab, 0x07, 0x0a, 0x06, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x12, 0x38, 0x0a, 0x0b, 0x53, 0x75,
+	0x62, 0x6d, 0x69, 0x74, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x11, 0x2e, 0x46, 0x54, 0x61, 0x78,
+	0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x64, 0x65, 0x1a, 0x16, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x12, 0x3e, 0x0a, 0x0e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x14, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47,
+	0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x1a, 0x16, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x12, 0x40, 0x0a, 0x0b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x72,
+	0x61, 0x64, 0x65, 0x12, 0x1e, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63,
+	0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x72, 0x61, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63,
+	0x2e, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x49, 0x0a, 0x0e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x21, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65,
+	0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x46, 0x54,
+	0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x12, 0x45, 0x0a, 0x10, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x47, 0x65, 0x6e, 0x65, 0x72,
+	0x69, 0x63, 0x46, 0x65, 0x65, 0x12, 0x19, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72,
+	0x70, 0x63, 0x2e, 0x53, 0x72, 0x63, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x69, 0x63, 0x46, 0x65, 0x65,
+	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x42, 0x0a, 0x0f, 0x53, 0x68, 0x6f, 0x77,
+	0x4a, 0x6f, 0x62, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x17, 0x2e, 0x46, 0x54,
+	0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x4a, 0x6f, 0x62, 0x50, 0x72, 0x6f, 0x67,
+	0x72, 0x65, 0x73, 0x73, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x3b, 0x0a, 0x0b,
+	0x47, 0x65, 0x74, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f,
 	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
-	0x70, 0x74, 0x79, 0x12, 0x42, 0x0a, 0x0f, 0x53, 0x68, 0x6f, 0x77, 0x4a, 0x6f, 0x62, 0x50, 0x72,
-	0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x17, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47,
-	0x72, 0x70, 0x63, 0x2e, 0x4a, 0x6f, 0x62, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x1a,
-	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x3b, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x53, 0x65,
-	0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x14,
-	0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x74, 0x74,
-	0x69, 0x6e, 0x67, 0x73, 0x12, 0x37, 0x0a, 0x06, 0x41, 0x70, 0x70, 0x4c, 0x6f, 0x67, 0x12, 0x15,
-	0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x70, 0x70, 0x4c,
-	0x6f, 0x67, 0x4d, 0x73, 0x67, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x43, 0x0a,
-	0x0d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x1c,
-	0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x72, 0x65,
-	0x61, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x4a, 0x6f, 0x62, 0x1a, 0x12, 0x2e, 0x46,
-	0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
-	0x30, 0x01, 0x12, 0x41, 0x0a, 0x0f, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x48, 0x65, 0x61, 0x72,
-	0x74, 0x62, 0x65, 0x61, 0x74, 0x12, 0x16, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72,
-	0x70, 0x63, 0x2e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x1a, 0x16, 0x2e,
-	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
-	0x45, 0x6d, 0x70, 0x74, 0x79, 0x32, 0xaa, 0x01, 0x0a, 0x09, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e,
-	0x43, 0x74, 0x6c, 0x12, 0x49, 0x0a, 0x14, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x50, 0x72,
-	0x69, 0x63, 0x65, 0x73, 0x49, 0x6e, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x1e, 0x2e, 0x46, 0x54,
-	0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x64, 0x65, 0x43, 0x6f,
-	0x6e, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x1a, 0x11, 0x2e, 0x46, 0x54,
-	0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x52,
-	0x0a, 0x17, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x73, 0x49,
-	0x6e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x21, 0x2e, 0x46, 0x54, 0x61, 0x78,
-	0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x43,
-	0x6f, 0x6e, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x4a, 0x6f, 0x62, 0x1a, 0x14, 0x2e, 0x46,
-	0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66,
-	0x65, 0x72, 0x42, 0x22, 0x5a, 0x20, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
-	0x2f, 0x66, 0x2d, 0x74, 0x61, 0x78, 0x65, 0x73, 0x2f, 0x66, 0x2d, 0x74, 0x61, 0x78, 0x65, 0x73,
-	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x70, 0x74, 0x79, 0x1a, 0x14, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63,
+	0x2e, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x37, 0x0a, 0x06, 0x41, 0x70, 0x70,
+	0x4c, 0x6f, 0x67, 0x12, 0x15, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63,
+	0x2e, 0x41, 0x70, 0x70, 0x4c, 0x6f, 0x67, 0x4d, 0x73, 0x67, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x12, 0x43, 0x0a, 0x0d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x73, 0x12, 0x1c, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63,
+	0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x4a, 0x6f,
+	0x62, 0x1a, 0x12, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x52,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x30, 0x01, 0x12, 0x43, 0x0a, 0x0c, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x18, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73,
+	0x47, 0x72, 0x70, 0x63, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x19, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x51,
+	0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a, 0x0f,
+	0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x12,
+	0x16, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x6c, 0x75,
+	0x67, 0x69, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12,
+	0x4c, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x12, 0x1f, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e,
+	0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63,
+	0x2e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x43, 0x0a,
+	0x0f, 0x53, 0x65, 0x74, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x12, 0x18, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x6c,
+	0x75, 0x67, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x12, 0x39, 0x0a, 0x09, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12,
+	0x17, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x1a, 0x11, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65,
+	0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x32, 0xf5, 0x01,
+	0x0a, 0x09, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x74, 0x6c, 0x12, 0x49, 0x0a, 0x14, 0x43,
+	0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x73, 0x49, 0x6e, 0x54, 0x72,
+	0x61, 0x64, 0x65, 0x12, 0x1e, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63,
+	0x2e, 0x54, 0x72, 0x61, 0x64, 0x65, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x4a, 0x6f, 0x62, 0x1a, 0x11, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63,
+	0x2e, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x52, 0x0a, 0x17, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72,
+	0x74, 0x50, 0x72, 0x69, 0x63, 0x65, 0x73, 0x49, 0x6e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x12, 0x21, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x4a, 0x6f, 0x62, 0x1a, 0x14, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70,
+	0x63, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x49, 0x0a, 0x0e, 0x47, 0x65,
+	0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x19, 0x2e, 0x46,
+	0x54, 0x61, 0x78, 0x65, 0x73, 0x47, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x46, 0x54, 0x61, 0x78, 0x65, 0x73,
+	0x47, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x41, 0x72, 0x74, 0x69, 0x66,
+	0x61, 0x63, 0x74, 0x30, 0x01, 0x42, 0x22, 0x5a, 0x20, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x66, 0x2d, 0x74, 0x61, 0x78, 0x65, 0x73, 0x2f, 0x66, 0x2d, 0x74, 0x61,
+	0x78, 0x65, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -1849,81 +2868,117 @@ func file_f_taxes_proto_rawDescGZIP() []byte {
 	return file_f_taxes_proto_rawDescData
 }
 
-var file_f_taxes_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
-var file_f_taxes_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_f_taxes_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_f_taxes_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_f_taxes_proto_goTypes = []any{
 	(TxAction)(0),                 // 0: FTaxesGrpc.TxAction
 	(TransferAction)(0),           // 1: FTaxesGrpc.TransferAction
 	(OrderType)(0),                // 2: FTaxesGrpc.OrderType
 	(CostType)(0),                 // 3: FTaxesGrpc.CostType
 	(LogLevel)(0),                 // 4: FTaxesGrpc.LogLevel
-	(*Props)(nil),                 // 5: FTaxesGrpc.Props
-	(*Cost)(nil),                  // 6: FTaxesGrpc.Cost
-	(*Trade)(nil),                 // 7: FTaxesGrpc.Trade
-	(*Transfer)(nil),              // 8: FTaxesGrpc.Transfer
-	(*SrcGenericFee)(nil),         // 9: FTaxesGrpc.SrcGenericFee
-	(*JobProgress)(nil),           // 10: FTaxesGrpc.JobProgress
-	(*Record)(nil),                // 11: FTaxesGrpc.Record
-	(*StreamRecordsJob)(nil),      // 12: FTaxesGrpc.StreamRecordsJob
-	(*Settings)(nil),              // 13: FTaxesGrpc.Settings
-	(*AppLogMsg)(nil),             // 14: FTaxesGrpc.AppLogMsg
-	(*TxUpdate)(nil),              // 15: FTaxesGrpc.TxUpdate
-	(*TradeConversionJob)(nil),    // 16: FTaxesGrpc.TradeConversionJob
-	(*TransferConversionJob)(nil), // 17: FTaxesGrpc.TransferConversionJob
-	(*PluginInfo)(nil),            // 18: FTaxesGrpc.PluginInfo
-	(*timestamppb.Timestamp)(nil), // 19: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),         // 20: google.protobuf.Empty
+	(EventType)(0),                // 5: FTaxesGrpc.EventType
+	(*Props)(nil),                 // 6: FTaxesGrpc.Props
+	(*Cost)(nil),                  // 7: FTaxesGrpc.Cost
+	(*Trade)(nil),                 // 8: FTaxesGrpc.Trade
+	(*Transfer)(nil),              // 9: FTaxesGrpc.Transfer
+	(*SrcGenericFee)(nil),         // 10: FTaxesGrpc.SrcGenericFee
+	(*JobProgress)(nil),           // 11: FTaxesGrpc.JobProgress
+	(*Record)(nil),                // 12: FTaxesGrpc.Record
+	(*StreamRecordsJob)(nil),      // 13: FTaxesGrpc.StreamRecordsJob
+	(*Settings)(nil),              // 14: FTaxesGrpc.Settings
+	(*QueryRequest)(nil),          // 15: FTaxesGrpc.QueryRequest
+	(*QueryResponse)(nil),         // 16: FTaxesGrpc.QueryResponse
+	(*UpdateTradeRequest)(nil),    // 17: FTaxesGrpc.UpdateTradeRequest
+	(*UpdateTransferRequest)(nil), // 18: FTaxesGrpc.UpdateTransferRequest
+	(*PluginConfigRequest)(nil),   // 19: FTaxesGrpc.PluginConfigRequest
+	(*PluginConfig)(nil),          // 20: FTaxesGrpc.PluginConfig
+	(*EventFilter)(nil),           // 21: FTaxesGrpc.EventFilter
+	(*Event)(nil),                 // 22: FTaxesGrpc.Event
+	(*AppLogMsg)(nil),             // 23: FTaxesGrpc.AppLogMsg
+	(*TxUpdate)(nil),              // 24: FTaxesGrpc.TxUpdate
+	(*TradeConversionJob)(nil),    // 25: FTaxesGrpc.TradeConversionJob
+	(*TransferConversionJob)(nil), // 26: FTaxesGrpc.TransferConversionJob
+	(*PluginInfo)(nil),            // 27: FTaxesGrpc.PluginInfo
+	(*ReportRequest)(nil),         // 28: FTaxesGrpc.ReportRequest
+	(*ReportArtifact)(nil),        // 29: FTaxesGrpc.ReportArtifact
+	(*timestamppb.Timestamp)(nil), // 30: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil), // 31: google.protobuf.FieldMask
+	(*structpb.Struct)(nil),       // 32: google.protobuf.Struct
+	(*emptypb.Empty)(nil),         // 33: google.protobuf.Empty
 }
 var file_f_taxes_proto_depIdxs = []int32{
-	19, // 0: FTaxesGrpc.Trade.Ts:type_name -> google.protobuf.Timestamp
+	30, // 0: FTaxesGrpc.Trade.Ts:type_name -> google.protobuf.Timestamp
 	0,  // 1: FTaxesGrpc.Trade.Action:type_name -> FTaxesGrpc.TxAction
 	2,  // 2: FTaxesGrpc.Trade.OrderType:type_name -> FTaxesGrpc.OrderType
-	6,  // 3: FTaxesGrpc.Trade.Fee:type_name -> FTaxesGrpc.Cost
-	6,  // 4: FTaxesGrpc.Trade.QuoteFee:type_name -> FTaxesGrpc.Cost
-	5,  // 5: FTaxesGrpc.Trade.Props:type_name -> FTaxesGrpc.Props
-	6,  // 6: FTaxesGrpc.Trade.OtherCosts:type_name -> FTaxesGrpc.Cost
-	19, // 7: FTaxesGrpc.Trade.Created:type_name -> google.protobuf.Timestamp
-	19, // 8: FTaxesGrpc.Trade.Updated:type_name -> google.protobuf.Timestamp
-	19, // 9: FTaxesGrpc.Transfer.Ts:type_name -> google.protobuf.Timestamp
+	7,  // 3: FTaxesGrpc.Trade.Fee:type_name -> FTaxesGrpc.Cost
+	7,  // 4: FTaxesGrpc.Trade.QuoteFee:type_name -> FTaxesGrpc.Cost
+	6,  // 5: FTaxesGrpc.Trade.Props:type_name -> FTaxesGrpc.Props
+	7,  // 6: FTaxesGrpc.Trade.OtherCosts:type_name -> FTaxesGrpc.Cost
+	30, // 7: FTaxesGrpc.Trade.Created:type_name -> google.protobuf.Timestamp
+	30, // 8: FTaxesGrpc.Trade.Updated:type_name -> google.protobuf.Timestamp
+	30, // 9: FTaxesGrpc.Transfer.Ts:type_name -> google.protobuf.Timestamp
 	1,  // 10: FTaxesGrpc.Transfer.Action:type_name -> FTaxesGrpc.TransferAction
-	19, // 11: FTaxesGrpc.Transfer.Created:type_name -> google.protobuf.Timestamp
-	19, // 12: FTaxesGrpc.Transfer.Updated:type_name -> google.protobuf.Timestamp
-	19, // 13: FTaxesGrpc.SrcGenericFee.Ts:type_name -> google.protobuf.Timestamp
-	19, // 14: FTaxesGrpc.SrcGenericFee.Created:type_name -> google.protobuf.Timestamp
-	19, // 15: FTaxesGrpc.SrcGenericFee.Updated:type_name -> google.protobuf.Timestamp
-	7,  // 16: FTaxesGrpc.Record.Trade:type_name -> FTaxesGrpc.Trade
-	8,  // 17: FTaxesGrpc.Record.Transfer:type_name -> FTaxesGrpc.Transfer
-	19, // 18: FTaxesGrpc.StreamRecordsJob.From:type_name -> google.protobuf.Timestamp
-	19, // 19: FTaxesGrpc.StreamRecordsJob.To:type_name -> google.protobuf.Timestamp
-	4,  // 20: FTaxesGrpc.AppLogMsg.Level:type_name -> FTaxesGrpc.LogLevel
-	19, // 21: FTaxesGrpc.TxUpdate.Since:type_name -> google.protobuf.Timestamp
-	7,  // 22: FTaxesGrpc.TradeConversionJob.Trade:type_name -> FTaxesGrpc.Trade
-	8,  // 23: FTaxesGrpc.TransferConversionJob.Transfer:type_name -> FTaxesGrpc.Transfer
-	7,  // 24: FTaxesGrpc.FTaxes.SubmitTrade:input_type -> FTaxesGrpc.Trade
-	8,  // 25: FTaxesGrpc.FTaxes.SubmitTransfer:input_type -> FTaxesGrpc.Transfer
-	9,  // 26: FTaxesGrpc.FTaxes.SubmitGenericFee:input_type -> FTaxesGrpc.SrcGenericFee
-	10, // 27: FTaxesGrpc.FTaxes.ShowJobProgress:input_type -> FTaxesGrpc.JobProgress
-	20, // 28: FTaxesGrpc.FTaxes.GetSettings:input_type -> google.protobuf.Empty
-	14, // 29: FTaxesGrpc.FTaxes.AppLog:input_type -> FTaxesGrpc.AppLogMsg
-	12, // 30: FTaxesGrpc.FTaxes.StreamRecords:input_type -> FTaxesGrpc.StreamRecordsJob
-	18, // 31: FTaxesGrpc.FTaxes.PluginHeartbeat:input_type -> FTaxesGrpc.PluginInfo
-	16, // 32: FTaxesGrpc.PluginCtl.ConvertPricesInTrade:input_type -> FTaxesGrpc.TradeConversionJob
-	17, // 33: FTaxesGrpc.PluginCtl.ConvertPricesInTransfer:input_type -> FTaxesGrpc.TransferConversionJob
-	20, // 34: FTaxesGrpc.FTaxes.SubmitTrade:output_type -> google.protobuf.Empty
-	20, // 35: FTaxesGrpc.FTaxes.SubmitTransfer:output_type -> google.protobuf.Empty
-	20, // 36: FTaxesGrpc.FTaxes.SubmitGenericFee:output_type -> google.protobuf.Empty
-	20, // 37: FTaxesGrpc.FTaxes.ShowJobProgress:output_type -> google.protobuf.Empty
-	13, // 38: FTaxesGrpc.FTaxes.GetSettings:output_type -> FTaxesGrpc.Settings
-	20, // 39: FTaxesGrpc.FTaxes.AppLog:output_type -> google.protobuf.Empty
-	11, // 40: FTaxesGrpc.FTaxes.StreamRecords:output_type -> FTaxesGrpc.Record
-	20, // 41: FTaxesGrpc.FTaxes.PluginHeartbeat:output_type -> google.protobuf.Empty
-	7,  // 42: FTaxesGrpc.PluginCtl.ConvertPricesInTrade:output_type -> FTaxesGrpc.Trade
-	8,  // 43: FTaxesGrpc.PluginCtl.ConvertPricesInTransfer:output_type -> FTaxesGrpc.Transfer
-	34, // [34:44] is the sub-list for method output_type
-	24, // [24:34] is the sub-list for method input_type
-	24, // [24:24] is the sub-list for extension type_name
-	24, // [24:24] is the sub-list for extension extendee
-	0,  // [0:24] is the sub-list for field type_name
+	30, // 11: FTaxesGrpc.Transfer.Created:type_name -> google.protobuf.Timestamp
+	30, // 12: FTaxesGrpc.Transfer.Updated:type_name -> google.protobuf.Timestamp
+	30, // 13: FTaxesGrpc.SrcGenericFee.Ts:type_name -> google.protobuf.Timestamp
+	30, // 14: FTaxesGrpc.SrcGenericFee.Created:type_name -> google.protobuf.Timestamp
+	30, // 15: FTaxesGrpc.SrcGenericFee.Updated:type_name -> google.protobuf.Timestamp
+	8,  // 16: FTaxesGrpc.Record.Trade:type_name -> FTaxesGrpc.Trade
+	9,  // 17: FTaxesGrpc.Record.Transfer:type_name -> FTaxesGrpc.Transfer
+	30, // 18: FTaxesGrpc.StreamRecordsJob.From:type_name -> google.protobuf.Timestamp
+	30, // 19: FTaxesGrpc.StreamRecordsJob.To:type_name -> google.protobuf.Timestamp
+	12, // 20: FTaxesGrpc.QueryResponse.Records:type_name -> FTaxesGrpc.Record
+	8,  // 21: FTaxesGrpc.UpdateTradeRequest.Trade:type_name -> FTaxesGrpc.Trade
+	31, // 22: FTaxesGrpc.UpdateTradeRequest.UpdateMask:type_name -> google.protobuf.FieldMask
+	9,  // 23: FTaxesGrpc.UpdateTransferRequest.Transfer:type_name -> FTaxesGrpc.Transfer
+	31, // 24: FTaxesGrpc.UpdateTransferRequest.UpdateMask:type_name -> google.protobuf.FieldMask
+	32, // 25: FTaxesGrpc.PluginConfig.Values:type_name -> google.protobuf.Struct
+	5,  // 26: FTaxesGrpc.EventFilter.Types:type_name -> FTaxesGrpc.EventType
+	5,  // 27: FTaxesGrpc.Event.Type:type_name -> FTaxesGrpc.EventType
+	30, // 28: FTaxesGrpc.Event.Ts:type_name -> google.protobuf.Timestamp
+	4,  // 29: FTaxesGrpc.AppLogMsg.Level:type_name -> FTaxesGrpc.LogLevel
+	30, // 30: FTaxesGrpc.TxUpdate.Since:type_name -> google.protobuf.Timestamp
+	8,  // 31: FTaxesGrpc.TradeConversionJob.Trade:type_name -> FTaxesGrpc.Trade
+	9,  // 32: FTaxesGrpc.TransferConversionJob.Transfer:type_name -> FTaxesGrpc.Transfer
+	8,  // 33: FTaxesGrpc.FTaxes.SubmitTrade:input_type -> FTaxesGrpc.Trade
+	9,  // 34: FTaxesGrpc.FTaxes.SubmitTransfer:input_type -> FTaxesGrpc.Transfer
+	17, // 35: FTaxesGrpc.FTaxes.UpdateTrade:input_type -> FTaxesGrpc.UpdateTradeRequest
+	18, // 36: FTaxesGrpc.FTaxes.UpdateTransfer:input_type -> FTaxesGrpc.UpdateTransferRequest
+	10, // 37: FTaxesGrpc.FTaxes.SubmitGenericFee:input_type -> FTaxesGrpc.SrcGenericFee
+	11, // 38: FTaxesGrpc.FTaxes.ShowJobProgress:input_type -> FTaxesGrpc.JobProgress
+	33, // 39: FTaxesGrpc.FTaxes.GetSettings:input_type -> google.protobuf.Empty
+	23, // 40: FTaxesGrpc.FTaxes.AppLog:input_type -> FTaxesGrpc.AppLogMsg
+	13, // 41: FTaxesGrpc.FTaxes.StreamRecords:input_type -> FTaxesGrpc.StreamRecordsJob
+	15, // 42: FTaxesGrpc.FTaxes.QueryRecords:input_type -> FTaxesGrpc.QueryRequest
+	27, // 43: FTaxesGrpc.FTaxes.PluginHeartbeat:input_type -> FTaxesGrpc.PluginInfo
+	19, // 44: FTaxesGrpc.FTaxes.GetPluginConfig:input_type -> FTaxesGrpc.PluginConfigRequest
+	20, // 45: FTaxesGrpc.FTaxes.SetPluginConfig:input_type -> FTaxesGrpc.PluginConfig
+	21, // 46: FTaxesGrpc.FTaxes.Subscribe:input_type -> FTaxesGrpc.EventFilter
+	25, // 47: FTaxesGrpc.PluginCtl.ConvertPricesInTrade:input_type -> FTaxesGrpc.TradeConversionJob
+	26, // 48: FTaxesGrpc.PluginCtl.ConvertPricesInTransfer:input_type -> FTaxesGrpc.TransferConversionJob
+	28, // 49: FTaxesGrpc.PluginCtl.GenerateReport:input_type -> FTaxesGrpc.ReportRequest
+	33, // 50: FTaxesGrpc.FTaxes.SubmitTrade:output_type -> google.protobuf.Empty
+	33, // 51: FTaxesGrpc.FTaxes.SubmitTransfer:output_type -> google.protobuf.Empty
+	8,  // 52: FTaxesGrpc.FTaxes.UpdateTrade:output_type -> FTaxesGrpc.Trade
+	9,  // 53: FTaxesGrpc.FTaxes.UpdateTransfer:output_type -> FTaxesGrpc.Transfer
+	33, // 54: FTaxesGrpc.FTaxes.SubmitGenericFee:output_type -> google.protobuf.Empty
+	33, // 55: FTaxesGrpc.FTaxes.ShowJobProgress:output_type -> google.protobuf.Empty
+	14, // 56: FTaxesGrpc.FTaxes.GetSettings:output_type -> FTaxesGrpc.Settings
+	33, // 57: FTaxesGrpc.FTaxes.AppLog:output_type -> google.protobuf.Empty
+	12, // 58: FTaxesGrpc.FTaxes.StreamRecords:output_type -> FTaxesGrpc.Record
+	16, // 59: FTaxesGrpc.FTaxes.QueryRecords:output_type -> FTaxesGrpc.QueryResponse
+	33, // 60: FTaxesGrpc.FTaxes.PluginHeartbeat:output_type -> google.protobuf.Empty
+	20, // 61: FTaxesGrpc.FTaxes.GetPluginConfig:output_type -> FTaxesGrpc.PluginConfig
+	33, // 62: FTaxesGrpc.FTaxes.SetPluginConfig:output_type -> google.protobuf.Empty
+	22, // 63: FTaxesGrpc.FTaxes.Subscribe:output_type -> FTaxesGrpc.Event
+	8,  // 64: FTaxesGrpc.PluginCtl.ConvertPricesInTrade:output_type -> FTaxesGrpc.Trade
+	9,  // 65: FTaxesGrpc.PluginCtl.ConvertPricesInTransfer:output_type -> FTaxesGrpc.Transfer
+	29, // 66: FTaxesGrpc.PluginCtl.GenerateReport:output_type -> FTaxesGrpc.ReportArtifact
+	50, // [50:67] is the sub-list for method output_type
+	33, // [33:50] is the sub-list for method input_type
+	33, // [33:33] is the sub-list for extension type_name
+	33, // [33:33] is the sub-list for extension extendee
+	0,  // [0:33] is the sub-list for field type_name
 }
 
 func init() { file_f_taxes_proto_init() }
@@ -2041,7 +3096,7 @@ func file_f_taxes_proto_init() {
 			}
 		}
 		file_f_taxes_proto_msgTypes[9].Exporter = func(v any, i int) any {
-			switch v := v.(*AppLogMsg); i {
+			switch v := v.(*QueryRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2053,7 +3108,7 @@ func file_f_taxes_proto_init() {
 			}
 		}
 		file_f_taxes_proto_msgTypes[10].Exporter = func(v any, i int) any {
-			switch v := v.(*TxUpdate); i {
+			switch v := v.(*QueryResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2065,7 +3120,7 @@ func file_f_taxes_proto_init() {
 			}
 		}
 		file_f_taxes_proto_msgTypes[11].Exporter = func(v any, i int) any {
-			switch v := v.(*TradeConversionJob); i {
+			switch v := v.(*UpdateTradeRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2077,7 +3132,7 @@ func file_f_taxes_proto_init() {
 			}
 		}
 		file_f_taxes_proto_msgTypes[12].Exporter = func(v any, i int) any {
-			switch v := v.(*TransferConversionJob); i {
+			switch v := v.(*UpdateTransferRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2089,6 +3144,102 @@ func file_f_taxes_proto_init() {
 			}
 		}
 		file_f_taxes_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*PluginConfigRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_f_taxes_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*PluginConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_f_taxes_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*EventFilter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_f_taxes_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*Event); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_f_taxes_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*AppLogMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_f_taxes_proto_msgTypes[18].Exporter = func(v any, i int) any {
+			switch v := v.(*TxUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_f_taxes_proto_msgTypes[19].Exporter = func(v any, i int) any {
+			switch v := v.(*TradeConversionJob); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_f_taxes_proto_msgTypes[20].Exporter = func(v any, i int) any {
+			switch v := v.(*TransferConversionJob); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_f_taxes_proto_msgTypes[21].Exporter = func(v any, i int) any {
 			switch v := v.(*PluginInfo); i {
 			case 0:
 				return &v.state
@@ -2100,14 +3251,38 @@ func file_f_taxes_proto_init() {
 				return nil
 			}
 		}
+		file_f_taxes_proto_msgTypes[22].Exporter = func(v any, i int) any {
+			switch v := v.(*ReportRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_f_taxes_proto_msgTypes[23].Exporter = func(v any, i int) any {
+			switch v := v.(*ReportArtifact); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_f_taxes_proto_rawDesc,
-			NumEnums:      5,
-			NumMessages:   14,
+			NumEnums:      6,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
@@ -22,26 +22,44 @@ const _ = grpc.SupportPackageIsVersion8
 const (
 	FTaxes_SubmitTrade_FullMethodName      = "/FTaxesGrpc.FTaxes/SubmitTrade"
 	FTaxes_SubmitTransfer_FullMethodName   = "/FTaxesGrpc.FTaxes/SubmitTransfer"
+	FTaxes_UpdateTrade_FullMethodName      = "/FTaxesGrpc.FTaxes/UpdateTrade"
+	FTaxes_UpdateTransfer_FullMethodName   = "/FTaxesGrpc.FTaxes/UpdateTransfer"
 	FTaxes_SubmitGenericFee_FullMethodName = "/FTaxesGrpc.FTaxes/SubmitGenericFee"
 	FTaxes_ShowJobProgress_FullMethodName  = "/FTaxesGrpc.FTaxes/ShowJobProgress"
 	FTaxes_GetSettings_FullMethodName      = "/FTaxesGrpc.FTaxes/GetSettings"
 	FTaxes_AppLog_FullMethodName           = "/FTaxesGrpc.FTaxes/AppLog"
 	FTaxes_StreamRecords_FullMethodName    = "/FTaxesGrpc.FTaxes/StreamRecords"
+	FTaxes_QueryRecords_FullMethodName     = "/FTaxesGrpc.FTaxes/QueryRecords"
 	FTaxes_PluginHeartbeat_FullMethodName  = "/FTaxesGrpc.FTaxes/PluginHeartbeat"
+	FTaxes_GetPluginConfig_FullMethodName  = "/FTaxesGrpc.FTaxes/GetPluginConfig"
+	FTaxes_SetPluginConfig_FullMethodName  = "/FTaxesGrpc.FTaxes/SetPluginConfig"
+	FTaxes_Subscribe_FullMethodName        = "/FTaxesGrpc.FTaxes/Subscribe"
 )
 
 // FTaxesClient is the client API for FTaxes service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Service offered by F-Taxes to plugins.
+// Every call must carry the metadata "x-plugin-id" and "x-plugin-token" with the values F-Taxes passes to the plugin
+// in the environment variables FTAXES_PLUGIN_ID and FTAXES_PLUGIN_TOKEN when starting it.
 type FTaxesClient interface {
 	SubmitTrade(ctx context.Context, in *Trade, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	SubmitTransfer(ctx context.Context, in *Transfer, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UpdateTrade(ctx context.Context, in *UpdateTradeRequest, opts ...grpc.CallOption) (*Trade, error)
+	UpdateTransfer(ctx context.Context, in *UpdateTransferRequest, opts ...grpc.CallOption) (*Transfer, error)
 	SubmitGenericFee(ctx context.Context, in *SrcGenericFee, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	ShowJobProgress(ctx context.Context, in *JobProgress, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	GetSettings(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Settings, error)
 	AppLog(ctx context.Context, in *AppLogMsg, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	StreamRecords(ctx context.Context, in *StreamRecordsJob, opts ...grpc.CallOption) (FTaxes_StreamRecordsClient, error)
+	QueryRecords(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error)
 	PluginHeartbeat(ctx context.Context, in *PluginInfo, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetPluginConfig(ctx context.Context, in *PluginConfigRequest, opts ...grpc.CallOption) (*PluginConfig, error)
+	SetPluginConfig(ctx context.Context, in *PluginConfig, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Streams events until the plugin cancels the call. Record events require the permission "records:read",
+	// settings events "settings:read". The stream ends with RESOURCE_EXHAUSTED if the plugin doesn't keep up.
+	Subscribe(ctx context.Context, in *EventFilter, opts ...grpc.CallOption) (FTaxes_SubscribeClient, error)
 }
 
 type fTaxesClient struct {
@@ -72,6 +90,26 @@ func (c *fTaxesClient) SubmitTransfer(ctx context.Context, in *Transfer, opts ..
 	return out, nil
 }
 
+func (c *fTaxesClient) UpdateTrade(ctx context.Context, in *UpdateTradeRequest, opts ...grpc.CallOption) (*Trade, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Trade)
+	err := c.cc.Invoke(ctx, FTaxes_UpdateTrade_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fTaxesClient) UpdateTransfer(ctx context.Context, in *UpdateTransferRequest, opts ...grpc.CallOption) (*Transfer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Transfer)
+	err := c.cc.Invoke(ctx, FTaxes_UpdateTransfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *fTaxesClient) SubmitGenericFee(ctx context.Context, in *SrcGenericFee, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(emptypb.Empty)
@@ -145,6 +183,16 @@ func (x *fTaxesStreamRecordsClient) Recv() (*Record, error) {
 	return m, nil
 }
 
+func (c *fTaxesClient) QueryRecords(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryResponse)
+	err := c.cc.Invoke(ctx, FTaxes_QueryRecords_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *fTaxesClient) PluginHeartbeat(ctx context.Context, in *PluginInfo, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(emptypb.Empty)
@@ -155,18 +203,83 @@ func (c *fTaxesClient) PluginHeartbeat(ctx context.Context, in *PluginInfo, opts
 	return out, nil
 }
 
+func (c *fTaxesClient) GetPluginConfig(ctx context.Context, in *PluginConfigRequest, opts ...grpc.CallOption) (*PluginConfig, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PluginConfig)
+	err := c.cc.Invoke(ctx, FTaxes_GetPluginConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fTaxesClient) SetPluginConfig(ctx context.Context, in *PluginConfig, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FTaxes_SetPluginConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fTaxesClient) Subscribe(ctx context.Context, in *EventFilter, opts ...grpc.CallOption) (FTaxes_SubscribeClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FTaxes_ServiceDesc.Streams[1], FTaxes_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fTaxesSubscribeClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FTaxes_SubscribeClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type fTaxesSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *fTaxesSubscribeClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // FTaxesServer is the server API for FTaxes service.
 // All implementations must embed UnimplementedFTaxesServer
 // for forward compatibility
+//
+// Service offered by F-Taxes to plugins.
+// Every call must carry the metadata "x-plugin-id" and "x-plugin-token" with the values F-Taxes passes to the plugin
+// in the environment variables FTAXES_PLUGIN_ID and FTAXES_PLUGIN_TOKEN when starting it.
 type FTaxesServer interface {
 	SubmitTrade(context.Context, *Trade) (*emptypb.Empty, error)
 	SubmitTransfer(context.Context, *Transfer) (*emptypb.Empty, error)
+	UpdateTrade(context.Context, *UpdateTradeRequest) (*Trade, error)
+	UpdateTransfer(context.Context, *UpdateTransferRequest) (*Transfer, error)
 	SubmitGenericFee(context.Context, *SrcGenericFee) (*emptypb.Empty, error)
 	ShowJobProgress(context.Context, *JobProgress) (*emptypb.Empty, error)
 	GetSettings(context.Context, *emptypb.Empty) (*Settings, error)
 	AppLog(context.Context, *AppLogMsg) (*emptypb.Empty, error)
 	StreamRecords(*StreamRecordsJob, FTaxes_StreamRecordsServer) error
+	QueryRecords(context.Context, *QueryRequest) (*QueryResponse, error)
 	PluginHeartbeat(context.Context, *PluginInfo) (*emptypb.Empty, error)
+	GetPluginConfig(context.Context, *PluginConfigRequest) (*PluginConfig, error)
+	SetPluginConfig(context.Context, *PluginConfig) (*emptypb.Empty, error)
+	// Streams events until the plugin cancels the call. Record events require the permission "records:read",
+	// settings events "settings:read". The stream ends with RESOURCE_EXHAUSTED if the plugin doesn't keep up.
+	Subscribe(*EventFilter, FTaxes_SubscribeServer) error
 	mustEmbedUnimplementedFTaxesServer()
 }
 
@@ -180,6 +293,12 @@ func (UnimplementedFTaxesServer) SubmitTrade(context.Context, *Trade) (*emptypb.
 func (UnimplementedFTaxesServer) SubmitTransfer(context.Context, *Transfer) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SubmitTransfer not implemented")
 }
+func (UnimplementedFTaxesServer) UpdateTrade(context.Context, *UpdateTradeRequest) (*Trade, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTrade not implemented")
+}
+func (UnimplementedFTaxesServer) UpdateTransfer(context.Context, *UpdateTransferRequest) (*Transfer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTransfer not implemented")
+}
 func (UnimplementedFTaxesServer) SubmitGenericFee(context.Context, *SrcGenericFee) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SubmitGenericFee not implemented")
 }
@@ -195,9 +314,21 @@ func (UnimplementedFTaxesServer) AppLog(context.Context, *AppLogMsg) (*emptypb.E
 func (UnimplementedFTaxesServer) StreamRecords(*StreamRecordsJob, FTaxes_StreamRecordsServer) error {
 	return status.Errorf(codes.Unimplemented, "method StreamRecords not implemented")
 }
+func (UnimplementedFTaxesServer) QueryRecords(context.Context, *QueryRequest) (*QueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryRecords not implemented")
+}
 func (UnimplementedFTaxesServer) PluginHeartbeat(context.Context, *PluginInfo) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PluginHeartbeat not implemented")
 }
+func (UnimplementedFTaxesServer) GetPluginConfig(context.Context, *PluginConfigRequest) (*PluginConfig, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPluginConfig not implemented")
+}
+func (UnimplementedFTaxesServer) SetPluginConfig(context.Context, *PluginConfig) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPluginConfig not implemented")
+}
+func (UnimplementedFTaxesServer) Subscribe(*EventFilter, FTaxes_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
 func (UnimplementedFTaxesServer) mustEmbedUnimplementedFTaxesServer() {}
 
 // UnsafeFTaxesServer may be embedded to opt out of forward compatibility for this service.
@@ -247,6 +378,42 @@ func _FTaxes_SubmitTransfer_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FTaxes_UpdateTrade_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTradeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FTaxesServer).UpdateTrade(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FTaxes_UpdateTrade_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FTaxesServer).UpdateTrade(ctx, req.(*UpdateTradeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FTaxes_UpdateTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FTaxesServer).UpdateTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FTaxes_UpdateTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FTaxesServer).UpdateTransfer(ctx, req.(*UpdateTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _FTaxes_SubmitGenericFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SrcGenericFee)
 	if err := dec(in); err != nil {
@@ -340,6 +507,24 @@ func (x *fTaxesStreamRecordsServer) Send(m *Record) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _FTaxes_QueryRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FTaxesServer).QueryRecords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FTaxes_QueryRecords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FTaxesServer).QueryRecords(ctx, req.(*QueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _FTaxes_PluginHeartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PluginInfo)
 	if err := dec(in); err != nil {
@@ -358,6 +543,63 @@ func _FTaxes_PluginHeartbeat_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FTaxes_GetPluginConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FTaxesServer).GetPluginConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FTaxes_GetPluginConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FTaxesServer).GetPluginConfig(ctx, req.(*PluginConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FTaxes_SetPluginConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginConfig)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FTaxesServer).SetPluginConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FTaxes_SetPluginConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FTaxesServer).SetPluginConfig(ctx, req.(*PluginConfig))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FTaxes_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EventFilter)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FTaxesServer).Subscribe(m, &fTaxesSubscribeServer{ServerStream: stream})
+}
+
+type FTaxes_SubscribeServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type fTaxesSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *fTaxesSubscribeServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // FTaxes_ServiceDesc is the grpc.ServiceDesc for FTaxes service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -373,6 +615,14 @@ var FTaxes_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SubmitTransfer",
 			Handler:    _FTaxes_SubmitTransfer_Handler,
 		},
+		{
+			MethodName: "UpdateTrade",
+			Handler:    _FTaxes_UpdateTrade_Handler,
+		},
+		{
+			MethodName: "UpdateTransfer",
+			Handler:    _FTaxes_UpdateTransfer_Handler,
+		},
 		{
 			MethodName: "SubmitGenericFee",
 			Handler:    _FTaxes_SubmitGenericFee_Handler,
@@ -389,10 +639,22 @@ var FTaxes_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AppLog",
 			Handler:    _FTaxes_AppLog_Handler,
 		},
+		{
+			MethodName: "QueryRecords",
+			Handler:    _FTaxes_QueryRecords_Handler,
+		},
 		{
 			MethodName: "PluginHeartbeat",
 			Handler:    _FTaxes_PluginHeartbeat_Handler,
 		},
+		{
+			MethodName: "GetPluginConfig",
+			Handler:    _FTaxes_GetPluginConfig_Handler,
+		},
+		{
+			MethodName: "SetPluginConfig",
+			Handler:    _FTaxes_SetPluginConfig_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -400,6 +662,11 @@ var FTaxes_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _FTaxes_StreamRecords_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Subscribe",
+			Handler:       _FTaxes_Subscribe_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "f-taxes.proto",
 }
@@ -407,6 +674,7 @@ var FTaxes_ServiceDesc = grpc.ServiceDesc{
 const (
 	PluginCtl_ConvertPricesInTrade_FullMethodName    = "/FTaxesGrpc.PluginCtl/ConvertPricesInTrade"
 	PluginCtl_ConvertPricesInTransfer_FullMethodName = "/FTaxesGrpc.PluginCtl/ConvertPricesInTransfer"
+	PluginCtl_GenerateReport_FullMethodName          = "/FTaxesGrpc.PluginCtl/GenerateReport"
 )
 
 // PluginCtlClient is the client API for PluginCtl service.
@@ -415,6 +683,8 @@ const (
 type PluginCtlClient interface {
 	ConvertPricesInTrade(ctx context.Context, in *TradeConversionJob, opts ...grpc.CallOption) (*Trade, error)
 	ConvertPricesInTransfer(ctx context.Context, in *TransferConversionJob, opts ...grpc.CallOption) (*Transfer, error)
+	// Generates a report. Plugins read the records they need via FTaxes.StreamRecords or FTaxes.QueryRecords.
+	GenerateReport(ctx context.Context, in *ReportRequest, opts ...grpc.CallOption) (PluginCtl_GenerateReportClient, error)
 }
 
 type pluginCtlClient struct {
@@ -445,12 +715,47 @@ func (c *pluginCtlClient) ConvertPricesInTransfer(ctx context.Context, in *Trans
 	return out, nil
 }
 
+func (c *pluginCtlClient) GenerateReport(ctx context.Context, in *ReportRequest, opts ...grpc.CallOption) (PluginCtl_GenerateReportClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PluginCtl_ServiceDesc.Streams[0], PluginCtl_GenerateReport_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &pluginCtlGenerateReportClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PluginCtl_GenerateReportClient interface {
+	Recv() (*ReportArtifact, error)
+	grpc.ClientStream
+}
+
+type pluginCtlGenerateReportClient struct {
+	grpc.ClientStream
+}
+
+func (x *pluginCtlGenerateReportClient) Recv() (*ReportArtifact, error) {
+	m := new(ReportArtifact)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // PluginCtlServer is the server API for PluginCtl service.
 // All implementations must embed UnimplementedPluginCtlServer
 // for forward compatibility
 type PluginCtlServer interface {
 	ConvertPricesInTrade(context.Context, *TradeConversionJob) (*Trade, error)
 	ConvertPricesInTransfer(context.Context, *TransferConversionJob) (*Transfer, error)
+	// Generates a report. Plugins read the records they need via FTaxes.StreamRecords or FTaxes.QueryRecords.
+	GenerateReport(*ReportRequest, PluginCtl_GenerateReportServer) error
 	mustEmbedUnimplementedPluginCtlServer()
 }
 
@@ -464,6 +769,9 @@ func (UnimplementedPluginCtlServer) ConvertPricesInTrade(context.Context, *Trade
 func (UnimplementedPluginCtlServer) ConvertPricesInTransfer(context.Context, *TransferConversionJob) (*Transfer, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ConvertPricesInTransfer not implemented")
 }
+func (UnimplementedPluginCtlServer) GenerateReport(*ReportRequest, PluginCtl_GenerateReportServer) error {
+	return status.Errorf(codes.Unimplemented, "method GenerateReport not implemented")
+}
 func (UnimplementedPluginCtlServer) mustEmbedUnimplementedPluginCtlServer() {}
 
 // UnsafePluginCtlServer may be embedded to opt out of forward compatibility for this service.
@@ -513,6 +821,27 @@ func _PluginCtl_ConvertPricesInTransfer_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PluginCtl_GenerateReport_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PluginCtlServer).GenerateReport(m, &pluginCtlGenerateReportServer{ServerStream: stream})
+}
+
+type PluginCtl_GenerateReportServer interface {
+	Send(*ReportArtifact) error
+	grpc.ServerStream
+}
+
+type pluginCtlGenerateReportServer struct {
+	grpc.ServerStream
+}
+
+func (x *pluginCtlGenerateReportServer) Send(m *ReportArtifact) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // PluginCtl_ServiceDesc is the grpc.ServiceDesc for PluginCtl service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -529,6 +858,12 @@ var PluginCtl_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _PluginCtl_ConvertPricesInTransfer_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GenerateReport",
+			Handler:       _PluginCtl_GenerateReport_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "f-taxes.proto",
 }